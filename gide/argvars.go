@@ -154,9 +154,20 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjPrefs, tv *giv.TextView) {
 		av["{SelStartCol}"] = fmt.Sprintf("%v", tv.SelectReg.Start.Ch)
 		av["{SelEndLine}"] = fmt.Sprintf("%v", tv.SelectReg.End.Ln)  // check for no sel
 		av["{SelEndCol}"] = fmt.Sprintf("%v", tv.SelectReg.Start.Ch) // check for no sel
-		av["{CurSel}"] = ""                                          // todo get sel
-		av["{CurLineText}"] = ""                                     // todo get cur line
-		av["{CurWord}"] = ""                                         // todo get word
+		av["{CurSel}"] = ""
+		av["{CurLineText}"] = ""
+		av["{CurWord}"] = ""
+		if tv.Buf != nil && tv.Buf.IsValidLine(tv.CursorPos.Ln) {
+			if sel := tv.Selection(); sel != nil {
+				av["{CurSel}"] = string(sel.ToBytes())
+			}
+			av["{CurLineText}"] = string(tv.Buf.Line(tv.CursorPos.Ln))
+			if wr := tv.WordAt(); wr.Start.IsLess(wr.End) {
+				if wd := tv.Buf.Region(wr.Start, wr.End); wd != nil {
+					av["{CurWord}"] = strings.TrimSpace(string(wd.ToBytes()))
+				}
+			}
+		}
 	} else {
 		av["{CurLine}"] = ""
 		av["{CurCol}"] = ""
@@ -0,0 +1,403 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/lex"
+)
+
+// ProjRelPath returns given file path relative to the root of the project,
+// or the path itself if it is not within it
+func ProjRelPath(pp *ProjPrefs, fpath string) string {
+	if !filepath.IsAbs(fpath) || pp.ProjRoot == "" {
+		return fpath
+	}
+	if rel, err := filepath.Rel(string(pp.ProjRoot), fpath); err == nil {
+		return rel
+	}
+	return fpath
+}
+
+// ProjAbsPath returns the full path of given file path relative to the root
+// of the project, as returned by ProjRelPath
+func ProjAbsPath(pp *ProjPrefs, rel string) string {
+	if filepath.IsAbs(rel) || pp.ProjRoot == "" {
+		return rel
+	}
+	return filepath.Join(string(pp.ProjRoot), rel)
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  NavHistory
+
+// NavPos is a position in a file in the navigation history
+type NavPos struct {
+	File string  `desc:"file name, relative to the project root"`
+	Pos  lex.Pos `desc:"position in the file"`
+}
+
+// Near returns true if given position is on the same line of the same file
+func (np NavPos) Near(op NavPos) bool {
+	return np.File == op.File && np.Pos.Ln == op.Pos.Ln
+}
+
+// NavHistMax is the maximum number of positions kept in the navigation history
+var NavHistMax = 100
+
+// NavHistory is the history of positions that were jumped from (e.g., by
+// following links, looking up symbols, or showing debugger frames), which
+// can be moved back and forward through
+type NavHistory struct {
+	Items []NavPos `desc:"positions in the history, oldest first"`
+	Idx   int      `desc:"index of the current position in Items, which is Items' length if at the end"`
+}
+
+// Push adds given position, jumped from, after the current one in the
+// history, replacing any positions after it, unless it is at the same place
+// as the last position
+func (nh *NavHistory) Push(np NavPos) {
+	if nh.Idx < len(nh.Items) {
+		nh.Items = nh.Items[:nh.Idx+1]
+	}
+	if n := len(nh.Items); n > 0 && nh.Items[n-1].Near(np) {
+		nh.Items[n-1] = np
+	} else {
+		nh.Items = append(nh.Items, np)
+	}
+	if n := len(nh.Items); n > NavHistMax {
+		nh.Items = nh.Items[n-NavHistMax:]
+	}
+	nh.Idx = len(nh.Items)
+}
+
+// Back returns the position before the current one in the history, given
+// the current position, which is added to the end of the history when
+// moving back from there so that Forward returns to it
+func (nh *NavHistory) Back(cur NavPos) (NavPos, bool) {
+	if nh.Idx > len(nh.Items) {
+		nh.Idx = len(nh.Items)
+	}
+	if nh.Idx == len(nh.Items) {
+		nh.Push(cur)
+		nh.Idx = len(nh.Items) - 1
+	}
+	for nh.Idx > 0 {
+		nh.Idx--
+		if !nh.Items[nh.Idx].Near(cur) {
+			return nh.Items[nh.Idx], true
+		}
+	}
+	return NavPos{}, false
+}
+
+// Forward returns the position after the current one in the history, if
+// the history has been moved back
+func (nh *NavHistory) Forward() (NavPos, bool) {
+	if nh.Idx+1 >= len(nh.Items) {
+		return NavPos{}, false
+	}
+	nh.Idx++
+	return nh.Items[nh.Idx], true
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Bookmarks
+
+// Bookmark is a numbered line in a file, with an optional name
+type Bookmark struct {
+	Num  int    `inactive:"+" width:"4" desc:"number of the bookmark, shown in the line numbers"`
+	Name string `desc:"optional name of the bookmark"`
+	File string `inactive:"+" desc:"file name, relative to the project root"`
+	Line int    `inactive:"+" desc:"line within file (starting at 1)"`
+}
+
+// Label returns the label of the bookmark for menus
+func (bm *Bookmark) Label() string {
+	lbl := fmt.Sprintf("%d", bm.Num)
+	if bm.Name != "" {
+		lbl += " " + bm.Name
+	}
+	return fmt.Sprintf("%s: %s:%d", lbl, bm.File, bm.Line)
+}
+
+// Bookmarks is a list of bookmarks, sorted by file and line
+type Bookmarks []*Bookmark
+
+// Find returns the index of the bookmark at given line (starting at 1) of
+// given file, or -1 if none
+func (bs Bookmarks) Find(file string, line int) int {
+	for i, bm := range bs {
+		if bm.File == file && bm.Line == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// Add adds a bookmark at given line (starting at 1) of given file, with
+// given name and the lowest number not already used
+func (bs *Bookmarks) Add(file string, line int, name string) *Bookmark {
+	used := make(map[int]bool, len(*bs))
+	for _, bm := range *bs {
+		used[bm.Num] = true
+	}
+	num := 1
+	for used[num] {
+		num++
+	}
+	bm := &Bookmark{Num: num, Name: name, File: file, Line: line}
+	*bs = append(*bs, bm)
+	bs.Sort()
+	return bm
+}
+
+// Delete deletes the bookmark at given index
+func (bs *Bookmarks) Delete(idx int) {
+	if idx < 0 || idx >= len(*bs) {
+		return
+	}
+	*bs = append((*bs)[:idx], (*bs)[idx+1:]...)
+}
+
+// Sort sorts the bookmarks by file and line
+func (bs Bookmarks) Sort() {
+	sort.SliceStable(bs, func(i, j int) bool {
+		if bs[i].File != bs[j].File {
+			return bs[i].File < bs[j].File
+		}
+		return bs[i].Line < bs[j].Line
+	})
+}
+
+// Next returns the index of the bookmark after (dir > 0) or before given
+// line (starting at 1) of given file, in order of file and line, wrapping
+// around at the ends, or -1 if there are no bookmarks
+func (bs Bookmarks) Next(file string, line int, dir int) int {
+	n := len(bs)
+	if n == 0 {
+		return -1
+	}
+	after := func(bm *Bookmark) bool {
+		return bm.File > file || (bm.File == file && bm.Line > line)
+	}
+	if dir > 0 {
+		for i, bm := range bs {
+			if after(bm) {
+				return i
+			}
+		}
+		return 0
+	}
+	for i := n - 1; i >= 0; i-- {
+		bm := bs[i]
+		if !after(bm) && !(bm.File == file && bm.Line == line) {
+			return i
+		}
+	}
+	return n - 1
+}
+
+// AdjustEdit adjusts the lines of the bookmarks in given file for an edit
+// starting at given position, which inserts dl lines, or deletes -dl lines
+// if negative, returning true if any changed.  Bookmarks move with the text
+// of their lines, so inserting at the start of a line moves its bookmark.
+func (bs Bookmarks) AdjustEdit(file string, st lex.Pos, dl int) bool {
+	if dl == 0 {
+		return false
+	}
+	chg := false
+	for _, bm := range bs {
+		bl := bm.Line - 1
+		if bm.File != file || bl < st.Ln || (bl == st.Ln && (dl < 0 || st.Ch > 0)) {
+			continue
+		}
+		bl += dl
+		if bl < st.Ln {
+			bl = st.Ln
+		}
+		bm.Line = bl + 1
+		chg = true
+	}
+	return chg
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  TextView bookmarks
+
+// BookmarkColor is the color of the bookmark markers in the line numbers
+var BookmarkColor = "#4080E0"
+
+// FileBookmarks returns the bookmarks of the file of the buffer, by line
+// (starting at 0), from the project prefs
+func (tv *TextView) FileBookmarks() map[int]*Bookmark {
+	if tv.Buf == nil || tv.Buf.Filename == "" {
+		return nil
+	}
+	ge, ok := ParentGide(tv)
+	if !ok {
+		return nil
+	}
+	pp := ge.ProjPrefs()
+	fn := ProjRelPath(pp, string(tv.Buf.Filename))
+	var bms map[int]*Bookmark
+	for _, bm := range pp.Bookmarks {
+		if bm.File != fn {
+			continue
+		}
+		if bms == nil {
+			bms = make(map[int]*Bookmark)
+		}
+		bms[bm.Line-1] = bm
+	}
+	return bms
+}
+
+// RenderBookmarks renders the numbers of the bookmarks in the file in the
+// line numbers, after the fold markers
+func (tv *TextView) RenderBookmarks() {
+	if !tv.HasLineNos() || !tv.IsVisible() || tv.NLines == 0 || len(tv.Offs) != tv.NLines {
+		return
+	}
+	bms := tv.FileBookmarks()
+	if len(bms) == 0 {
+		return
+	}
+	rs := tv.Render()
+	rs.PushBounds(tv.VpBBox)
+	rs.Lock()
+	pc := &rs.Paint
+	spos := tv.RenderStartPos()
+	mx, ch := tv.foldMarkX()
+	x := float32(tv.VpBBox.Min.X) + mx + 1.1*ch
+	var clr gist.Color
+	clr.SetString(BookmarkColor, nil)
+	fnt := tv.Sty.Font
+	fnt.Color.SetString("white", nil)
+	var tr girl.Text
+	for ln, bm := range bms {
+		if ln >= tv.NLines || tv.IsHidden(ln) {
+			continue
+		}
+		y := spos.Y + tv.Offs[ln]
+		if int(mat32.Ceil(y+tv.LineHeight)) < tv.VpBBox.Min.Y || int(mat32.Floor(y)) > tv.VpBBox.Max.Y {
+			continue
+		}
+		pc.FillBoxColor(rs, mat32.Vec2{x, y}, mat32.Vec2{1.2 * ch, tv.LineHeight}, clr)
+		lbl := fmt.Sprintf("%d", bm.Num)
+		if bm.Num > 9 {
+			lbl = "+"
+		}
+		tr.SetString(lbl, &fnt, &tv.Sty.UnContext, &tv.Sty.Text, true, 0, 1)
+		tr.Render(rs, mat32.Vec2{x + 0.1*ch, y})
+	}
+	rs.Unlock()
+	rs.PopBounds()
+}
+
+// BookmarksBufSig moves the bookmarks in the file of given buffer along
+// with the lines inserted or deleted for given signal from it -- it must be
+// connected once per buffer, as it is in the views of a project
+func BookmarksBufSig(ge Gide, tb *giv.TextBuf, sig giv.TextBufSignals, data interface{}) {
+	if sig != giv.TextBufInsert && sig != giv.TextBufDelete {
+		return
+	}
+	tbe, ok := data.(*textbuf.Edit)
+	if !ok || tb.Filename == "" {
+		return
+	}
+	dl := tbe.Reg.End.Ln - tbe.Reg.Start.Ln
+	if dl == 0 {
+		return
+	}
+	if tbe.Delete {
+		dl = -dl
+	}
+	pp := ge.ProjPrefs()
+	if len(pp.Bookmarks) == 0 {
+		return
+	}
+	pp.Bookmarks.AdjustEdit(ProjRelPath(pp, string(tb.Filename)), tbe.Reg.Start, dl)
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  BookmarksView
+
+// BookmarksView is a view of the bookmarks in the project, which are shown
+// in their files by double-clicking
+type BookmarksView struct {
+	gi.Layout
+	Gide Gide `json:"-" xml:"-" desc:"parent gide project"`
+}
+
+var KiT_BookmarksView = kit.Types.AddType(&BookmarksView{}, BookmarksViewProps)
+
+// Config configures the view for the bookmarks of given project
+func (bv *BookmarksView) Config(ge Gide) {
+	bv.Gide = ge
+	bv.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(giv.KiT_TableView, "bookmarks")
+	mods, updt := bv.ConfigChildren(config)
+	tv := bv.TableView()
+	if mods {
+		tv.SliceViewSig.Connect(bv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			bvv := recv.Embed(KiT_BookmarksView).(*BookmarksView)
+			switch sig {
+			case int64(giv.SliceViewDoubleClicked):
+				bvv.ShowBookmark(data.(int))
+			case int64(giv.SliceViewDeleted):
+				bvv.UpdateTextViews()
+			}
+		})
+	} else {
+		updt = bv.UpdateStart()
+	}
+	tv.SetStretchMax()
+	tv.NoAdd = true
+	tv.SetSlice(&ge.ProjPrefs().Bookmarks)
+	bv.UpdateEnd(updt)
+}
+
+// TableView returns the tableview
+func (bv *BookmarksView) TableView() *giv.TableView {
+	return bv.ChildByName("bookmarks", 0).(*giv.TableView)
+}
+
+// ShowBookmark shows the file of the bookmark at given index, at its line
+func (bv *BookmarksView) ShowBookmark(idx int) {
+	pp := bv.Gide.ProjPrefs()
+	if idx < 0 || idx >= len(pp.Bookmarks) {
+		return
+	}
+	bm := pp.Bookmarks[idx]
+	bv.Gide.ShowFile(ProjAbsPath(pp, bm.File), bm.Line)
+}
+
+// UpdateTextViews re-renders the active text view, to update its bookmarks
+func (bv *BookmarksView) UpdateTextViews() {
+	if tv := bv.Gide.ActiveTextView(); tv != nil && tv.Buf != nil {
+		tv.SetFullReRender()
+		tv.UpdateSig()
+	}
+}
+
+// BookmarksViewProps are style properties for BookmarksView
+var BookmarksViewProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/pi/lex"
+)
+
+func navPos(file string, ln int) NavPos {
+	return NavPos{File: file, Pos: lex.Pos{Ln: ln}}
+}
+
+func TestNavHistory(t *testing.T) {
+	var nh NavHistory
+	nh.Push(navPos("a.go", 1))
+	nh.Push(navPos("b.go", 5))
+	nh.Push(navPos("b.go", 5)) // same place as last: not added twice
+	if len(nh.Items) != 2 || nh.Idx != 2 {
+		t.Fatalf("should have 2 items at end, was: %v", nh)
+	}
+	np, ok := nh.Back(navPos("c.go", 9))
+	if !ok || np != navPos("b.go", 5) {
+		t.Errorf("back should go to b.go:5, was: %v %v", np, ok)
+	}
+	np, ok = nh.Back(np)
+	if !ok || np != navPos("a.go", 1) {
+		t.Errorf("back should go to a.go:1, was: %v %v", np, ok)
+	}
+	if _, ok = nh.Back(np); ok {
+		t.Errorf("back should stop at the start")
+	}
+	nh.Forward()
+	np, ok = nh.Forward()
+	if !ok || np != navPos("c.go", 9) {
+		t.Errorf("forward should return to where back started, was: %v %v", np, ok)
+	}
+	if _, ok = nh.Forward(); ok {
+		t.Errorf("forward should stop at the end")
+	}
+	nh.Back(np)
+	nh.Push(navPos("d.go", 2))
+	if len(nh.Items) != 3 || nh.Items[1] != navPos("b.go", 5) || nh.Items[2] != navPos("d.go", 2) || nh.Idx != 3 {
+		t.Errorf("push after back should replace forward items, was: %v", nh)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	var bs Bookmarks
+	bs.Add("b.go", 10, "")
+	bs.Add("a.go", 20, "top")
+	bs.Add("b.go", 3, "")
+	if len(bs) != 3 || bs[0].File != "a.go" || bs[1].Line != 3 || bs[1].Num != 3 {
+		t.Fatalf("should be sorted by file and line, was: %v", bs)
+	}
+	bs.Delete(bs.Find("a.go", 20))
+	if bm := bs.Add("c.go", 1, ""); bm.Num != 2 {
+		t.Errorf("should reuse lowest free number 2, was: %d", bm.Num)
+	}
+	if idx := bs.Next("b.go", 3, 1); idx < 0 || bs[idx].Line != 10 {
+		t.Errorf("next should be b.go:10, was: %d", idx)
+	}
+	if idx := bs.Next("c.go", 1, 1); idx != 0 {
+		t.Errorf("next should wrap around to the first, was: %d", idx)
+	}
+	if idx := bs.Next("b.go", 10, -1); idx < 0 || bs[idx].Line != 3 {
+		t.Errorf("prev should be b.go:3, was: %d", idx)
+	}
+	if idx := bs.Next("a.go", 1, -1); idx != len(bs)-1 {
+		t.Errorf("prev should wrap around to the last, was: %d", idx)
+	}
+}
+
+func TestBookmarksAdjustEdit(t *testing.T) {
+	bs := Bookmarks{{Num: 1, File: "a.go", Line: 3}, {Num: 2, File: "a.go", Line: 10}, {Num: 3, File: "b.go", Line: 10}}
+	if !bs.AdjustEdit("a.go", lex.Pos{Ln: 4, Ch: 3}, 2) || bs[0].Line != 3 || bs[1].Line != 12 || bs[2].Line != 10 {
+		t.Errorf("insert should move bookmarks after it in the file, was: %v %v %v", bs[0], bs[1], bs[2])
+	}
+	bs.AdjustEdit("a.go", lex.Pos{Ln: 11, Ch: 5}, 1)
+	if bs[1].Line != 12 {
+		t.Errorf("insert within the bookmarked line should not move it, was: %v", bs[1])
+	}
+	bs.AdjustEdit("a.go", lex.Pos{Ln: 11, Ch: 0}, 1)
+	if bs[1].Line != 13 {
+		t.Errorf("insert at the start of the bookmarked line should move it, was: %v", bs[1])
+	}
+	bs.AdjustEdit("a.go", lex.Pos{Ln: 0, Ch: 0}, -6)
+	if bs[0].Line != 1 || bs[1].Line != 7 {
+		t.Errorf("delete should move bookmarks within it to its start, was: %v %v", bs[0], bs[1])
+	}
+}
+
+func TestProjRelPath(t *testing.T) {
+	root := filepath.FromSlash("/proj/root")
+	pp := &ProjPrefs{ProjRoot: gi.FileName(root)}
+	fn := filepath.Join(root, "sub", "a.go")
+	rel := ProjRelPath(pp, fn)
+	if rel != filepath.Join("sub", "a.go") {
+		t.Errorf("should be relative to the root, was: %s", rel)
+	}
+	if abs := ProjAbsPath(pp, rel); abs != fn {
+		t.Errorf("should be back to the full path, was: %s", abs)
+	}
+}
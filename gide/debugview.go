@@ -6,17 +6,25 @@ package gide
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/giv"
+	"github.com/goki/gi/units"
 	"github.com/goki/gide/gidebug"
+	"github.com/goki/gide/gidebug/gidedump"
+	"github.com/goki/gide/gidebug/gidegdb"
 	"github.com/goki/gide/gidebug/gidelve"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
 	"github.com/goki/mat32"
+	"github.com/goki/pi/complete"
 	"github.com/goki/pi/filecat"
 )
 
@@ -43,18 +51,57 @@ const (
 // DebugBreakColors are the colors indicating different breakpoint statuses
 var DebugBreakColors = [DebugBreakStatusN]string{"pink", "red", "orange", "lightblue"}
 
+// DebugTraceUpdate is how often the Trace tab is updated while running
+var DebugTraceUpdate = 250 * time.Millisecond
+
+// DebugVarChangeColors are the background colors indicating how variables
+// have changed since the previous stop -- none for VarSame
+var DebugVarChangeColors = [gidebug.VarChangesN]string{"", "lightgreen", "yellow", "pink"}
+
+// SetVarChangeColor sets the background color of given widget according to
+// given variable change, returning true if it was updated
+func SetVarChangeColor(wi ki.Ki, ch gidebug.VarChanges) bool {
+	clr := ""
+	if ch >= 0 && ch < gidebug.VarChangesN {
+		clr = DebugVarChangeColors[ch]
+	}
+	cur, err := wi.PropTry("background-color")
+	if clr == "" {
+		if err != nil {
+			return false
+		}
+		wi.DeleteProp("background-color")
+		return true
+	}
+	if err == nil && cur == clr {
+		return false
+	}
+	wi.SetProp("background-color", clr)
+	return true
+}
+
 // Debuggers is the list of supported debuggers
 var Debuggers = map[filecat.Supported]func(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (gidebug.GiDebug, error){
 	filecat.Go: func(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (gidebug.GiDebug, error) {
 		return gidelve.NewGiDelve(path, rootPath, outbuf, pars)
 	},
+	filecat.C: func(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (gidebug.GiDebug, error) {
+		return gidegdb.NewGiGdb(path, rootPath, outbuf, pars)
+	},
 }
 
 // NewDebugger returns a new debugger for given supported file type
 func NewDebugger(sup filecat.Supported, path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (gidebug.GiDebug, error) {
+	if pars.Mode == gidebug.Dump {
+		dbg, err := gidedump.NewGiDump(path, rootPath, outbuf, pars)
+		if err != nil {
+			log.Println(err)
+		}
+		return dbg, err
+	}
 	df, ok := Debuggers[sup]
 	if !ok {
-		err := fmt.Errorf("Gi Debug: File type %v not supported -- change the MainLang in File/Project Prefs.. to a supported language (Go only option so far)", sup)
+		err := fmt.Errorf("Gi Debug: File type %v not supported -- change the MainLang in File/Project Prefs.. to a supported language (Go and C / C++ so far)", sup)
 		log.Println(err)
 		return nil, err
 	}
@@ -68,15 +115,24 @@ func NewDebugger(sup filecat.Supported, path, rootPath string, outbuf *giv.TextB
 // DebugView is the debugger
 type DebugView struct {
 	gi.Layout
-	Sup        filecat.Supported `desc:"supported file type to determine debugger"`
-	ExePath    string            `desc:"path to executable / dir to debug"`
-	DbgTime    time.Time         `desc:"time when dbg was last restarted"`
-	Dbg        gidebug.GiDebug   `json:"-" xml:"-" desc:"the debugger"`
-	State      gidebug.AllState  `json:"-" xml:"-" desc:"all relevant debug state info"`
-	CurFileLoc gidebug.Location  `json:"-" xml:"-" desc:"current ShowFile location -- cleared before next one or run"`
-	BBreaks    []*gidebug.Break  `json:"-" xml:"-" desc:"backup breakpoints list -- to track deletes"`
-	OutBuf     *giv.TextBuf      `json:"-" xml:"-" desc:"output from the debugger"`
-	Gide       Gide              `json:"-" xml:"-" desc:"parent gide project"`
+	Sup          filecat.Supported         `desc:"supported file type to determine debugger"`
+	ExePath      string                    `desc:"path to executable / dir to debug"`
+	Launch       string                    `desc:"name of the launch configuration in use -- if empty, the project debug params are used as set by the Debug commands"`
+	ConfigMode   gidebug.Modes             `json:"-" xml:"-" view:"-" desc:"debug mode the view was configured with by the Debug commands, used when there is no launch configuration"`
+	ConfigExe    string                    `json:"-" xml:"-" view:"-" desc:"path to executable / dir the view was configured with by the Debug commands, used when there is no launch configuration"`
+	Params       gidebug.Params            `json:"-" xml:"-" view:"-" desc:"debug params for this session: a copy of the project debug params, with the launch configuration, if any, applied"`
+	DbgTime      time.Time                 `desc:"time when dbg was last restarted"`
+	Dbg          gidebug.GiDebug           `json:"-" xml:"-" desc:"the debugger"`
+	State        gidebug.AllState          `json:"-" xml:"-" desc:"all relevant debug state info"`
+	CurFileLoc   gidebug.Location          `json:"-" xml:"-" desc:"current ShowFile location -- cleared before next one or run"`
+	BBreaks      []*gidebug.Break          `json:"-" xml:"-" desc:"backup breakpoints list -- to track deletes"`
+	OutBuf       *giv.TextBuf              `json:"-" xml:"-" desc:"output from the debugger"`
+	Funcs        []string                  `json:"-" xml:"-" desc:"names of the functions in the process, for completion"`
+	Types        []string                  `json:"-" xml:"-" desc:"names of the types in the process, for completion"`
+	InlineVals   map[string]map[int]string `json:"-" xml:"-" desc:"values of variables shown inline in the editor while stopped, by file path and 0-based line"`
+	GlobalFilter string                    `json:"-" xml:"-" desc:"filter used in the last listing of global vars"`
+	TraceTime    time.Time                 `json:"-" xml:"-" desc:"time when the Trace tab was last updated while running"`
+	Gide         Gide                      `json:"-" xml:"-" desc:"parent gide project"`
 }
 
 var KiT_DebugView = kit.Types.AddType(&DebugView{}, DebugViewProps)
@@ -101,10 +157,22 @@ func (dv *DebugView) DbgIsAvail() bool {
 	return true
 }
 
+// DbgIsCore means the debugger is examining a core dump or a saved dump,
+// post-mortem, so the process cannot be run or stepped.
+func (dv *DebugView) DbgIsCore() bool {
+	return dv.State.Mode == gidebug.Core || dv.State.Mode == gidebug.Dump
+}
+
+// DbgCanRun means the debugger is available for input AND the process
+// can be run -- i.e., it is not a core dump.
+func (dv *DebugView) DbgCanRun() bool {
+	return dv.DbgIsAvail() && !dv.DbgIsCore()
+}
+
 // DbgCanStep means the debugger is started AND process is not currently running,
 // AND it is not already waiting for a next step
 func (dv *DebugView) DbgCanStep() bool {
-	if !dv.DbgIsAvail() {
+	if !dv.DbgCanRun() {
 		return false
 	}
 	if dv.State.State.NextUp {
@@ -121,10 +189,27 @@ func (dv *DebugView) Destroy() {
 	dv.Layout.Destroy()
 }
 
+// SetLaunch sets the launch configuration to use, by name, and restarts the
+// debugger with it -- an empty name debugs the executable and mode the view
+// was configured with, with the project debug params
+func (dv *DebugView) SetLaunch(name string) {
+	if name == dv.Launch {
+		return
+	}
+	dv.Launch = name
+	pp := dv.Gide.ProjPrefs()
+	pp.CurLaunch = name
+	pp.Changed = true
+	if dv.Dbg != nil {
+		dv.Detach()
+	}
+	dv.Start()
+}
+
 // Detach from debugger
 func (dv *DebugView) Detach() {
 	killProc := true
-	if dv.State.Mode == gidebug.Attach {
+	if dv.State.Mode == gidebug.Attach || dv.DbgIsCore() || dv.State.Mode == gidebug.Remote {
 		killProc = false
 	}
 	if dv.DbgIsAvail() {
@@ -134,6 +219,7 @@ func (dv *DebugView) Detach() {
 		dv.Dbg.Detach(killProc)
 	}
 	dv.Dbg = nil
+	dv.ClearInlineVals()
 }
 
 // Start starts the debuger
@@ -142,7 +228,7 @@ func (dv *DebugView) Start() {
 		return
 	}
 	rebuild := false
-	if dv.Dbg != nil && dv.State.Mode != gidebug.Attach {
+	if dv.Dbg != nil && dv.State.Mode != gidebug.Attach && !dv.DbgIsCore() && dv.State.Mode != gidebug.Remote {
 		lmod := dv.Gide.FileTree().LatestFileMod(filecat.Code)
 		rebuild = lmod.After(dv.DbgTime) || dv.Gide.LastSaveTime().After(dv.DbgTime)
 	}
@@ -151,14 +237,26 @@ func (dv *DebugView) Start() {
 		if dv.Dbg != nil {
 			dv.Detach()
 		}
-		rootPath := string(dv.Gide.ProjPrefs().ProjRoot)
-		pars := &dv.Gide.ProjPrefs().Debug
+		pp := dv.Gide.ProjPrefs()
+		rootPath := string(pp.ProjRoot)
+		dv.Params = pp.Debug
+		pars := &dv.Params
+		if lc, _ := pp.Launches.ByName(dv.Launch); lc != nil {
+			lc.SetParams(pars, rootPath)
+			dv.ExePath = lc.ProgramPath(rootPath)
+		} else {
+			pars.Mode = dv.ConfigMode
+			dv.ExePath = dv.ConfigExe
+		}
 		dv.State.Mode = pars.Mode
 		pars.StatFunc = func(stat gidebug.Status) {
 			if stat == gidebug.Ready && dv.State.Mode == gidebug.Attach {
 				dv.UpdateFmState()
 			}
 			dv.SetStatus(stat)
+			if stat == gidebug.Ready && dv.State.Mode == gidebug.Core {
+				dv.UpdateView() // core is already stopped, with all its state
+			}
 			if stat == gidebug.Error {
 				dv.Dbg = nil
 			}
@@ -167,9 +265,16 @@ func (dv *DebugView) Start() {
 		if err == nil {
 			dv.Dbg = dbg
 			dv.DbgTime = time.Now()
+			dv.Funcs = nil
+			dv.Types = nil
+			if dv.State.Mode == gidebug.Remote || dv.State.Mode == gidebug.Dump {
+				dv.UpdateView() // the remote process may already be stopped, and a dump is
+			}
 		} else {
 			dv.SetStatus(gidebug.Error)
 		}
+	} else if dv.DbgIsCore() {
+		dv.UpdateView() // nothing to restart: just reload the state
 	} else {
 		dv.Dbg.Restart()
 		dv.SetStatus(gidebug.Ready)
@@ -188,10 +293,11 @@ func (dv *DebugView) UpdateView() {
 // Continue continues running from current point -- this MUST be called
 // in a separate goroutine!
 func (dv *DebugView) Continue() {
-	if !dv.DbgIsAvail() {
+	if !dv.DbgCanRun() {
 		return
 	}
 	dv.SetBreaks()
+	dv.ClearInlineVals()
 	dv.State.State.Running = true
 	dv.SetStatus(gidebug.Running)
 	dsc := dv.Dbg.Continue(&dv.State)
@@ -200,6 +306,9 @@ func (dv *DebugView) Continue() {
 		if dv.IsDeleted() || dv.IsDestroyed() {
 			return
 		}
+		if ds.CurTrace > 0 && ds.Trace != nil {
+			dv.AddTrace(ds.Trace)
+		}
 	}
 	if dv.Gide != nil {
 		vp := dv.Gide.VPort()
@@ -271,6 +380,9 @@ func (dv *DebugView) SingleStep() {
 
 // Stop stops a running process
 func (dv *DebugView) Stop() {
+	if dv.DbgIsCore() {
+		return
+	}
 	// if !dv.DbgIsActive() || dv.DbgIsAvail() {
 	// 	return
 	// }
@@ -291,6 +403,34 @@ func (dv *DebugView) SetBreaks() {
 	dv.Dbg.UpdateBreaks(&dv.State.Breaks)
 	dv.UpdateAllBreaks()
 	dv.ShowBreaks(false)
+	dv.SaveBreaks()
+}
+
+// SaveBreaks saves the current breakpoints in the project prefs
+func (dv *DebugView) SaveBreaks() {
+	if dv.Gide == nil || dv.Gide.IsDeleted() {
+		return
+	}
+	dv.Gide.ProjPrefs().Breaks = dv.State.SaveBreaks()
+}
+
+// RestoreBreaks restores the breakpoints saved in the project prefs,
+// except instruction-level ones, which only hold for the executable
+// they were set in.
+func (dv *DebugView) RestoreBreaks() {
+	if dv.Gide == nil || dv.Gide.IsDeleted() {
+		return
+	}
+	sbk := dv.Gide.ProjPrefs().Breaks
+	dv.State.Breaks = make([]*gidebug.Break, 0, len(sbk))
+	for _, br := range sbk {
+		if br.Kind == gidebug.BreakAddr {
+			continue
+		}
+		nb := *br
+		dv.State.Breaks = append(dv.State.Breaks, &nb)
+	}
+	dv.UpdateAllBreaks()
 }
 
 // AddBreak adds a breakpoint at given file path and line number.
@@ -299,6 +439,87 @@ func (dv *DebugView) SetBreaks() {
 func (dv *DebugView) AddBreak(fpath string, line int) {
 	dv.State.AddBreak(fpath, line)
 	dv.ShowBreaks(true)
+	dv.SaveBreaks()
+}
+
+// AddBreakFunc adds a breakpoint at the start of given function.
+// As with AddBreak, it is uploaded right before running.
+func (dv *DebugView) AddBreakFunc(fname string) {
+	fname = strings.TrimSpace(fname)
+	if fname == "" {
+		return
+	}
+	dv.State.AddBreakExpr(gidebug.BreakFunc, fname)
+	dv.ShowBreaks(true)
+	dv.SaveBreaks()
+}
+
+// AddWatch adds a data watchpoint on given variable expression, which
+// stops when it is written.  As with AddBreak, it is uploaded right
+// before running.
+func (dv *DebugView) AddWatch(expr string) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return
+	}
+	dv.State.AddBreakExpr(gidebug.BreakWatch, expr)
+	dv.ShowBreaks(true)
+	dv.SaveBreaks()
+}
+
+// AddBreakFuncPrompt prompts for the name of a function to break at,
+// with completion from the functions in the process
+func (dv *DebugView) AddBreakFuncPrompt() {
+	if dv.DbgIsAvail() {
+		dv.Funcs, _ = dv.Dbg.ListFuncs("")
+	}
+	dlg := gi.StringPromptDialog(dv.Viewport, "", "main.main",
+		gi.DlgOpts{Title: "Break at Function", Prompt: "Name of function to break at the start of, including the package (e.g., main.main) -- type part of the name for completion"},
+		dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			dlg := send.(*gi.Dialog)
+			if sig == int64(gi.DialogAccepted) {
+				dvv, _ := recv.Embed(KiT_DebugView).(*DebugView)
+				dvv.AddBreakFunc(gi.StringPromptDialogValue(dlg))
+			}
+		})
+	tf := dlg.Frame().ChildByName("str-field", 0).(*gi.TextField)
+	tf.SetCompleter(dv, CompleteFunc, CompleteFuncEdit)
+}
+
+// AddWatchPrompt prompts for a variable expression to watch
+func (dv *DebugView) AddWatchPrompt() {
+	gi.StringPromptDialog(dv.Viewport, "", "pkg.Var",
+		gi.DlgOpts{Title: "Watch", Prompt: "Variable expression to watch -- execution stops when it is written"},
+		dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			dlg := send.(*gi.Dialog)
+			if sig == int64(gi.DialogAccepted) {
+				dvv, _ := recv.Embed(KiT_DebugView).(*DebugView)
+				dvv.AddWatch(gi.StringPromptDialogValue(dlg))
+			}
+		})
+}
+
+// CompleteFunc is the completer for function names, matching the seed
+// anywhere within the names of the functions in the process
+func CompleteFunc(data interface{}, text string, posLn, posCh int) (md complete.Matches) {
+	dv := data.(*DebugView)
+	md.Seed = complete.SeedWhiteSpace(text)
+	if md.Seed == "" {
+		return md
+	}
+	lsd := strings.ToLower(md.Seed)
+	for _, fn := range dv.Funcs {
+		if strings.Contains(strings.ToLower(fn), lsd) {
+			md.Matches = append(md.Matches, complete.Completion{Text: fn})
+		}
+	}
+	return md
+}
+
+// CompleteFuncEdit edits completer text field after the user chooses from the candidate completions
+func CompleteFuncEdit(data interface{}, text string, cursorPos int, c complete.Completion, seed string) (ed complete.Edit) {
+	ed = complete.EditWord(text, cursorPos, c.Text, seed)
+	return ed
 }
 
 // DeleteBreak deletes given breakpoint.  If debugger is not yet
@@ -310,6 +531,7 @@ func (dv *DebugView) DeleteBreak(fpath string, line int) {
 	}
 	dv.DeleteBreakImpl(fpath, line)
 	dv.ShowBreaks(true)
+	dv.SaveBreaks()
 }
 
 // DeleteBreakImpl deletes given breakpoint with no other updates
@@ -332,19 +554,38 @@ func (dv *DebugView) DeleteBreakIdx(bidx int) {
 	}
 	bk := dv.BBreaks[bidx]
 	dv.DeleteBreakInBuf(bk.FPath, bk.Line)
-	if !dv.DbgIsAvail() {
-		dv.State.DeleteBreakByFile(bk.FPath, bk.Line)
-		return
+	if dv.DbgIsAvail() && bk.ID > 0 {
+		dv.Dbg.ClearBreak(bk.ID)
 	}
-	dv.Dbg.ClearBreak(bk.ID)
-	dv.State.DeleteBreakByID(bk.ID)
+	dv.State.DeleteBreak(bk)
 	dv.BackupBreaks()
+	dv.SaveBreaks()
+}
+
+// ToggleBreakPC adds or deletes an instruction-level breakpoint at given
+// address, which is within given file path and line number.
+// As with AddBreak, new breakpoints are uploaded right before running.
+func (dv *DebugView) ToggleBreakPC(pc uint64, fpath string, line int) {
+	bk, _ := dv.State.BreakByPC(pc)
+	if bk == nil {
+		dv.State.AddBreakPC(pc, fpath, line)
+	} else {
+		dv.DeleteBreakInBuf(bk.FPath, bk.Line)
+		if dv.DbgIsAvail() && bk.ID > 0 {
+			dv.Dbg.ClearBreak(bk.ID)
+		}
+		dv.State.DeleteBreakByPC(pc)
+		dv.UpdateAllBreaks()
+	}
+	dv.ShowBreaks(false)
+	dv.ShowDisasm(false)
+	dv.SaveBreaks()
 }
 
 // DeleteBreakInBuf delete breakpoint in its TextBuf
 // line is 1-based line number
 func (dv *DebugView) DeleteBreakInBuf(fpath string, line int) {
-	if dv.Gide == nil || dv.Gide.IsDeleted() {
+	if fpath == "" || dv.Gide == nil || dv.Gide.IsDeleted() {
 		return
 	}
 	tb := dv.Gide.TextBufForFile(fpath, false)
@@ -367,7 +608,7 @@ func (dv *DebugView) DeleteAllBreaks() {
 // UpdateBreakInBuf updates break status in its TextBuf
 // line is 1-based line number
 func (dv *DebugView) UpdateBreakInBuf(fpath string, line int, stat DebugBreakStatus) {
-	if dv.Gide == nil || dv.Gide.IsDeleted() {
+	if fpath == "" || dv.Gide == nil || dv.Gide.IsDeleted() {
 		return
 	}
 	tb := dv.Gide.TextBufForFile(fpath, false)
@@ -415,11 +656,15 @@ func (dv *DebugView) InitState(ds *gidebug.State) {
 	} else {
 		dv.SetStatus(gidebug.Stopped)
 	}
+	dv.State.SavePrevVars()
 	err := dv.Dbg.InitAllState(&dv.State)
 	if err == gidebug.IsRunningErr {
 		dv.SetStatus(gidebug.Running)
 		return
 	}
+	dv.UpdateGlobalVars()
+	dv.State.DiffVars()
+	dv.UpdateMemory()
 	dv.UpdateFmState()
 }
 
@@ -442,17 +687,160 @@ func (dv *DebugView) UpdateFmState() {
 			dv.SetStatus(gidebug.Breakpoint)
 		}
 	}
+	dv.UpdateInlineVals()
 	dv.UpdateAllBreaks()
+	dv.UpdateDisasm()
 	dv.ShowBreaks(false)
 	dv.ShowStack(false)
 	dv.ShowVars(false)
+	dv.ShowGlobalVars(false)
 	dv.ShowThreads(false)
 	if dv.Dbg.HasTasks() {
 		dv.ShowTasks(false)
 	}
+	dv.ShowDisasm(false)
+	dv.ShowMemory(false)
+	dv.ShowTraces(false)
 	dv.UpdateToolBar()
 }
 
+// UpdateDisasm gets the disassembly of the function of the current frame
+func (dv *DebugView) UpdateDisasm() {
+	dv.State.Disasm = nil
+	cf := dv.State.StackFrame(dv.State.CurFrame)
+	if cf == nil || cf.PC == 0 {
+		return
+	}
+	ins, err := dv.Dbg.Disassemble(dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame, cf.PC)
+	if err == nil {
+		dv.State.Disasm = ins
+	}
+}
+
+// DebugMemLen is the default number of bytes of memory to examine
+var DebugMemLen = 256
+
+// ExamineMemory shows given length of memory starting at given address in
+// the Memory tab, which is then updated at each stop.  length <= 0 uses
+// DebugMemLen.
+func (dv *DebugView) ExamineMemory(addr uint64, length int) {
+	if !dv.DbgIsAvail() {
+		return
+	}
+	if length <= 0 {
+		length = DebugMemLen
+	}
+	dv.State.MemAddr = addr
+	dv.State.MemLen = length
+	dv.State.Mem = nil
+	dv.State.PrevMem = nil
+	dv.UpdateMemory()
+	dv.ShowMemory(true)
+}
+
+// ExamineMemoryPrompt prompts for an address (and optional length) of
+// memory to examine
+func (dv *DebugView) ExamineMemoryPrompt() {
+	cur := ""
+	if dv.State.MemAddr != 0 {
+		cur = fmt.Sprintf("%#x %d", dv.State.MemAddr, dv.State.MemLen)
+	}
+	gi.StringPromptDialog(dv.Viewport, cur, "0x1234 256",
+		gi.DlgOpts{Title: "Examine Memory", Prompt: "Address of memory to examine (hex with 0x prefix, or decimal), optionally followed by the number of bytes"},
+		dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			dlg := send.(*gi.Dialog)
+			if sig == int64(gi.DialogAccepted) {
+				dvv, _ := recv.Embed(KiT_DebugView).(*DebugView)
+				flds := strings.Fields(gi.StringPromptDialogValue(dlg))
+				if len(flds) == 0 {
+					return
+				}
+				addr, err := strconv.ParseUint(flds[0], 0, 64)
+				if err != nil {
+					log.Println(err)
+					return
+				}
+				length := 0
+				if len(flds) > 1 {
+					length, _ = strconv.Atoi(flds[1])
+				}
+				dvv.ExamineMemory(addr, length)
+			}
+		})
+}
+
+// UpdateMemory gets the memory being examined, if any, from the debugger
+func (dv *DebugView) UpdateMemory() {
+	if dv.State.MemAddr == 0 {
+		return
+	}
+	mem, _ := dv.Dbg.ExamineMemory(dv.State.MemAddr, dv.State.MemLen)
+	dv.State.SetMem(mem)
+}
+
+// UpdateInlineVals updates the values of the variables of the current
+// frame that are shown inline in the editor, at the lines where they were
+// last assigned in the function, up to the current line
+func (dv *DebugView) UpdateInlineVals() {
+	dv.ClearInlineVals()
+	cf := dv.State.StackFrame(dv.State.CurFrame)
+	if cf == nil || cf.FPath == "" || dv.Gide == nil || dv.Gide.IsDeleted() {
+		return
+	}
+	tb := dv.Gide.TextBufForFile(cf.FPath, false)
+	if tb == nil {
+		return
+	}
+	lns := make([]string, tb.NumLines())
+	for i := range lns {
+		lns[i] = string(tb.Line(i))
+	}
+	dv.InlineVals = map[string]map[int]string{cf.FPath: DebugInlineVals(dv.State.Vars, lns, cf.Line)}
+	tb.Refresh()
+}
+
+// ClearInlineVals removes the inline values of variables from the editor
+func (dv *DebugView) ClearInlineVals() {
+	ivs := dv.InlineVals
+	dv.InlineVals = nil
+	if dv.Gide == nil || dv.Gide.IsDeleted() {
+		return
+	}
+	for fpath := range ivs {
+		if tb := dv.Gide.TextBufForFile(fpath, false); tb != nil {
+			tb.Refresh()
+		}
+	}
+}
+
+// DebugInlineVals returns the text to show inline for given variables, by
+// 0-based line in given source lines: each variable is shown at the last
+// line where it is assigned, between its declaration and the current
+// 1-based line curLn (which has not yet executed), or else at its
+// declaration.  Variables declared at or after curLn are not shown.
+func DebugInlineVals(vrs []*gidebug.Variable, lns []string, curLn int) map[int]string {
+	vals := make(map[int][]string)
+	for _, vr := range vrs {
+		if vr == nil || vr.Nm == "" || vr.Loc.Line <= 0 || vr.Loc.Line >= curLn || vr.Loc.Line > len(lns) {
+			continue
+		}
+		nm := regexp.QuoteMeta(vr.Nm)
+		asgn := regexp.MustCompile(`(?:^|[^\w.])(?:[\w.]+\s*,\s*)*` + nm + `(?:\s*,\s*[\w.]+)*\s*(?::|[-+*/%&|^]|<<|>>|&\^)?=(?:[^=]|$)|(?:^|[^\w.])` + nm + `\s*(?:\+\+|--)`)
+		aln := vr.Loc.Line - 1
+		for ln := vr.Loc.Line; ln < curLn-1 && ln < len(lns); ln++ {
+			if asgn.MatchString(lns[ln]) {
+				aln = ln
+			}
+		}
+		vals[aln] = append(vals[aln], vr.Label())
+	}
+	ivs := make(map[int]string, len(vals))
+	for ln, vs := range vals {
+		ivs[ln] = strings.Join(vs, ", ")
+	}
+	return ivs
+}
+
 // SetFrame sets the given frame depth level as active
 func (dv *DebugView) SetFrame(depth int) {
 	if !dv.DbgIsAvail() {
@@ -460,11 +848,39 @@ func (dv *DebugView) SetFrame(depth int) {
 	}
 	cf := dv.State.StackFrame(depth)
 	if cf != nil {
-		dv.Dbg.UpdateAllState(&dv.State, dv.State.CurTask, depth) // todo: CurTask is not general!
+		dv.Dbg.UpdateAllState(&dv.State, dv.Dbg.CurThreadID(&dv.State), depth)
+		dv.State.DiffVars()
 	}
 	dv.UpdateFmState()
 }
 
+// SwitchTask switches the debugger to given task (e.g., goroutine),
+// making it the current one for subsequent stepping etc.
+func (dv *DebugView) SwitchTask(id int) {
+	if !dv.DbgIsAvail() {
+		return
+	}
+	ds, err := dv.Dbg.SwitchTask(id)
+	if err != nil || ds == nil {
+		return
+	}
+	dv.InitState(ds)
+}
+
+// UpdateBlocked updates the Blocked status of all tasks, from their stacks
+func (dv *DebugView) UpdateBlocked() {
+	if !dv.DbgIsAvail() {
+		return
+	}
+	for _, tk := range dv.State.Tasks {
+		st, err := dv.Dbg.Stack(tk.ID, 20)
+		if err != nil {
+			continue
+		}
+		tk.Blocked = gidebug.BlockedReason(st)
+	}
+}
+
 // SetThread sets the given thread as active -- this must be TaskID if HasTasks
 // and ThreadID if not.
 func (dv *DebugView) SetThread(threadID int) {
@@ -514,6 +930,79 @@ func (dv *DebugView) FindFrames(fpath string, line int) {
 	dv.ShowFindFrames(true)
 }
 
+// AddTrace adds given tracepoint hit to the Trace log, updating the Trace
+// tab at most every DebugTraceUpdate while running
+func (dv *DebugView) AddTrace(te *gidebug.TraceEvent) {
+	dv.State.Traces = append(dv.State.Traces, te)
+	if time.Since(dv.TraceTime) < DebugTraceUpdate {
+		return
+	}
+	dv.TraceTime = time.Now()
+	updt := dv.UpdateStart()
+	dv.ShowTraces(false)
+	dv.UpdateEnd(updt)
+}
+
+// ClearTraces clears the Trace log
+func (dv *DebugView) ClearTraces() {
+	dv.State.Traces = nil
+	dv.ShowTraces(false)
+}
+
+// MakeDump returns a dump of the current state: all threads and tasks with
+// their stacks, and the local variables of the current frame
+func (dv *DebugView) MakeDump() *gidebug.StateDump {
+	dp := gidebug.NewStateDump(&dv.State)
+	dp.Exe = dv.ExePath
+	if !dv.DbgIsAvail() {
+		return dp
+	}
+	if dv.Dbg.HasTasks() {
+		for _, tk := range dv.State.Tasks {
+			if _, has := dp.Stacks[tk.ID]; has {
+				continue
+			}
+			if st, err := dv.Dbg.Stack(tk.ID, 100); err == nil {
+				dp.SetStack(tk.ID, st)
+			}
+		}
+	} else {
+		for _, th := range dv.State.Threads {
+			if _, has := dp.Stacks[th.ID]; has {
+				continue
+			}
+			if st, err := dv.Dbg.Stack(th.ID, 100); err == nil {
+				dp.SetStack(th.ID, st)
+			}
+		}
+	}
+	return dp
+}
+
+// ExportDump saves a dump of the current state to given file, as JSON if
+// it has a .json extension, and otherwise as text in the format of a Go
+// goroutine dump -- it can be browsed later with Debug Dump
+func (dv *DebugView) ExportDump(fname gi.FileName) {
+	if !dv.DbgIsAvail() {
+		return
+	}
+	if err := dv.MakeDump().Save(string(fname)); err != nil {
+		gi.PromptDialog(dv.Viewport, gi.DlgOpts{Title: "Could not Export Dump", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+	}
+}
+
+// ExportDumpDialog prompts for a file to export a dump of the current state to
+func (dv *DebugView) ExportDumpDialog() {
+	giv.FileViewDialog(dv.Viewport, "", ".json,.txt", giv.DlgOpts{Title: "Export Dump", Prompt: "File to save the threads, tasks, stacks and locals to -- .json for JSON (which keeps the locals when browsed), otherwise text in the format of a Go goroutine dump"}, nil,
+		dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.DialogAccepted) {
+				dvv, _ := recv.Embed(KiT_DebugView).(*DebugView)
+				dlg, _ := send.(*gi.Dialog)
+				dvv.ExportDump(gi.FileName(giv.FileViewDialogValue(dlg)))
+			}
+		})
+}
+
 // ListGlobalVars lists global vars matching given optional filter in Global Vars tab
 func (dv *DebugView) ListGlobalVars(filter string) {
 	if !dv.DbgIsAvail() {
@@ -523,10 +1012,28 @@ func (dv *DebugView) ListGlobalVars(filter string) {
 	if err != nil {
 		return
 	}
+	if filter != dv.GlobalFilter {
+		dv.State.PrevGlobalVars = nil
+	}
+	dv.GlobalFilter = filter
 	dv.State.GlobalVars = vrs
+	dv.State.RemovedGlobalVars = gidebug.DiffVars(dv.State.PrevGlobalVars, vrs)
 	dv.ShowGlobalVars(true)
 }
 
+// UpdateGlobalVars lists the global vars again with the last filter used,
+// if they have been listed, so they can be compared to the previous stop
+func (dv *DebugView) UpdateGlobalVars() {
+	if len(dv.State.GlobalVars) == 0 || dv.State.GlobalVars[0].Nm == "" {
+		return
+	}
+	vrs, err := dv.Dbg.ListGlobalVars(dv.GlobalFilter)
+	if err != nil {
+		return
+	}
+	dv.State.GlobalVars = vrs
+}
+
 // ShowFile shows the file name in gide
 func (dv *DebugView) ShowFile(fpath string, line int) {
 	if fpath == "" || fpath == "?" {
@@ -642,19 +1149,49 @@ func (dv *DebugView) ShowGlobalVars(selTab bool) {
 	sv.ShowVars()
 }
 
+// ShowDisasm shows the current disassembly
+func (dv *DebugView) ShowDisasm(selTab bool) {
+	if selTab {
+		dv.Tabs().SelectTabByName("Disasm")
+	}
+	sv := dv.DisasmVw()
+	sv.ShowDisasm()
+}
+
+// ShowMemory shows the memory being examined
+func (dv *DebugView) ShowMemory(selTab bool) {
+	if selTab {
+		dv.Tabs().SelectTabByName("Memory")
+	}
+	sv := dv.MemoryVw()
+	sv.ShowMemory()
+}
+
+// ShowTraces shows the Trace log
+func (dv *DebugView) ShowTraces(selTab bool) {
+	if selTab {
+		dv.Tabs().SelectTabByName("Trace")
+	}
+	sv := dv.TraceVw()
+	sv.ShowTraces()
+}
+
 // ShowVar shows info on a given variable within the current frame scope in a text view dialog
 func (dv *DebugView) ShowVar(name string) error {
 	if !dv.DbgIsAvail() {
 		return nil
 	}
-	vv, err := dv.Dbg.GetVar(name, dv.State.CurTask, dv.State.CurFrame)
+	vv, err := dv.Dbg.GetVar(name, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 	if err != nil {
 		return err
 	}
+	if cv := dv.State.VarByName(name); cv != nil && (cv.Prev != nil || cv.Change == gidebug.VarAdded) {
+		vv.DiffPrev(cv.Prev)
+	}
 	frinfo := ""
 	cf := dv.State.StackFrame(dv.State.CurFrame)
 	if cf != nil {
-		frinfo = "at: " + cf.FPath + fmt.Sprintf(":%d  Thread: %d  Depth: %d", cf.Line, dv.State.CurTask, dv.State.CurFrame)
+		frinfo = "at: " + cf.FPath + fmt.Sprintf(":%d  Thread: %d  Depth: %d", cf.Line, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 	}
 	VarViewDialog(vv, frinfo, dv)
 	return nil
@@ -667,7 +1204,7 @@ func (dv *DebugView) VarValue(varNm string) string {
 		return ""
 	}
 	if strings.Contains(varNm, ".") {
-		vv, err := dv.Dbg.GetVar(varNm, dv.State.CurTask, dv.State.CurFrame)
+		vv, err := dv.Dbg.GetVar(varNm, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 		if err == nil {
 			return vv.Value
 		}
@@ -704,8 +1241,13 @@ func (dv *DebugView) SetStatus(stat gidebug.Status) {
 		stl.CurBgColor = stl.CurBgColor.Darker(75)
 	}
 	lbl := stat.String()
-	if stat == gidebug.Breakpoint {
+	switch {
+	case stat == gidebug.Breakpoint:
 		lbl = fmt.Sprintf("Break: %d", dv.State.CurBreak)
+	case stat == gidebug.Stopped && dv.State.Mode == gidebug.Dump:
+		lbl = "Dump"
+	case stat == gidebug.Stopped && dv.DbgIsCore():
+		lbl = "Core"
 	}
 	stl.SetText(lbl)
 	tb.UpdateActions()
@@ -715,11 +1257,20 @@ func (dv *DebugView) SetStatus(stat gidebug.Status) {
 //    GUI config
 
 // Config configures the view -- parameters for the job must have
-// already been set in ge.ProjParams.Debug.
-func (dv *DebugView) Config(ge Gide, sup filecat.Supported, exePath string) {
+// already been set in ge.ProjParams.Debug, unless a launch configuration
+// name is given, in which case they are set from it.
+func (dv *DebugView) Config(ge Gide, sup filecat.Supported, exePath, launch string) {
 	dv.Gide = ge
 	dv.Sup = sup
 	dv.ExePath = exePath
+	dv.Launch = launch
+	if launch == "" {
+		dv.ConfigMode = ge.ProjPrefs().Debug.Mode
+		dv.ConfigExe = exePath
+	} else { // as in the Debug command without a launch configuration
+		dv.ConfigMode = gidebug.Exec
+		dv.ConfigExe = string(ge.ProjPrefs().RunExec)
+	}
 	dv.Lay = gi.LayoutVert
 	dv.SetProp("spacing", gi.StdDialogVSpaceUnits)
 	config := kit.TypeAndNameList{}
@@ -732,15 +1283,35 @@ func (dv *DebugView) Config(ge Gide, sup filecat.Supported, exePath string) {
 		dv.OutBuf.InitName(dv.OutBuf, "debug-outbuf")
 		dv.ConfigToolBar()
 		dv.ConfigTabs()
-		dv.State.Breaks = nil // get rid of dummy
+		dv.RestoreBreaks() // get rid of dummy
 	} else {
 		updt = dv.UpdateStart()
 	}
+	dv.UpdateLaunchCombo()
 	dv.Start()
 	dv.SetFullReRender()
 	dv.UpdateEnd(updt)
 }
 
+// DebugNoLaunch is the launch choice for not using a launch configuration
+const DebugNoLaunch = "(none)"
+
+// LaunchCombo returns the launch configuration chooser in the toolbar
+func (dv *DebugView) LaunchCombo() *gi.ComboBox {
+	return dv.ToolBar().ChildByName("launch", 11).(*gi.ComboBox)
+}
+
+// UpdateLaunchCombo updates the launch configuration chooser from the
+// project launch configurations
+func (dv *DebugView) UpdateLaunchCombo() {
+	lcc := dv.LaunchCombo()
+	lcs := dv.Gide.ProjPrefs().Launches
+	lcc.ItemsFromStringList(append([]string{DebugNoLaunch}, lcs.Names()...), true, 0)
+	if _, i := lcs.ByName(dv.Launch); i >= 0 {
+		lcc.SetCurIndex(i + 1)
+	}
+}
+
 // ToolBar returns the find toolbar
 func (dv *DebugView) ToolBar() *gi.ToolBar {
 	return dv.ChildByName("toolbar", 0).(*gi.ToolBar)
@@ -793,6 +1364,24 @@ func (dv DebugView) AllVarVw() *VarsView {
 	return tv.TabByName("Global Vars").(*VarsView)
 }
 
+// DisasmVw returns the disassembly view from tabs
+func (dv DebugView) DisasmVw() *DisasmView {
+	tv := dv.Tabs()
+	return tv.TabByName("Disasm").(*DisasmView)
+}
+
+// MemoryVw returns the memory view from tabs
+func (dv DebugView) MemoryVw() *MemoryView {
+	tv := dv.Tabs()
+	return tv.TabByName("Memory").(*MemoryView)
+}
+
+// TraceVw returns the trace view from tabs
+func (dv DebugView) TraceVw() *TraceView {
+	tv := dv.Tabs()
+	return tv.TabByName("Trace").(*TraceView)
+}
+
 // ConsoleText returns the console TextView
 func (dv DebugView) ConsoleText() *giv.TextView {
 	tv := dv.Tabs()
@@ -800,6 +1389,12 @@ func (dv DebugView) ConsoleText() *giv.TextView {
 	return cv
 }
 
+// ReplCombo returns the console input ComboBox
+func (dv DebugView) ReplCombo() *gi.ComboBox {
+	tv := dv.Tabs()
+	return tv.TabByName("Console").ChildByName("repl", 1).(*gi.ComboBox)
+}
+
 // ConfigTabs configures the tabs
 func (dv *DebugView) ConfigTabs() {
 	tb := dv.Tabs()
@@ -808,6 +1403,9 @@ func (dv *DebugView) ConfigTabs() {
 	otv := ConfigOutputTextView(cv)
 	dv.OutBuf.Opts.LineNos = false
 	otv.SetBuf(dv.OutBuf)
+	if cv.ChildByName("repl", 1) == nil {
+		dv.ConfigRepl(cv)
+	}
 	bv := tb.RecycleTab("Breaks", KiT_BreakView, false).(*BreakView)
 	bv.Config(dv)
 	sv := tb.RecycleTab("Stack", KiT_StackView, false).(*StackView)
@@ -824,31 +1422,188 @@ func (dv *DebugView) ConfigTabs() {
 	ff.Config(dv, true) // find frames
 	av := tb.RecycleTab("Global Vars", KiT_VarsView, false).(*VarsView)
 	av.Config(dv, true) // all vars
+	dav := tb.RecycleTab("Disasm", KiT_DisasmView, false).(*DisasmView)
+	dav.Config(dv)
+	mv := tb.RecycleTab("Memory", KiT_MemoryView, false).(*MemoryView)
+	mv.Config(dv)
+	trv := tb.RecycleTab("Trace", KiT_TraceView, false).(*TraceView)
+	trv.Config(dv)
+}
+
+// ConfigRepl adds the console input line to given console layout
+func (dv *DebugView) ConfigRepl(cv *gi.Layout) {
+	rc := cv.AddNewChild(gi.KiT_ComboBox, "repl").(*gi.ComboBox)
+	rc.Editable = true
+	rc.SetStretchMaxWidth()
+	rc.Tooltip = "debugger console: enter an expression to evaluate, call f(x) to call a function, set x = value to set a variable, or :cmd for a raw debugger command (:help lists them) -- click for history"
+	rc.ConfigParts()
+	rc.ItemsFromStringList(dv.Gide.ProjPrefs().DebugHist, false, 0)
+	tf, _ := rc.TextField()
+	tf.Placeholder = "expr | call f(x) | set x = value | :cmd"
+	tf.SetCompleter(dv, CompleteRepl, CompleteFuncEdit)
+	tf.TextFieldSig.Connect(dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) {
+			dvv := recv.Embed(KiT_DebugView).(*DebugView)
+			tff := send.(*gi.TextField)
+			cmd := tff.Text()
+			tff.SetText("")
+			dvv.ReplEval(cmd)
+		}
+	})
 }
 
-// ActionActivate is the update function for actions that depend on the debugger being avail
-// for input commands
-func (dv *DebugView) ActionActivate(act *gi.Action) {
-	act.SetActiveStateUpdt(dv.DbgIsAvail())
-}
-
-func (dv *DebugView) UpdateToolBar() {
-	tb := dv.ToolBar()
-	tb.UpdateActions()
+// ConsoleWrite writes given message string to the console
+func (dv *DebugView) ConsoleWrite(msg string) {
+	if dv.Dbg != nil {
+		dv.Dbg.WriteToConsole(msg)
+		return
+	}
+	dv.OutBuf.AppendText([]byte(msg), true)
 }
 
-func (dv *DebugView) ConfigToolBar() {
-	tb := dv.ToolBar()
-	if tb.HasChildren() {
+// ReplEval evaluates given input to the debugger console: an expression
+// to evaluate, call f(x) to call a function, set x = value to set a
+// variable, or :cmd for a raw debugger command.  The input and its result
+// are written to the console.
+func (dv *DebugView) ReplEval(cmd string) {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
 		return
 	}
-	tb.SetStretchMaxWidth()
+	pp := dv.Gide.ProjPrefs()
+	gi.StringsInsertFirstUnique(&pp.DebugHist, cmd, gi.Prefs.Params.SavedPathsMax)
+	dv.ReplCombo().ItemsFromStringList(pp.DebugHist, false, 0)
+	dv.ConsoleWrite("> " + cmd + "\n")
+	if !dv.DbgIsAvail() {
+		dv.ConsoleWrite("debugger is not available\n")
+		return
+	}
+	out, err := dv.ReplEvalImpl(cmd)
+	if err != nil {
+		dv.ConsoleWrite(fmt.Sprintf("error: %v\n", err))
+		return
+	}
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	dv.ConsoleWrite(out)
+}
 
-	// rb := dv.ReplBar()
-	// rb.SetStretchMaxWidth()
+// ReplEvalImpl does the evaluation for ReplEval, returning the output
+func (dv *DebugView) ReplEvalImpl(cmd string) (string, error) {
+	tid := dv.Dbg.CurThreadID(&dv.State)
+	switch {
+	case strings.HasPrefix(cmd, ":"):
+		// commands that change the state of a core are refused by the debugger
+		return dv.Dbg.Command(strings.TrimSpace(cmd[1:]), tid, dv.State.CurFrame)
+	case strings.HasPrefix(cmd, "call "):
+		if !dv.DbgCanRun() {
+			return "", fmt.Errorf("cannot call functions in this mode")
+		}
+		ds, err := dv.Dbg.Call(tid, strings.TrimSpace(cmd[5:]), false)
+		if err != nil || ds == nil {
+			return "", err
+		}
+		var rets []string
+		for _, vr := range ds.Returns {
+			rets = append(rets, vr.Label())
+		}
+		dv.InitState(ds)
+		return strings.Join(rets, "\n"), nil
+	case strings.HasPrefix(cmd, "set "):
+		if dv.DbgIsCore() {
+			return "", fmt.Errorf("cannot set variables in this mode")
+		}
+		asgn := strings.TrimSpace(cmd[4:])
+		eq := strings.Index(asgn, "=")
+		if eq <= 0 || strings.HasPrefix(asgn[eq:], "==") {
+			return "", fmt.Errorf("set requires: set name = value")
+		}
+		nm := strings.TrimSpace(asgn[:eq])
+		val := strings.TrimSpace(asgn[eq+1:])
+		if err := dv.Dbg.SetVar(nm, val, tid, dv.State.CurFrame); err != nil {
+			return "", err
+		}
+		vrs, err := dv.Dbg.ListVars(tid, dv.State.CurFrame)
+		if err == nil {
+			dv.State.Vars = vrs
+			dv.State.DiffVars()
+			dv.ShowVars(false)
+		}
+		return nm + " = " + val, nil
+	}
+	vr, err := dv.Dbg.GetVar(cmd, tid, dv.State.CurFrame)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v (%v)", vr.ValueString(true, 0, 4, 200, false), vr.TypeStr), nil
+}
 
-	// cb.AddAction(gi.ActOpts{Label: "Updt", Icon: "update", Tooltip: "update current state"}, dv.This(),
-	// 	func(recv, send ki.Ki, sig int64, data interface{}) {
+// CompleteRepl is the completer for the debugger console, matching the seed
+// as a prefix of the local variables, and anywhere within the names of the
+// functions and types in the process
+func CompleteRepl(data interface{}, text string, posLn, posCh int) (md complete.Matches) {
+	dv := data.(*DebugView)
+	md.Seed = complete.SeedWhiteSpace(text)
+	if md.Seed == "" {
+		return md
+	}
+	if dv.DbgIsAvail() {
+		if dv.Funcs == nil {
+			dv.Funcs, _ = dv.Dbg.ListFuncs("")
+		}
+		if dv.Types == nil {
+			dv.Types, _ = dv.Dbg.ListTypes("")
+		}
+	}
+	for _, vr := range dv.State.Vars {
+		if vr != nil && strings.HasPrefix(vr.Nm, md.Seed) {
+			md.Matches = append(md.Matches, complete.Completion{Text: vr.Nm, Icon: "var"})
+		}
+	}
+	lsd := strings.ToLower(md.Seed)
+	for _, fn := range dv.Funcs {
+		if strings.Contains(strings.ToLower(fn), lsd) {
+			md.Matches = append(md.Matches, complete.Completion{Text: fn, Icon: "function"})
+		}
+	}
+	for _, ty := range dv.Types {
+		if strings.Contains(strings.ToLower(ty), lsd) {
+			md.Matches = append(md.Matches, complete.Completion{Text: ty, Icon: "type"})
+		}
+	}
+	return md
+}
+
+// ActionActivate is the update function for actions that depend on the debugger being avail
+// for input commands
+func (dv *DebugView) ActionActivate(act *gi.Action) {
+	act.SetActiveStateUpdt(dv.DbgIsAvail())
+}
+
+// ActionActivateRun is the update function for actions that run or step the
+// process, which also requires that it is not a core dump
+func (dv *DebugView) ActionActivateRun(act *gi.Action) {
+	act.SetActiveStateUpdt(dv.DbgCanRun())
+}
+
+func (dv *DebugView) UpdateToolBar() {
+	tb := dv.ToolBar()
+	tb.UpdateActions()
+}
+
+func (dv *DebugView) ConfigToolBar() {
+	tb := dv.ToolBar()
+	if tb.HasChildren() {
+		return
+	}
+	tb.SetStretchMaxWidth()
+
+	// rb := dv.ReplBar()
+	// rb.SetStretchMaxWidth()
+
+	// cb.AddAction(gi.ActOpts{Label: "Updt", Icon: "update", Tooltip: "update current state"}, dv.This(),
+	// 	func(recv, send ki.Ki, sig int64, data interface{}) {
 	// 		dvv := recv.Embed(KiT_DebugView).(*DebugView)
 	// 		dvv.UpdateView()
 	// 		cb.UpdateActions()
@@ -865,43 +1620,60 @@ func (dv *DebugView) ConfigToolBar() {
 			dvv.Start()
 			tb.UpdateActions()
 		})
-	tb.AddAction(gi.ActOpts{Label: "Cont", Icon: "play", Tooltip: "continue execution from current point", Shortcut: "Control+Alt+R", UpdateFunc: dv.ActionActivate}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Cont", Icon: "play", Tooltip: "continue execution from current point", Shortcut: "Control+Alt+R", UpdateFunc: dv.ActionActivateRun}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
 			go dvv.Continue()
 			tb.UpdateActions()
 		})
 	gi.AddNewLabel(tb, "step", "Step: ")
-	tb.AddAction(gi.ActOpts{Label: "Over", Icon: "step-over", Tooltip: "continues to the next source line, not entering function calls", Shortcut: "F6", UpdateFunc: dv.ActionActivate}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Over", Icon: "step-over", Tooltip: "continues to the next source line, not entering function calls", Shortcut: "F6", UpdateFunc: dv.ActionActivateRun}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
 			dvv.StepOver()
 			tb.UpdateActions()
 		})
-	tb.AddAction(gi.ActOpts{Label: "Into", Icon: "step-into", Tooltip: "continues to the next source line, entering into function calls", Shortcut: "F7", UpdateFunc: dv.ActionActivate}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Into", Icon: "step-into", Tooltip: "continues to the next source line, entering into function calls", Shortcut: "F7", UpdateFunc: dv.ActionActivateRun}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
 			dvv.StepInto()
 			tb.UpdateActions()
 		})
-	tb.AddAction(gi.ActOpts{Label: "Out", Icon: "step-out", Tooltip: "continues to the return point of the current function", Shortcut: "F8", UpdateFunc: dv.ActionActivate}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Out", Icon: "step-out", Tooltip: "continues to the return point of the current function", Shortcut: "F8", UpdateFunc: dv.ActionActivateRun}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
 			dvv.StepOut()
 			tb.UpdateActions()
 		})
-	tb.AddAction(gi.ActOpts{Label: "Single", Icon: "step-fwd", Tooltip: "steps a single CPU instruction", UpdateFunc: dv.ActionActivate}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Single", Icon: "step-fwd", Tooltip: "steps a single CPU instruction", UpdateFunc: dv.ActionActivateRun}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
-			dvv.StepOut()
+			dvv.SingleStep()
 			tb.UpdateActions()
 		})
-	tb.AddAction(gi.ActOpts{Label: "Stop", Icon: "stop", Tooltip: "stop execution"}, dv.This(),
+	tb.AddAction(gi.ActOpts{Label: "Stop", Icon: "stop", Tooltip: "stop execution", UpdateFunc: func(act *gi.Action) {
+		act.SetActiveStateUpdt(!dv.DbgIsCore())
+	}}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
 			dvv := recv.Embed(KiT_DebugView).(*DebugView)
 			dvv.Stop()
 			tb.UpdateActions()
 		})
+	tb.AddSeparator("sep-launch")
+	lcl := gi.AddNewLabel(tb, "launch-lbl", "Launch:")
+	lcl.Tooltip = "launch configuration to debug with -- these are edited in the project prefs (Launches), where the one to use for the Debug command is also set (CurLaunch)"
+	lcc := tb.AddNewChild(gi.KiT_ComboBox, "launch").(*gi.ComboBox)
+	lcc.Tooltip = lcl.Tooltip
+	lcc.ComboSig.Connect(dv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		dvv := recv.Embed(KiT_DebugView).(*DebugView)
+		cb := send.(*gi.ComboBox)
+		nm := cb.CurVal.(string)
+		if nm == DebugNoLaunch {
+			nm = ""
+		}
+		dvv.SetLaunch(nm)
+		tb.UpdateActions()
+	})
 	tb.AddSeparator("sep-av")
 	tb.AddAction(gi.ActOpts{Label: "Global Vars", Icon: "search", Tooltip: "list variables at global scope, subject to filter (name contains)"}, dv.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
@@ -909,6 +1681,11 @@ func (dv *DebugView) ConfigToolBar() {
 			giv.CallMethod(dvv, "ListGlobalVars", dvv.Viewport)
 			tb.UpdateActions()
 		})
+	tb.AddAction(gi.ActOpts{Label: "Export...", Icon: "file-save", Tooltip: "save a dump of the current threads, tasks, stacks and locals to a JSON or text file, to share it -- browse it with Debug Dump", UpdateFunc: dv.ActionActivate}, dv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			dvv := recv.Embed(KiT_DebugView).(*DebugView)
+			dvv.ExportDumpDialog()
+		})
 }
 
 // DebugViewProps are style properties for DebugView
@@ -1023,10 +1800,23 @@ func (sv *BreakView) DebugVw() *DebugView {
 func (sv *BreakView) Config(dv *DebugView) {
 	sv.Lay = gi.LayoutVert
 	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "breakbar")
 	config.Add(giv.KiT_TableView, "breaks")
 	mods, updt := sv.ConfigChildren(config)
 	tv := sv.TableView()
 	if mods {
+		bb := sv.BreakBar()
+		bb.SetStretchMaxWidth()
+		bb.AddAction(gi.ActOpts{Label: "Break Func", Icon: "plus", Tooltip: "add a breakpoint at the start of a function, chosen by name"}, dv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				dvv := recv.Embed(KiT_DebugView).(*DebugView)
+				dvv.AddBreakFuncPrompt()
+			})
+		bb.AddAction(gi.ActOpts{Label: "Watch", Icon: "plus", Tooltip: "add a data watchpoint on a variable expression, which stops execution when it is written"}, dv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				dvv := recv.Embed(KiT_DebugView).(*DebugView)
+				dvv.AddWatchPrompt()
+			})
 		tv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			if sig == int64(giv.SliceViewDoubleClicked) {
 				idx := data.(int)
@@ -1045,9 +1835,14 @@ func (sv *BreakView) Config(dv *DebugView) {
 	sv.UpdateEnd(updt)
 }
 
+// BreakBar returns the breaks toolbar
+func (sv *BreakView) BreakBar() *gi.ToolBar {
+	return sv.ChildByName("breakbar", 0).(*gi.ToolBar)
+}
+
 // TableView returns the tableview
 func (sv *BreakView) TableView() *giv.TableView {
-	return sv.ChildByName("breaks", 0).(*giv.TableView)
+	return sv.ChildByName("breaks", 1).(*giv.TableView)
 }
 
 // ShowBreaks triggers update of view of State.Breaks
@@ -1140,12 +1935,445 @@ var ThreadViewProps = ki.Props{
 	"max-height":    -1,
 }
 
+//////////////////////////////////////////////////////////////////////////////////////
+//  DisasmView
+
+// DisasmLine is one line in the disassembly view: either an instruction, or
+// the source line that the following instructions were compiled from
+type DisasmLine struct {
+	At   string `width:"2" desc:"=> marks the instruction at the PC of the current frame"`
+	Brk  bool   `width:"3" desc:"an instruction-level breakpoint is set at this instruction -- double-click to toggle"`
+	PC   uint64 `format:"%#X" desc:"address of the instruction -- 0 for source lines"`
+	Text string `width:"60" desc:"the instruction, or file:line and text for source lines"`
+	Dest string `desc:"destination function of a call instruction"`
+	Idx  int    `tableview:"-" desc:"index of the instruction in State.Disasm, -1 for source lines"`
+}
+
+// DisasmView is a view of the disassembly of the function of the current
+// frame, with the source lines interleaved
+type DisasmView struct {
+	gi.Layout
+	Lines []*DisasmLine `desc:"the lines shown in the view"`
+}
+
+var KiT_DisasmView = kit.Types.AddType(&DisasmView{}, DisasmViewProps)
+
+func (sv *DisasmView) DebugVw() *DebugView {
+	dv := sv.ParentByType(KiT_DebugView, ki.Embeds).Embed(KiT_DebugView).(*DebugView)
+	return dv
+}
+
+func (sv *DisasmView) Config(dv *DebugView) {
+	sv.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(giv.KiT_TableView, "disasm")
+	mods, updt := sv.ConfigChildren(config)
+	tv := sv.TableView()
+	if mods {
+		tv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(giv.SliceViewDoubleClicked) {
+				idx := data.(int)
+				sv.ToggleBreakIdx(idx)
+			}
+		})
+	} else {
+		updt = sv.UpdateStart()
+	}
+	tv.SetStretchMax()
+	tv.SetInactive()
+	tv.SetSlice(&sv.Lines)
+	sv.UpdateEnd(updt)
+}
+
+// TableView returns the tableview
+func (sv *DisasmView) TableView() *giv.TableView {
+	return sv.ChildByName("disasm", 0).(*giv.TableView)
+}
+
+// ToggleBreakIdx toggles an instruction-level breakpoint on the instruction
+// at given line index, or shows the file for source lines
+func (sv *DisasmView) ToggleBreakIdx(idx int) {
+	if idx < 0 || idx >= len(sv.Lines) {
+		return
+	}
+	dv := sv.DebugVw()
+	dl := sv.Lines[idx]
+	if dl.Idx < 0 { // source line: always followed by its first instruction
+		if idx+1 < len(sv.Lines) {
+			in := dv.State.Disasm[sv.Lines[idx+1].Idx]
+			dv.ShowFile(in.FPath, in.Line)
+		}
+		return
+	}
+	in := dv.State.Disasm[dl.Idx]
+	dv.ToggleBreakPC(in.PC, in.FPath, in.Line)
+}
+
+// disasmSrcLine returns the text of given 1-based line in given file,
+// reading the file into srcs map as needed
+func disasmSrcLine(srcs map[string][]string, fpath string, line int) string {
+	lns, has := srcs[fpath]
+	if !has {
+		b, err := ioutil.ReadFile(fpath)
+		if err == nil {
+			lns = strings.Split(string(b), "\n")
+		}
+		srcs[fpath] = lns
+	}
+	if line < 1 || line > len(lns) {
+		return ""
+	}
+	return strings.TrimSpace(lns[line-1])
+}
+
+// ConfigLines configures the Lines from State.Disasm, inserting a source
+// line each time the file or line of the instructions changes
+func (sv *DisasmView) ConfigLines() {
+	dv := sv.DebugVw()
+	var pc uint64
+	cf := dv.State.StackFrame(dv.State.CurFrame)
+	if cf != nil {
+		pc = cf.PC
+	}
+	sv.Lines = make([]*DisasmLine, 0, len(dv.State.Disasm))
+	srcs := make(map[string][]string)
+	fpath := ""
+	line := 0
+	for i, in := range dv.State.Disasm {
+		if in.FPath != fpath || in.Line != line {
+			fpath = in.FPath
+			line = in.Line
+			txt := fmt.Sprintf("%v:%v: %v", in.File, in.Line, disasmSrcLine(srcs, in.FPath, in.Line))
+			sv.Lines = append(sv.Lines, &DisasmLine{Text: txt, Idx: -1})
+		}
+		dl := &DisasmLine{PC: in.PC, Text: in.Text, Dest: in.Dest, Idx: i}
+		if in.PC == pc {
+			dl.At = "=>"
+		}
+		if bk, _ := dv.State.BreakByPC(in.PC); bk != nil && bk.On {
+			dl.Brk = true
+		}
+		sv.Lines = append(sv.Lines, dl)
+	}
+}
+
+// ShowDisasm triggers update of view of State.Disasm, selecting the
+// instruction at the current PC
+func (sv *DisasmView) ShowDisasm() {
+	tv := sv.TableView()
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	sv.ConfigLines()
+	tv.SetInactive()
+	for i, dl := range sv.Lines {
+		if dl.At != "" {
+			tv.SelectedIdx = i
+			break
+		}
+	}
+	tv.SetSlice(&sv.Lines)
+	sv.UpdateEnd(updt)
+}
+
+// DisasmViewProps are style properties for DebugView
+var DisasmViewProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  MemoryView
+
+// MemRow is one row of 16 bytes in the memory view, in hex and ASCII
+type MemRow struct {
+	Addr  uint64 `format:"%#X" desc:"address of the first byte in the row"`
+	X0    string `width:"2"`
+	X1    string `width:"2"`
+	X2    string `width:"2"`
+	X3    string `width:"2"`
+	X4    string `width:"2"`
+	X5    string `width:"2"`
+	X6    string `width:"2"`
+	X7    string `width:"2"`
+	X8    string `width:"2"`
+	X9    string `width:"2"`
+	XA    string `width:"2"`
+	XB    string `width:"2"`
+	XC    string `width:"2"`
+	XD    string `width:"2"`
+	XE    string `width:"2"`
+	XF    string `width:"2"`
+	ASCII string `width:"16" desc:"the bytes as ASCII text, with . for non-printable bytes"`
+}
+
+// MemRowBytes is the number of bytes in each MemRow
+const MemRowBytes = 16
+
+// MemRows returns rows for given memory starting at given address
+func MemRows(addr uint64, mem []byte) []*MemRow {
+	nr := (len(mem) + MemRowBytes - 1) / MemRowBytes
+	rows := make([]*MemRow, nr)
+	for ri := range rows {
+		mr := &MemRow{Addr: addr + uint64(ri*MemRowBytes)}
+		rv := reflect.ValueOf(mr).Elem()
+		asc := make([]byte, 0, MemRowBytes)
+		for i := 0; i < MemRowBytes; i++ {
+			off := ri*MemRowBytes + i
+			if off >= len(mem) {
+				break
+			}
+			b := mem[off]
+			rv.Field(1 + i).SetString(fmt.Sprintf("%02X", b))
+			if b >= 0x20 && b < 0x7f {
+				asc = append(asc, b)
+			} else {
+				asc = append(asc, '.')
+			}
+		}
+		mr.ASCII = string(asc)
+		rows[ri] = mr
+	}
+	return rows
+}
+
+// MemoryView is a hex and ASCII view of the memory being examined, with
+// the bytes that changed since the previous stop highlighted
+type MemoryView struct {
+	gi.Layout
+	Rows []*MemRow `desc:"the rows shown in the view"`
+}
+
+var KiT_MemoryView = kit.Types.AddType(&MemoryView{}, MemoryViewProps)
+
+func (sv *MemoryView) DebugVw() *DebugView {
+	dv := sv.ParentByType(KiT_DebugView, ki.Embeds).Embed(KiT_DebugView).(*DebugView)
+	return dv
+}
+
+func (sv *MemoryView) Config(dv *DebugView) {
+	sv.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "membar")
+	config.Add(giv.KiT_TableView, "memory")
+	mods, updt := sv.ConfigChildren(config)
+	tv := sv.TableView()
+	if mods {
+		mb := sv.MemBar()
+		mb.SetStretchMaxWidth()
+		mb.AddAction(gi.ActOpts{Label: "Address", Icon: "search", Tooltip: "examine memory at given address", UpdateFunc: dv.ActionActivate}, dv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				dvv := recv.Embed(KiT_DebugView).(*DebugView)
+				dvv.ExamineMemoryPrompt()
+			})
+		mb.AddAction(gi.ActOpts{Label: "Prev", Icon: "wedge-up", Tooltip: "examine the memory just before the current range", UpdateFunc: dv.ActionActivate}, dv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				dvv := recv.Embed(KiT_DebugView).(*DebugView)
+				if ln := uint64(dvv.State.MemLen); dvv.State.MemAddr > ln {
+					dvv.ExamineMemory(dvv.State.MemAddr-ln, int(ln))
+				}
+			})
+		mb.AddAction(gi.ActOpts{Label: "Next", Icon: "wedge-down", Tooltip: "examine the memory just after the current range", UpdateFunc: dv.ActionActivate}, dv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				dvv := recv.Embed(KiT_DebugView).(*DebugView)
+				if dvv.State.MemAddr != 0 {
+					dvv.ExamineMemory(dvv.State.MemAddr+uint64(dvv.State.MemLen), dvv.State.MemLen)
+				}
+			})
+		tv.StyleFunc = func(tv *giv.TableView, slice interface{}, widg gi.Node2D, row, col int, vv giv.ValueView) {
+			wi := widg.AsNode2D()
+			if col < 1 || col > MemRowBytes || !dv.State.MemChanged(row*MemRowBytes+col-1) {
+				if _, err := wi.PropTry("background-color"); err == nil {
+					wi.DeleteProp("background-color")
+					wi.SetFullReRender()
+				}
+				return
+			}
+			wi.SetProp("background-color", DebugBreakColors[DebugPCCurrent])
+			wi.SetFullReRender()
+		}
+	} else {
+		updt = sv.UpdateStart()
+	}
+	tv.SetStretchMax()
+	tv.SetInactive()
+	tv.SetSlice(&sv.Rows)
+	sv.UpdateEnd(updt)
+}
+
+// MemBar returns the memory toolbar
+func (sv *MemoryView) MemBar() *gi.ToolBar {
+	return sv.ChildByName("membar", 0).(*gi.ToolBar)
+}
+
+// TableView returns the tableview
+func (sv *MemoryView) TableView() *giv.TableView {
+	return sv.ChildByName("memory", 1).(*giv.TableView)
+}
+
+// ShowMemory triggers update of view of State.Mem
+func (sv *MemoryView) ShowMemory() {
+	tv := sv.TableView()
+	dv := sv.DebugVw()
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	sv.Rows = MemRows(dv.State.MemAddr, dv.State.Mem)
+	tv.SetInactive()
+	tv.SetSlice(&sv.Rows)
+	sv.MemBar().UpdateActions()
+	sv.UpdateEnd(updt)
+}
+
+// MemoryViewProps are style properties for DebugView
+var MemoryViewProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  TraceView
+
+// TraceView is a view of the log of tracepoint hits
+type TraceView struct {
+	gi.Layout
+	Filter string                `desc:"only show trace events whose function, file or values contain this string"`
+	Events []*gidebug.TraceEvent `desc:"the trace events being shown"`
+}
+
+var KiT_TraceView = kit.Types.AddType(&TraceView{}, TraceViewProps)
+
+func (sv *TraceView) DebugVw() *DebugView {
+	dv := sv.ParentByType(KiT_DebugView, ki.Embeds).Embed(KiT_DebugView).(*DebugView)
+	return dv
+}
+
+func (sv *TraceView) Config(dv *DebugView) {
+	sv.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "tracebar")
+	config.Add(giv.KiT_TableView, "traces")
+	mods, updt := sv.ConfigChildren(config)
+	tv := sv.TableView()
+	if mods {
+		sv.ConfigTraceBar(dv)
+		tv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(giv.SliceViewDoubleClicked) {
+				svv, _ := recv.Embed(KiT_TraceView).(*TraceView)
+				idx := data.(int)
+				if idx >= 0 && idx < len(svv.Events) {
+					te := svv.Events[idx]
+					dv.ShowFile(te.FPath, te.Line)
+				}
+			}
+		})
+	} else {
+		updt = sv.UpdateStart()
+	}
+	tv.SetStretchMax()
+	tv.SetInactive()
+	tv.SetSlice(&sv.Events)
+	sv.UpdateEnd(updt)
+}
+
+// ConfigTraceBar configures the toolbar with the filter and export controls
+func (sv *TraceView) ConfigTraceBar(dv *DebugView) {
+	tb := sv.TraceBar()
+	tb.SetStretchMaxWidth()
+	gi.AddNewLabel(tb, "filter-lbl", "Filter:")
+	ftf := gi.AddNewTextField(tb, "filter")
+	ftf.Tooltip = "only show trace events whose function, file or values contain this string -- hit enter to update"
+	ftf.SetMinPrefWidth(units.NewCh(30))
+	ftf.TextFieldSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) || sig == int64(gi.TextFieldCleared) {
+			svv, _ := recv.Embed(KiT_TraceView).(*TraceView)
+			tf := send.(*gi.TextField)
+			svv.Filter = tf.Text()
+			svv.ShowTraces()
+		}
+	})
+	tb.AddAction(gi.ActOpts{Label: "Export...", Icon: "file-save", Tooltip: "save the trace events being shown to a JSON or CSV file"}, sv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			svv, _ := recv.Embed(KiT_TraceView).(*TraceView)
+			svv.ExportDialog()
+		})
+	tb.AddAction(gi.ActOpts{Label: "Clear", Icon: "minus", Tooltip: "clear the trace log"}, dv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			dvv := recv.Embed(KiT_DebugView).(*DebugView)
+			dvv.ClearTraces()
+		})
+	gi.AddNewLabel(tb, "summary", "")
+}
+
+// TraceBar returns the toolbar
+func (sv *TraceView) TraceBar() *gi.ToolBar {
+	return sv.ChildByName("tracebar", 0).(*gi.ToolBar)
+}
+
+// TableView returns the tableview
+func (sv *TraceView) TableView() *giv.TableView {
+	return sv.ChildByName("traces", 1).(*giv.TableView)
+}
+
+// ShowTraces triggers update of view of State.Traces, filtering them
+func (sv *TraceView) ShowTraces() {
+	dv := sv.DebugVw()
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	sv.Events = nil
+	for _, te := range dv.State.Traces {
+		if gidebug.TraceMatches(te, sv.Filter) {
+			sv.Events = append(sv.Events, te)
+		}
+	}
+	lbl := sv.TraceBar().ChildByName("summary", 5).(*gi.Label)
+	lbl.SetText(fmt.Sprintf("%d of %d events", len(sv.Events), len(dv.State.Traces)))
+	tv := sv.TableView()
+	tv.SetInactive()
+	tv.SetSlice(&sv.Events)
+	sv.UpdateEnd(updt)
+}
+
+// Export saves the trace events being shown to given file, as JSON if it
+// has a .json extension, and otherwise as CSV
+func (sv *TraceView) Export(fname gi.FileName) {
+	if err := gidebug.SaveTraces(sv.Events, string(fname)); err != nil {
+		log.Println(err)
+	}
+}
+
+// ExportDialog prompts for a file to export the trace events to
+func (sv *TraceView) ExportDialog() {
+	giv.FileViewDialog(sv.Viewport, "", ".json,.csv", giv.DlgOpts{Title: "Export Trace", Prompt: "File to save the trace events to -- .json for JSON, otherwise CSV"}, nil,
+		sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.DialogAccepted) {
+				svv, _ := recv.Embed(KiT_TraceView).(*TraceView)
+				dlg, _ := send.(*gi.Dialog)
+				svv.Export(gi.FileName(giv.FileViewDialogValue(dlg)))
+			}
+		})
+}
+
+// TraceViewProps are style properties for DebugView
+var TraceViewProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
 //////////////////////////////////////////////////////////////////////////////////////
 //  TaskView
 
-// TaskView is a view of the threads
+// TaskView is a view of the tasks, grouped and filtered, so that large
+// numbers of tasks (e.g., goroutines) can be navigated
 type TaskView struct {
 	gi.Layout
+	GroupBy     gidebug.TaskGroupings `desc:"how to group the tasks"`
+	Filter      string                `desc:"only tasks whose function, file, start or launch location contains this string are shown"`
+	BlockedOnly bool                  `desc:"only show tasks that are blocked, as of the last Blocked update"`
+	Groups      []*gidebug.TaskGroup  `desc:"the current groups of tasks"`
+	GroupIdx    int                   `desc:"index of the currently selected group"`
+	Tasks       []*gidebug.Task       `desc:"tasks in the currently selected group"`
 }
 
 var KiT_TaskView = kit.Types.AddType(&TaskView{}, TaskViewProps)
@@ -1158,44 +2386,184 @@ func (sv *TaskView) DebugVw() *DebugView {
 func (sv *TaskView) Config(dv *DebugView) {
 	sv.Lay = gi.LayoutVert
 	config := kit.TypeAndNameList{}
-	config.Add(giv.KiT_TableView, "tasks")
+	config.Add(gi.KiT_ToolBar, "taskbar")
+	config.Add(gi.KiT_SplitView, "splitview")
 	mods, updt := sv.ConfigChildren(config)
-	tv := sv.TableView()
 	if mods {
-		tv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		sv.ConfigTaskBar(dv)
+		split := sv.SplitView()
+		split.Dim = mat32.X
+		gtv := giv.AddNewTableView(split, "groups")
+		ttv := giv.AddNewTableView(split, "tasks")
+		split.SetSplits(.3, .7)
+		gtv.WidgetSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.WidgetSelected) {
+				svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
+				gtvv, _ := send.(*giv.TableView)
+				svv.SelectGroup(gtvv.SelectedIdx)
+			}
+		})
+		ttv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			if sig == int64(giv.SliceViewDoubleClicked) {
+				svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
 				idx := data.(int)
-				if dv.Dbg != nil && dv.Dbg.HasTasks() {
-					dv.SetThreadIdx(idx)
+				if idx >= 0 && idx < len(svv.Tasks) && dv.Dbg != nil && dv.Dbg.HasTasks() {
+					dv.SwitchTask(svv.Tasks[idx].ID)
 				}
 			}
 		})
+		ttv.StyleFunc = func(tv *giv.TableView, slice interface{}, widg gi.Node2D, row, col int, vv giv.ValueView) {
+			tks, ok := slice.([]*gidebug.Task)
+			if !ok || row >= len(tks) {
+				return
+			}
+			wi := widg.AsNode2D()
+			if tks[row].Blocked == "" {
+				if _, err := wi.PropTry("background-color"); err == nil {
+					wi.DeleteProp("background-color")
+					wi.SetFullReRender()
+				}
+				return
+			}
+			wi.SetProp("background-color", DebugBreakColors[DebugPCCurrent])
+			wi.SetFullReRender()
+		}
 	} else {
 		updt = sv.UpdateStart()
 	}
+	gtv := sv.GroupsView()
+	gtv.SetStretchMax()
+	gtv.SetInactive()
+	gtv.SetSlice(&sv.Groups)
+	tv := sv.TableView()
 	tv.SetStretchMax()
 	tv.SetInactive()
-	tv.SetSlice(&dv.State.Tasks)
+	tv.SetSlice(&sv.Tasks)
 	sv.UpdateEnd(updt)
 }
 
-// TableView returns the tableview
+// ConfigTaskBar configures the toolbar with the grouping and filtering
+// controls
+func (sv *TaskView) ConfigTaskBar(dv *DebugView) {
+	tb := sv.TaskBar()
+	tb.SetStretchMaxWidth()
+	gi.AddNewLabel(tb, "group-lbl", "Group:")
+	gcb := tb.AddNewChild(gi.KiT_ComboBox, "group").(*gi.ComboBox)
+	gcb.Tooltip = "how to group the tasks: by the function they are in, the function they started in, or where they were launched from"
+	gcb.ItemsFromEnum(gidebug.KiT_TaskGroupings, false, 0)
+	gcb.SetCurIndex(int(sv.GroupBy))
+	gcb.ComboSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
+		cb := send.(*gi.ComboBox)
+		svv.GroupBy = gidebug.TaskGroupings(cb.CurIndex)
+		svv.ShowTasks()
+	})
+	gi.AddNewLabel(tb, "filter-lbl", "Filter:")
+	ftf := gi.AddNewTextField(tb, "filter")
+	ftf.Tooltip = "only show tasks whose function, file, start or launch location, or blocked reason contains this string -- hit enter to update"
+	ftf.SetMinPrefWidth(units.NewCh(30))
+	ftf.TextFieldSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) || sig == int64(gi.TextFieldCleared) {
+			svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
+			tf := send.(*gi.TextField)
+			svv.Filter = tf.Text()
+			svv.ShowTasks()
+		}
+	})
+	bo := tb.AddNewChild(gi.KiT_CheckBox, "blocked-only").(*gi.CheckBox)
+	bo.SetText("Blocked Only")
+	bo.Tooltip = "only show tasks that are blocked, as of the last Blocked update"
+	bo.ButtonSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
+			cb := send.(*gi.CheckBox)
+			svv.BlockedOnly = cb.IsChecked()
+			svv.ShowTasks()
+		}
+	})
+	tb.AddAction(gi.ActOpts{Label: "Blocked", Icon: "update", Tooltip: "find the tasks that are blocked waiting on channels, mutexes etc -- gets the stack of every task, so it can take a while for large numbers of tasks", UpdateFunc: dv.ActionActivate}, sv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			svv, _ := recv.Embed(KiT_TaskView).(*TaskView)
+			svv.DebugVw().UpdateBlocked()
+			svv.ShowTasks()
+		})
+	gi.AddNewLabel(tb, "summary", "")
+}
+
+// TaskBar returns the toolbar
+func (sv *TaskView) TaskBar() *gi.ToolBar {
+	return sv.ChildByName("taskbar", 0).(*gi.ToolBar)
+}
+
+// SplitView returns the splitview
+func (sv *TaskView) SplitView() *gi.SplitView {
+	return sv.ChildByName("splitview", 1).(*gi.SplitView)
+}
+
+// GroupsView returns the tableview of groups
+func (sv *TaskView) GroupsView() *giv.TableView {
+	return sv.SplitView().ChildByName("groups", 0).(*giv.TableView)
+}
+
+// TableView returns the tableview of tasks
 func (sv *TaskView) TableView() *giv.TableView {
-	return sv.ChildByName("tasks", 0).(*giv.TableView)
+	return sv.SplitView().ChildByName("tasks", 1).(*giv.TableView)
 }
 
-// ShowTasks triggers update of view of State.Tasks
-func (sv *TaskView) ShowTasks() {
+// SelectGroup shows the tasks of the group at given index
+func (sv *TaskView) SelectGroup(idx int) {
+	if idx < 0 || idx >= len(sv.Groups) {
+		return
+	}
+	sv.GroupIdx = idx
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	sv.Tasks = sv.Groups[idx].Tasks
 	tv := sv.TableView()
+	tv.SelectedIdx = -1
+	tv.SetSlice(&sv.Tasks)
+	sv.UpdateEnd(updt)
+}
+
+// ShowTasks triggers update of view of State.Tasks, regrouping and
+// filtering them, and selecting the current task
+func (sv *TaskView) ShowTasks() {
 	dv := sv.DebugVw()
 	updt := sv.UpdateStart()
 	sv.SetFullReRender()
-	tv.SetInactive()
-	_, idx := gidebug.TaskByID(dv.State.Tasks, dv.State.CurTask)
-	if idx >= 0 {
-		tv.SelectedIdx = idx
+	gkey := ""
+	if sv.GroupIdx >= 0 && sv.GroupIdx < len(sv.Groups) {
+		gkey = sv.Groups[sv.GroupIdx].Key
+	}
+	sv.Groups = gidebug.GroupTasks(dv.State.Tasks, sv.GroupBy, sv.Filter, sv.BlockedOnly)
+	sv.GroupIdx = 0
+	ctk, _ := gidebug.TaskByID(dv.State.Tasks, dv.State.CurTask)
+	ckey := ""
+	if ctk != nil {
+		ckey = sv.GroupBy.GroupKey(ctk)
+	}
+	nblk := 0
+	for i, tg := range sv.Groups {
+		nblk += tg.Blocked
+		if tg.Key == gkey || (gkey == "" && tg.Key == ckey) {
+			sv.GroupIdx = i
+		}
+	}
+	sv.Tasks = nil
+	if len(sv.Groups) > 0 {
+		sv.Tasks = sv.Groups[sv.GroupIdx].Tasks
 	}
-	tv.SetSlice(&dv.State.Tasks)
+	gtv := sv.GroupsView()
+	gtv.SetInactive()
+	gtv.SelectedIdx = sv.GroupIdx
+	gtv.SetSlice(&sv.Groups)
+	tv := sv.TableView()
+	tv.SetInactive()
+	_, idx := gidebug.TaskByID(sv.Tasks, dv.State.CurTask)
+	tv.SelectedIdx = idx
+	tv.SetSlice(&sv.Tasks)
+	lbl := sv.TaskBar().ChildByName("summary", 6).(*gi.Label)
+	lbl.SetText(fmt.Sprintf("tasks: %d  groups: %d  blocked: %d", len(dv.State.Tasks), len(sv.Groups), nblk))
 	sv.UpdateEnd(updt)
 }
 
@@ -1212,7 +2580,9 @@ var TaskViewProps = ki.Props{
 // VarsView is a view of the variables
 type VarsView struct {
 	gi.Layout
-	GlobalVars bool `desc:"if true, this is global vars, not local ones"`
+	GlobalVars  bool                `desc:"if true, this is global vars, not local ones"`
+	OnlyChanged bool                `desc:"if true, only show the variables that have changed since the previous stop"`
+	Vars        []*gidebug.Variable `desc:"the variables being shown, including those removed since the previous stop"`
 }
 
 var KiT_VarsView = kit.Types.AddType(&VarsView{}, VarsViewProps)
@@ -1226,52 +2596,92 @@ func (sv *VarsView) Config(dv *DebugView, globalVars bool) {
 	sv.Lay = gi.LayoutVert
 	sv.GlobalVars = globalVars
 	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "varbar")
 	config.Add(giv.KiT_TableView, "vars")
 	mods, updt := sv.ConfigChildren(config)
 	tv := sv.TableView()
 	if mods {
+		vb := sv.VarBar()
+		vb.SetStretchMaxWidth()
+		oc := vb.AddNewChild(gi.KiT_CheckBox, "only-changed").(*gi.CheckBox)
+		oc.SetText("Only Changed")
+		oc.Tooltip = "only show the variables that have been added, changed or removed since the previous stop"
+		oc.ButtonSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.ButtonToggled) {
+				svv, _ := recv.Embed(KiT_VarsView).(*VarsView)
+				cb := send.(*gi.CheckBox)
+				svv.OnlyChanged = cb.IsChecked()
+				svv.ShowVars()
+			}
+		})
 		tv.SliceViewSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			if sig == int64(giv.SliceViewDoubleClicked) {
 				idx := data.(int)
-				if sv.GlobalVars {
-					vr := dv.State.GlobalVars[idx]
-					dv.ShowVar(vr.Nm)
+				if idx < 0 || idx >= len(sv.Vars) {
+					return
+				}
+				vr := sv.Vars[idx]
+				if vr.Change == gidebug.VarRemoved {
+					VarViewDialog(vr, "removed since the previous stop", dv)
 				} else {
-					vr := dv.State.Vars[idx]
 					dv.ShowVar(vr.Nm)
 				}
 			}
 		})
+		tv.StyleFunc = func(tv *giv.TableView, slice interface{}, widg gi.Node2D, row, col int, vv giv.ValueView) {
+			if row < 0 || row >= len(sv.Vars) {
+				return
+			}
+			if SetVarChangeColor(widg, sv.Vars[row].Change) {
+				widg.AsNode2D().SetFullReRender()
+			}
+		}
 	} else {
 		updt = sv.UpdateStart()
 	}
+	sv.ConfigVars()
 	tv.SetStretchMax()
 	tv.SetInactive()
-	if sv.GlobalVars {
-		tv.SetSlice(&dv.State.GlobalVars)
-	} else {
-		tv.SetSlice(&dv.State.Vars)
-	}
+	tv.SetSlice(&sv.Vars)
 	sv.UpdateEnd(updt)
 }
 
+// VarBar returns the vars toolbar
+func (sv *VarsView) VarBar() *gi.ToolBar {
+	return sv.ChildByName("varbar", 0).(*gi.ToolBar)
+}
+
 // TableView returns the tableview
 func (sv *VarsView) TableView() *giv.TableView {
-	return sv.ChildByName("vars", 0).(*giv.TableView)
+	return sv.ChildByName("vars", 1).(*giv.TableView)
+}
+
+// ConfigVars sets the variables to show from the State, adding those
+// removed since the previous stop, and filtering by OnlyChanged
+func (sv *VarsView) ConfigVars() {
+	dv := sv.DebugVw()
+	vrs, rem := dv.State.Vars, dv.State.RemovedVars
+	if sv.GlobalVars {
+		vrs, rem = dv.State.GlobalVars, dv.State.RemovedGlobalVars
+	}
+	sv.Vars = make([]*gidebug.Variable, 0, len(vrs)+len(rem))
+	for _, vr := range vrs {
+		if sv.OnlyChanged && vr.Change == gidebug.VarSame {
+			continue
+		}
+		sv.Vars = append(sv.Vars, vr)
+	}
+	sv.Vars = append(sv.Vars, rem...)
 }
 
 // ShowVars triggers update of view of State.Vars
 func (sv *VarsView) ShowVars() {
 	tv := sv.TableView()
-	dv := sv.DebugVw()
 	updt := sv.UpdateStart()
 	sv.SetFullReRender()
+	sv.ConfigVars()
 	tv.SetInactive()
-	if sv.GlobalVars {
-		tv.SetSlice(&dv.State.GlobalVars)
-	} else {
-		tv.SetSlice(&dv.State.Vars)
-	}
+	tv.SetSlice(&sv.Vars)
 	sv.UpdateEnd(updt)
 }
 
@@ -1324,12 +2734,12 @@ func (vv *VarView) Config() {
 	vv.SetProp("spacing", gi.StdDialogVSpaceUnits)
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_Label, "frame-info")
-	// config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_ToolBar, "toolbar")
 	config.Add(gi.KiT_SplitView, "splitview")
 	mods, updt := vv.ConfigChildren(config)
 	vv.SetFrameInfo(vv.FrameInfo)
 	vv.ConfigSplitView()
-	// vv.ConfigToolBar()
+	vv.ConfigToolBar()
 	if mods {
 		vv.UpdateEnd(updt)
 	}
@@ -1351,10 +2761,10 @@ func (vv *VarView) StructView() *giv.StructView {
 	return vv.SplitView().Child(1).(*giv.StructView)
 }
 
-// // ToolBar returns the toolbar widget
-// func (vv *VarView) ToolBar() *gi.ToolBar {
-// 	return vv.ChildByName("toolbar", 0).(*gi.ToolBar)
-// }
+// ToolBar returns the toolbar widget
+func (vv *VarView) ToolBar() *gi.ToolBar {
+	return vv.ChildByName("toolbar", 1).(*gi.ToolBar)
+}
 
 // SetFrameInfo sets the frame info
 func (vv *VarView) SetFrameInfo(finfo string) {
@@ -1362,15 +2772,31 @@ func (vv *VarView) SetFrameInfo(finfo string) {
 	lab.Text = finfo
 }
 
-// // ConfigToolBar adds a VarView toolbar.
-// func (vv *VarView) ConfigToolBar() {
-// 	tb := vv.ToolBar()
-// 	if tb != nil && tb.HasChildren() {
-// 		return
-// 	}
-// 	tb.SetStretchMaxWidth()
-// 	giv.ToolBarView(vv, vv.Viewport, tb)
-// }
+// SelVar returns the variable currently selected in the tree view, or the
+// main Var if none
+func (vv *VarView) SelVar() *gidebug.Variable {
+	if vr, ok := vv.StructView().Struct.(*gidebug.Variable); ok {
+		return vr
+	}
+	return vv.Var
+}
+
+// ConfigToolBar adds a VarView toolbar.
+func (vv *VarView) ConfigToolBar() {
+	tb := vv.ToolBar()
+	if tb != nil && tb.HasChildren() {
+		return
+	}
+	tb.SetStretchMaxWidth()
+	tb.AddAction(gi.ActOpts{Label: "Memory", Icon: "search", Tooltip: "examine the memory at the address of the selected variable, in the Memory tab of the debugger", UpdateFunc: func(act *gi.Action) {
+		act.SetActiveStateUpdt(vv.DbgView != nil && vv.DbgView.DbgIsAvail() && vv.SelVar().Addr != 0)
+	}}, vv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			vve, _ := recv.Embed(KiT_VarView).(*VarView)
+			vr := vve.SelVar()
+			vve.DbgView.ExamineMemory(uint64(vr.Addr), 0)
+		})
+}
 
 // ConfigSplitView configures the SplitView.
 func (vv *VarView) ConfigSplitView() {
@@ -1394,6 +2820,7 @@ func (vv *VarView) ConfigSplitView() {
 			tvn, _ := data.(ki.Ki).Embed(giv.KiT_TreeView).(*giv.TreeView)
 			if sig == int64(giv.TreeViewSelected) {
 				svr.SetStruct(tvn.SrcNode)
+				vve.ToolBar().UpdateActions()
 			}
 		})
 		split.SetSplits(.3, .7)
@@ -1405,10 +2832,34 @@ func (vv *VarView) ConfigSplitView() {
 	sv.SetStruct(vv.Var)
 }
 
-// func (ge *VarView) Render2D() {
-// 	// ge.ToolBar().UpdateActions()
-// 	ge.Frame.Render2D()
-// }
+// StyleChanges sets the background color of the nodes in the tree view
+// according to how their variables have changed since the previous stop
+func (vv *VarView) StyleChanges() {
+	if vv.Var == nil || !vv.HasChildren() {
+		return
+	}
+	vv.TreeView().FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d interface{}) bool {
+		tvn, ok := k.Embed(giv.KiT_TreeView).(*giv.TreeView)
+		if !ok || tvn.SrcNode == nil {
+			return ki.Continue
+		}
+		vr, ok := tvn.SrcNode.Embed(gidebug.KiT_Variable).(*gidebug.Variable)
+		if !ok {
+			return ki.Continue
+		}
+		tvn.SetProp("no-templates", true)
+		if SetVarChangeColor(tvn, vr.Change) {
+			tvn.Style2D()
+		}
+		return ki.Continue
+	})
+}
+
+// Render2D highlights the changed variables and renders the view
+func (vv *VarView) Render2D() {
+	vv.StyleChanges()
+	vv.Frame.Render2D()
+}
 
 var VarViewProps = ki.Props{
 	"EnumType:Flag":    gi.KiT_NodeFlags,
@@ -1450,8 +2901,8 @@ func VarViewDialog(vr *gidebug.Variable, frinfo string, dbgVw *DebugView) *VarVi
 	vv.DbgView = dbgVw
 	vv.SetVar(vr, frinfo)
 
-	// tb := vv.ToolBar()
-	// tb.UpdateActions()
+	tb := vv.ToolBar()
+	tb.UpdateActions()
 
 	vp.UpdateEndNoSig(updt)
 	win.GoStartEventLoop() // in a separate goroutine
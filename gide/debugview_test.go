@@ -0,0 +1,44 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"testing"
+
+	"github.com/goki/gide/gidebug"
+)
+
+func TestDebugInlineVals(t *testing.T) {
+	lns := []string{
+		"func f(n int) int {",
+		"	a := 1",
+		"	b, c := 2, 3",
+		"	if a == n {",
+		"		a += b",
+		"	}",
+		"	c++",
+		"	d := a + c",
+		"	return d",
+		"}",
+	}
+	mkvar := func(nm, val string, decl int) *gidebug.Variable {
+		vr := &gidebug.Variable{}
+		vr.InitName(vr, nm)
+		vr.Value = val
+		vr.Loc.Line = decl
+		return vr
+	}
+	vrs := []*gidebug.Variable{mkvar("n", "3", 1), mkvar("a", "3", 2), mkvar("b", "2", 3), mkvar("c", "4", 3), mkvar("d", "0", 8)}
+	ivs := DebugInlineVals(vrs, lns, 8)
+	trg := map[int]string{0: "n = 3", 4: "a = 3", 2: "b = 2", 6: "c = 4"}
+	if len(ivs) != len(trg) {
+		t.Errorf("inline vals should have been: %v  was: %v\n", trg, ivs)
+	}
+	for ln, txt := range trg {
+		if ivs[ln] != txt {
+			t.Errorf("inline val at line %d should have been: %v  was: %v\n", ln, txt, ivs[ln])
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+)
+
+// FileSearchWorkers is the number of concurrent workers used to search
+// files -- if 0, runtime.NumCPU() is used.
+var FileSearchWorkers = 0
+
+// FileSearchStats are statistics about a completed (or canceled) search
+type FileSearchStats struct {
+	Files      int           `desc:"number of files scanned"`
+	MatchFiles int           `desc:"number of files with at least one match"`
+	Matches    int           `desc:"total number of matches"`
+	Skipped    int           `desc:"number of files skipped without reading, using the trigram index"`
+	Time       time.Duration `desc:"total elapsed time of search"`
+	Canceled   bool          `desc:"search was canceled before it completed"`
+}
+
+// String returns a summary of the stats suitable for the status bar
+func (st *FileSearchStats) String() string {
+	cs := ""
+	if st.Canceled {
+		cs = " (canceled)"
+	}
+	if st.Skipped > 0 {
+		cs = fmt.Sprintf(", %d skipped by index", st.Skipped) + cs
+	}
+	return fmt.Sprintf("%d matches in %d files, %d files scanned in %d ms%s", st.Matches, st.MatchFiles, st.Files, st.Time.Milliseconds(), cs)
+}
+
+// fileSearchJob is one file to be searched -- Node is nil for files
+// found directly on disk, in which case they still need to be filtered.
+type fileSearchJob struct {
+	Node  *giv.FileNode
+	FPath string
+}
+
+// FileSearch is a concurrent, cancelable search of the files in a file tree,
+// which delivers results incrementally as each file is searched.  Use
+// NewFileSearch to create, then Run to start, reading results from the
+// returned channel until it is closed, at which point Stats are valid.
+type FileSearch struct {
+	Start     *giv.FileNode   `desc:"node to start searching from"`
+	Params    FindParams      `desc:"copy of the find params at the start of the search"`
+	ActiveDir string          `desc:"directory of the current active file, for FindLocDir"`
+	Filter    *FindFilter     `desc:"file filter based on params"`
+	Re        *regexp.Regexp  `desc:"compiled regexp if using regexp or multi-line mode"`
+	Index     *TrigramIndex   `desc:"trigram index used to skip files that cannot match -- nil if not using"`
+	Query     *TrigramQuery   `desc:"trigram query for the find string, if using Index"`
+	Pat       *StructPattern  `desc:"parsed structural search pattern, if using Structural"`
+	Stats     FileSearchStats `desc:"stats of the search -- only valid after results channel is closed"`
+	find      []byte
+	files     int64
+	skipped   int64
+	cancel    chan struct{}
+	cancelOne sync.Once
+	done      chan struct{}
+	stTime    time.Time
+}
+
+// NewFileSearch returns a new FileSearch starting at given node, using
+// given params (which are copied), and active directory of the current file.
+// Returns an error if the regexp does not compile.
+func NewFileSearch(start *giv.FileNode, fp *FindParams, activeDir string) (*FileSearch, error) {
+	fs := &FileSearch{Start: start, Params: *fp, ActiveDir: activeDir}
+	re, err := FindRegexp(fp)
+	if err != nil {
+		return nil, err
+	}
+	fs.Re = re
+	if fp.Structural {
+		fs.Pat, err = ParseStructPattern(fp.Find)
+		if err != nil {
+			return nil, err
+		}
+	}
+	fs.find = []byte(fp.Find)
+	fs.Filter = NewFindFilter(string(start.FRoot.FPath), fp)
+	if fp.Index && !fp.Structural {
+		fs.Index = TrigramIndexForRoot(string(start.FRoot.FPath))
+		if fs.Index != nil {
+			fs.Query = NewTrigramQuery(fp.Find, fp.Regexp)
+		}
+	}
+	fs.cancel = make(chan struct{})
+	fs.done = make(chan struct{})
+	return fs, nil
+}
+
+// Run starts the search, returning the channel on which results are sent,
+// in the order in which files finish searching.  The file tree itself is
+// traversed here, so Run must be called from the same goroutine that
+// otherwise updates the tree -- all further work happens in other goroutines.
+func (fs *FileSearch) Run() <-chan FileSearchResults {
+	fs.stTime = time.Now()
+	res := make(chan FileSearchResults, 64)
+	if len(fs.find) == 0 {
+		close(res)
+		close(fs.done)
+		return res
+	}
+	jobs := make(chan fileSearchJob, 256)
+	nodes, dirs := fs.TreeJobs()
+	go func() {
+		defer close(jobs)
+		for _, jb := range nodes {
+			if !fs.sendJob(jobs, jb) {
+				return
+			}
+		}
+		for _, dir := range dirs {
+			if !fs.WalkDisk(dir, jobs) {
+				return
+			}
+		}
+	}()
+	nw := FileSearchWorkers
+	if nw <= 0 {
+		nw = runtime.NumCPU()
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := 0; i < nw; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for jb := range jobs {
+				if fs.IsCanceled() {
+					continue // drain
+				}
+				r, ok := fs.SearchJob(jb)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				fs.Stats.MatchFiles++
+				fs.Stats.Matches += r.Count
+				mu.Unlock()
+				select {
+				case res <- r:
+				case <-fs.cancel:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		fs.Stats.Files = int(atomic.LoadInt64(&fs.files))
+		fs.Stats.Skipped = int(atomic.LoadInt64(&fs.skipped))
+		if fs.Index != nil {
+			if err := fs.Index.Save(); err != nil {
+				log.Println(err)
+			}
+		}
+		fs.Stats.Time = time.Since(fs.stTime)
+		fs.Stats.Canceled = fs.IsCanceled()
+		close(res)
+		close(fs.done)
+	}()
+	return res
+}
+
+// sendJob sends the job unless canceled, returning false if canceled
+func (fs *FileSearch) sendJob(jobs chan<- fileSearchJob, jb fileSearchJob) bool {
+	select {
+	case jobs <- jb:
+		return true
+	case <-fs.cancel:
+		return false
+	}
+}
+
+// Cancel cancels the search -- results already sent remain valid, and the
+// results channel is closed as soon as the workers stop.
+func (fs *FileSearch) Cancel() {
+	fs.cancelOne.Do(func() { close(fs.cancel) })
+}
+
+// IsCanceled returns true if the search has been canceled
+func (fs *FileSearch) IsCanceled() bool {
+	select {
+	case <-fs.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDone returns true if the search has finished, whether or not it was canceled
+func (fs *FileSearch) IsDone() bool {
+	select {
+	case <-fs.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait waits for the search to finish -- the results channel must still be drained
+func (fs *FileSearch) Wait() {
+	<-fs.done
+}
+
+// TreeJobs traverses the file tree from Start, returning the file nodes to
+// search, and the paths of closed directories to search on disk (only if
+// ClosedDirs is set).
+func (fs *FileSearch) TreeJobs() ([]fileSearchJob, []string) {
+	fp := &fs.Params
+	ff := fs.Filter
+	start := fs.Start
+	var nodes []fileSearchJob
+	var dirs []string
+	start.FuncDownMeFirst(0, start, func(k ki.Ki, level int, d interface{}) bool {
+		sfn := k.Embed(giv.KiT_FileNode).(*giv.FileNode)
+		if sfn.IsDir() {
+			if sfn.This() != start.This() && ff.SkipDir(string(sfn.FPath)) {
+				return ki.Break
+			}
+			if !sfn.IsOpen() {
+				if fp.ClosedDirs && fp.Loc != FindLocDir {
+					dirs = append(dirs, string(sfn.FPath))
+				}
+				return ki.Break // don't go down into closed directories!
+			}
+			return ki.Continue
+		}
+		if sfn.IsExec() || sfn.Info.Kind == "octet-stream" || sfn.IsAutoSave() {
+			return ki.Continue
+		}
+		if strings.HasSuffix(sfn.Nm, ".gide") || strings.HasSuffix(sfn.Nm, ".gidx") { // exclude self
+			return ki.Continue
+		}
+		if ff.SkipFile(string(sfn.FPath), sfn.Info.Sup) {
+			return ki.Continue
+		}
+		if fp.Loc == FindLocDir {
+			cdir, _ := filepath.Split(string(sfn.FPath))
+			if fs.ActiveDir != cdir {
+				return ki.Continue
+			}
+		} else if fp.Loc == FindLocNotTop {
+			if level == 1 {
+				return ki.Continue
+			}
+		}
+		nodes = append(nodes, fileSearchJob{Node: sfn, FPath: string(sfn.FPath)})
+		return ki.Continue
+	})
+	return nodes, dirs
+}
+
+// WalkDisk sends jobs for all the files within given directory directly on
+// disk, without creating any file tree nodes -- used for directories that
+// are closed in the file tree.  Returns false if canceled.
+func (fs *FileSearch) WalkDisk(dir string, jobs chan<- fileSearchJob) bool {
+	ff := fs.Filter
+	err := filepath.Walk(dir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // ignore
+		}
+		if info.IsDir() {
+			if fpath == dir {
+				return nil
+			}
+			for _, sd := range FindSkipDirs {
+				if info.Name() == sd {
+					return filepath.SkipDir
+				}
+			}
+			if ff.SkipDir(fpath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || strings.HasSuffix(fpath, ".gide") || strings.HasSuffix(fpath, ".gidx") {
+			return nil
+		}
+		if !fs.sendJob(jobs, fileSearchJob{FPath: fpath}) {
+			return errFileSearchCanceled
+		}
+		return nil
+	})
+	return err == nil
+}
+
+var errFileSearchCanceled = fmt.Errorf("file search canceled")
+
+// SearchJob searches one file, returning the results and true if there
+// were any matches.  Files found on disk are filtered here, as getting
+// their file info requires reading the file.
+func (fs *FileSearch) SearchJob(jb fileSearchJob) (FileSearchResults, bool) {
+	var cnt int
+	var matches []textbuf.Match
+	sfn := jb.Node
+	if sfn == nil {
+		fi, err := giv.NewFileInfo(jb.FPath)
+		if err != nil {
+			return FileSearchResults{}, false
+		}
+		if fi.IsExec() || fi.Kind == "octet-stream" || (strings.HasPrefix(fi.Name, "#") && strings.HasSuffix(fi.Name, "#")) {
+			return FileSearchResults{}, false
+		}
+		if fs.Filter.SkipFile(jb.FPath, fi.Sup) {
+			return FileSearchResults{}, false
+		}
+	}
+	inBuf := sfn != nil && sfn.IsOpen() && sfn.Buf != nil
+	if !inBuf && fs.Index != nil && fs.IndexSkip(jb.FPath) {
+		atomic.AddInt64(&fs.skipped, 1)
+		return FileSearchResults{}, false
+	}
+	if fs.Pat != nil && filepath.Ext(jb.FPath) != ".go" {
+		return FileSearchResults{}, false
+	}
+	atomic.AddInt64(&fs.files, 1)
+	if fs.Pat != nil {
+		if inBuf {
+			cnt, matches = SearchBufStruct(sfn.Buf, fs.Pat)
+		} else {
+			cnt, matches = SearchFileStruct(jb.FPath, fs.Pat)
+		}
+	} else if fs.Params.MultiLine {
+		if inBuf {
+			cnt, matches = SearchBufMultiLine(sfn.Buf, fs.Re)
+		} else {
+			cnt, matches = SearchFileMultiLine(jb.FPath, fs.Re)
+		}
+	} else if inBuf {
+		if fs.Re != nil {
+			cnt, matches = sfn.Buf.SearchRegexp(fs.Re)
+		} else {
+			cnt, matches = sfn.Buf.Search(fs.find, fs.Params.IgnoreCase, false)
+		}
+	} else {
+		cnt, matches = FileSearchPath(jb.FPath, fs.find, fs.Params.IgnoreCase, fs.Re)
+	}
+	if cnt == 0 {
+		return FileSearchResults{}, false
+	}
+	return FileSearchResults{Node: sfn, FPath: jb.FPath, Count: cnt, Matches: matches}, true
+}
+
+// IndexSkip returns true if the trigram index shows that the file at given
+// path cannot match.  Files that are not yet indexed, or have changed since,
+// are (re)indexed here so that they can be skipped next time.
+func (fs *FileSearch) IndexSkip(fpath string) bool {
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return false
+	}
+	tf := fs.Index.Entry(fpath, info)
+	if tf == nil {
+		tf = fs.Index.UpdateFile(fpath)
+		if tf == nil {
+			return false
+		}
+	}
+	if fs.Query.IsEmpty() {
+		return false
+	}
+	return !tf.HasAll(fs.Query.Tris)
+}
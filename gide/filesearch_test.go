@@ -0,0 +1,158 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+)
+
+// genSearchTree generates a tree of ndirs directories with nfiles Go files
+// each, with every file containing nmatch lines that match "needle"
+func genSearchTree(tb testing.TB, ndirs, nfiles, nmatch int) string {
+	root, err := ioutil.TempDir("", "gide-search")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var sb strings.Builder
+	sb.WriteString("package gen\n\n")
+	for i := 0; i < 200; i++ {
+		if i < nmatch {
+			fmt.Fprintf(&sb, "var needle%d = %d // haystack line\n", i, i)
+		} else {
+			fmt.Fprintf(&sb, "var hay%d = %d // haystack line\n", i, i)
+		}
+	}
+	src := []byte(sb.String())
+	for d := 0; d < ndirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%d", d))
+		os.MkdirAll(dir, 0755)
+		for f := 0; f < nfiles; f++ {
+			ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.go", f)), src, 0644)
+		}
+	}
+	return root
+}
+
+// openSearchTree returns a FileTree for given root, built directly from the
+// directory contents, with all dirs open if openAll, else closed
+func openSearchTree(tb testing.TB, root string, openAll bool) *giv.FileTree {
+	ft := &giv.FileTree{}
+	ft.InitName(ft, "filetree")
+	ft.FRoot = ft
+	ft.FPath = gi.FileName(root)
+	ft.Info.InitFile(root)
+	ft.SetOpen()
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	for _, di := range dirs {
+		dn := ft.AddNewChild(giv.KiT_FileNode, di.Name()).(*giv.FileNode)
+		dn.FRoot = ft
+		dn.FPath = gi.FileName(filepath.Join(root, di.Name()))
+		dn.Info.InitFile(string(dn.FPath))
+		if !openAll {
+			continue
+		}
+		dn.SetOpen()
+		files, _ := ioutil.ReadDir(string(dn.FPath))
+		for _, fi := range files {
+			fn := dn.AddNewChild(giv.KiT_FileNode, fi.Name()).(*giv.FileNode)
+			fn.FRoot = ft
+			fn.FPath = gi.FileName(filepath.Join(string(dn.FPath), fi.Name()))
+			fn.Info.InitFile(string(fn.FPath))
+		}
+	}
+	return ft
+}
+
+func TestFileTreeSearch(t *testing.T) {
+	root := genSearchTree(t, 4, 5, 3)
+	defer os.RemoveAll(root)
+
+	for _, closed := range []bool{false, true} {
+		ft := openSearchTree(t, root, !closed)
+		fp := &FindParams{Find: "needle", ClosedDirs: closed}
+		fs, err := NewFileSearch(&ft.FileNode, fp, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []FileSearchResults
+		for r := range fs.Run() {
+			res = append(res, r)
+		}
+		if len(res) != 20 {
+			t.Errorf("closed: %v number of files should have been: %v  was: %v\n", closed, 20, len(res))
+		}
+		if fs.Stats.Matches != 60 || fs.Stats.Files != 20 {
+			t.Errorf("closed: %v stats should have been: 60 matches, 20 files  was: %v\n", closed, fs.Stats.String())
+		}
+	}
+
+	// ignore files are read lazily by both the disk walk and the workers,
+	// so run several workers in parallel even on one cpu (test with -race)
+	defer func(nw int) { FileSearchWorkers = nw }(FileSearchWorkers)
+	FileSearchWorkers = 4
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	ioutil.WriteFile(filepath.Join(root, ".gitignore"), []byte("file0.go\n"), 0644)
+	ioutil.WriteFile(filepath.Join(root, "dir1", ".ignore"), []byte("file1.go\n"), 0644)
+	defer os.Remove(filepath.Join(root, ".gitignore"))
+	for _, closed := range []bool{false, true} {
+		ft := openSearchTree(t, root, !closed)
+		fp := &FindParams{Find: "needle", ClosedDirs: closed, UseIgnore: true}
+		fs, err := NewFileSearch(&ft.FileNode, fp, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for range fs.Run() {
+			n++
+		}
+		if n != 15 {
+			t.Errorf("closed: %v UseIgnore: number of files should have been: %v  was: %v\n", closed, 15, n)
+		}
+	}
+
+	ft := openSearchTree(t, root, true)
+	fs, _ := NewFileSearch(&ft.FileNode, &FindParams{Find: "needle"}, "")
+	res := fs.Run()
+	fs.Cancel()
+	for range res {
+	}
+	if !fs.Stats.Canceled {
+		t.Errorf("search should have been canceled\n")
+	}
+}
+
+func benchmarkFileTreeSearch(b *testing.B, closed bool) {
+	root := genSearchTree(b, 20, 50, 2)
+	defer os.RemoveAll(root)
+	ft := openSearchTree(b, root, !closed)
+	fp := &FindParams{Find: "needle", ClosedDirs: closed}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res := FileTreeSearch(&ft.FileNode, fp, "")
+		if len(res) != 1000 {
+			b.Fatalf("number of files should have been: %v  was: %v\n", 1000, len(res))
+		}
+	}
+}
+
+func BenchmarkFileTreeSearch(b *testing.B) {
+	benchmarkFileTreeSearch(b, false)
+}
+
+func BenchmarkFileTreeSearchDisk(b *testing.B) {
+	benchmarkFileTreeSearch(b, true)
+}
@@ -214,83 +214,129 @@ func (on *OpenNodes) NChanged() int {
 
 // FileSearchResults is used to report search results
 type FileSearchResults struct {
-	Node    *giv.FileNode
-	Count   int
-	Matches []textbuf.Match
-}
-
-// FileTreeSearch returns list of all nodes starting at given node of given
-// language(s) that contain the given string (non regexp version), sorted in
-// descending order by number of occurrences -- ignoreCase transforms
-// everything into lowercase
-func FileTreeSearch(start *giv.FileNode, find string, ignoreCase, regExp bool, loc FindLoc, activeDir string, langs []filecat.Supported) []FileSearchResults {
-	fb := []byte(find)
-	fsz := len(find)
-	if fsz == 0 {
-		return nil
+	Node    *giv.FileNode   `desc:"file node for the file -- nil for files found on disk within closed directories"`
+	FPath   string          `desc:"full path to the file -- always set"`
+	Count   int             `desc:"number of matches"`
+	Matches []textbuf.Match `desc:"the matches"`
+}
+
+// RelPath returns the path of the file relative to given root path
+func (fs *FileSearchResults) RelPath(root string) string {
+	if fs.Node != nil {
+		return fs.Node.MyRelPath()
 	}
-	var re *regexp.Regexp
-	var err error
-	if regExp {
-		re, err = regexp.Compile(find)
-		if err != nil {
-			log.Println(err)
-			return nil
-		}
+	return giv.RelFilePath(fs.FPath, root)
+}
+
+// FindSkipDirs are directory names that are never searched when reading
+// closed directories directly from disk (version control metadata)
+var FindSkipDirs = []string{".git", ".svn", ".hg", ".bzr"}
+
+// FindFilter determines which files and directories are searched, based on
+// the Langs, Include, Exclude and UseIgnore settings in FindParams.
+// Glob patterns use the same syntax as .gitignore files: patterns without a /
+// match the name at any level, otherwise they match the path relative to Root.
+type FindFilter struct {
+	Root    string              `desc:"root path of the project -- patterns are relative to this"`
+	Langs   []filecat.Supported `desc:"languages for files to search"`
+	Include []*IgnoreRule       `desc:"compiled include patterns"`
+	Exclude []*IgnoreRule       `desc:"compiled exclude patterns"`
+	Ign     *Ignorer            `desc:"ignore file rules -- nil if not using them"`
+}
+
+// NewFindFilter returns a new FindFilter for given root path and params
+func NewFindFilter(root string, fp *FindParams) *FindFilter {
+	ff := &FindFilter{Root: filepath.Clean(root), Langs: fp.Langs}
+	ff.Include = FindFilterRules(fp.Include)
+	ff.Exclude = FindFilterRules(fp.Exclude)
+	if fp.UseIgnore {
+		ff.Ign = NewIgnorer(ff.Root)
 	}
-	mls := make([]FileSearchResults, 0)
-	start.FuncDownMeFirst(0, start, func(k ki.Ki, level int, d interface{}) bool {
-		sfn := k.Embed(giv.KiT_FileNode).(*giv.FileNode)
-		if sfn.IsDir() && !sfn.IsOpen() {
-			// fmt.Printf("dir: %v closed\n", sfn.FPath)
-			return ki.Break // don't go down into closed directories!
-		}
-		if sfn.IsDir() || sfn.IsExec() || sfn.Info.Kind == "octet-stream" || sfn.IsAutoSave() {
-			// fmt.Printf("dir: %v opened\n", sfn.Nm)
-			return ki.Continue
-		}
-		if strings.HasSuffix(sfn.Nm, ".gide") { // exclude self
-			return ki.Continue
-		}
-		if !filecat.IsMatchList(langs, sfn.Info.Sup) {
-			return ki.Continue
-		}
-		if loc == FindLocDir {
-			cdir, _ := filepath.Split(string(sfn.FPath))
-			if activeDir != cdir {
-				return ki.Continue
-			}
-		} else if loc == FindLocNotTop {
-			if level == 1 {
-				return ki.Continue
-			}
+	return ff
+}
+
+// FindFilterRules compiles given glob patterns into rules
+func FindFilterRules(globs []string) []*IgnoreRule {
+	var rls []*IgnoreRule
+	for _, g := range globs {
+		rl := ParseIgnoreRule(strings.TrimSpace(g))
+		if rl != nil {
+			rls = append(rls, rl)
 		}
-		var cnt int
-		var matches []textbuf.Match
-		if sfn.IsOpen() && sfn.Buf != nil {
-			if regExp {
-				cnt, matches = sfn.Buf.SearchRegexp(re)
-			} else {
-				cnt, matches = sfn.Buf.Search(fb, ignoreCase, false)
-			}
-		} else {
-			if regExp {
-				cnt, matches = textbuf.SearchFileRegexp(string(sfn.FPath), re)
-			} else {
-				cnt, matches = textbuf.SearchFile(string(sfn.FPath), fb, ignoreCase)
-			}
+	}
+	return rls
+}
+
+// MatchRules returns true if given path matches any of the rules
+func (ff *FindFilter) MatchRules(rls []*IgnoreRule, fpath string, isDir bool) bool {
+	if len(rls) == 0 {
+		return false
+	}
+	rel := filepath.ToSlash(giv.RelFilePath(fpath, ff.Root))
+	for _, rl := range rls {
+		if rl.DirOnly && !isDir {
+			continue
 		}
-		if cnt > 0 {
-			mls = append(mls, FileSearchResults{sfn, cnt, matches})
+		if rl.Re.MatchString(rel) {
+			return true
 		}
-		return ki.Continue
-	})
+	}
+	return false
+}
+
+// SkipDir returns true if given directory should not be searched
+func (ff *FindFilter) SkipDir(fpath string) bool {
+	if ff.MatchRules(ff.Exclude, fpath, true) {
+		return true
+	}
+	return ff.Ign != nil && ff.Ign.IsIgnored(fpath, true)
+}
+
+// SkipFile returns true if given file, of given supported type, should not be searched
+func (ff *FindFilter) SkipFile(fpath string, sup filecat.Supported) bool {
+	if !filecat.IsMatchList(ff.Langs, sup) {
+		return true
+	}
+	if len(ff.Include) > 0 && !ff.MatchRules(ff.Include, fpath, false) {
+		return true
+	}
+	if ff.MatchRules(ff.Exclude, fpath, false) {
+		return true
+	}
+	return ff.Ign != nil && ff.Ign.IsIgnored(fpath, false)
+}
+
+// FileTreeSearch returns list of all nodes starting at given node that
+// contain the find string in given params, subject to the Langs, Loc and
+// file filtering options, sorted in descending order by number of
+// occurrences.  Closed directories are only searched if ClosedDirs is set,
+// in which case they are read directly from disk and not opened in the tree.
+// This runs a FileSearch and waits for all of its results.
+func FileTreeSearch(start *giv.FileNode, fp *FindParams, activeDir string) []FileSearchResults {
+	fs, err := NewFileSearch(start, fp, activeDir)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	mls := make([]FileSearchResults, 0)
+	for r := range fs.Run() {
+		mls = append(mls, r)
+	}
 	sort.Slice(mls, func(i, j int) bool {
 		return mls[i].Count > mls[j].Count
 	})
 	return mls
 }
 
+// FileSearchPath searches the file at given path, using re if non-nil,
+// and otherwise the literal find string.
+func FileSearchPath(fpath string, find []byte, ignoreCase bool, re *regexp.Regexp) (int, []textbuf.Match) {
+	if re != nil {
+		return textbuf.SearchFileRegexp(fpath, re)
+	}
+	return textbuf.SearchFile(fpath, find, ignoreCase)
+}
+
 /////////////////////////////////////////////////////////////////////////
 // FileTreeView is the Gide version of the FileTreeView
 
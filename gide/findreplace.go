@@ -0,0 +1,199 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/pi/lex"
+)
+
+// FindRegexp returns the compiled regexp for given find params: in MultiLine
+// mode ^ and $ match at line boundaries, and a non-regexp find string is
+// matched literally (it can contain newlines).  Returns nil if neither
+// Regexp nor MultiLine is set, or if Structural is set.
+func FindRegexp(fp *FindParams) (*regexp.Regexp, error) {
+	if (!fp.Regexp && !fp.MultiLine) || fp.Structural {
+		return nil, nil
+	}
+	rs := fp.Find
+	if !fp.Regexp {
+		rs = regexp.QuoteMeta(rs)
+		if fp.IgnoreCase {
+			rs = "(?i)" + rs
+		}
+	}
+	if fp.MultiLine {
+		rs = "(?m)" + rs
+	}
+	return regexp.Compile(rs)
+}
+
+// SearchMultiLine finds all the matches of re in the entire text, which can
+// span multiple lines -- the context text of each match is from its first line.
+func SearchMultiLine(txt []byte, re *regexp.Regexp) (int, []textbuf.Match) {
+	matches := MatchesAtOffsets(txt, re.FindAllIndex(txt, -1))
+	return len(matches), matches
+}
+
+// MatchesAtOffsets returns the matches for given start, end byte offsets in
+// the text, which can span multiple lines -- the context text of each match
+// is from its first line.
+func MatchesAtOffsets(txt []byte, locs [][]int) []textbuf.Match {
+	if len(locs) == 0 {
+		return nil
+	}
+	lnst := []int{0} // byte offset of start of each line
+	for i, b := range txt {
+		if b == '\n' {
+			lnst = append(lnst, i+1)
+		}
+	}
+	bytePos := func(off int) (ln, boff int) {
+		ln = sort.Search(len(lnst), func(i int) bool { return lnst[i] > off }) - 1
+		return ln, off - lnst[ln]
+	}
+	var matches []textbuf.Match
+	for _, loc := range locs {
+		if loc[0] == loc[1] {
+			continue // empty matches are not useful here
+		}
+		sln, sb := bytePos(loc[0])
+		eln, eb := bytePos(loc[1])
+		lend := len(txt)
+		if sln+1 < len(lnst) {
+			lend = lnst[sln+1] - 1
+		}
+		lbs := txt[lnst[sln]:lend]
+		rn := bytes.Runes(lbs)
+		sch := utf8.RuneCount(lbs[:sb])
+		ech := len(rn)
+		if eln == sln {
+			ech = utf8.RuneCount(lbs[:eb])
+		}
+		mt := textbuf.NewMatch(rn, sch, ech, sln)
+		mt.Reg.End.Ln = eln
+		mt.Reg.End.Ch = utf8.RuneCount(txt[lnst[eln] : lnst[eln]+eb])
+		matches = append(matches, mt)
+	}
+	return matches
+}
+
+// SearchFileMultiLine finds all the matches of re in given file, which can
+// span multiple lines
+func SearchFileMultiLine(fpath string, re *regexp.Regexp) (int, []textbuf.Match) {
+	txt, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return 0, nil
+	}
+	return SearchMultiLine(txt, re)
+}
+
+// SearchBufMultiLine finds all the matches of re in given buffer, which can
+// span multiple lines
+func SearchBufMultiLine(tb *giv.TextBuf, re *regexp.Regexp) (int, []textbuf.Match) {
+	tb.LinesMu.RLock()
+	txt := bytes.Join(tb.LineBytes, []byte("\n"))
+	tb.LinesMu.RUnlock()
+	return SearchMultiLine(txt, re)
+}
+
+// NormalizeReplace converts numbered capture group references in a regexp
+// replacement string into the unambiguous ${n} form, so that $1x means
+// group 1 followed by x, instead of the (generally nonexistent) group named
+// 1x as in regexp.Expand.  Named groups ($name, ${name}) and $$ are unchanged.
+func NormalizeReplace(repl string) string {
+	if !strings.Contains(repl, "$") {
+		return repl
+	}
+	var b strings.Builder
+	sz := len(repl)
+	for i := 0; i < sz; i++ {
+		c := repl[i]
+		if c != '$' || i+1 >= sz {
+			b.WriteByte(c)
+			continue
+		}
+		if repl[i+1] == '$' {
+			b.WriteString("$$")
+			i++
+			continue
+		}
+		j := i + 1
+		for j < sz && repl[j] >= '0' && repl[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("${" + repl[i+1:j] + "}")
+		i = j - 1
+	}
+	return b.String()
+}
+
+// RegexpReplaceText returns the replacement for the match of re at given
+// region of the buffer, expanding capture group references in repl.  The
+// regexp is re-matched in the context of the full lines of the region, so
+// that anchors and word boundaries behave as they did in the search.
+func RegexpReplaceText(tb *giv.TextBuf, reg textbuf.Region, re *regexp.Regexp, repl string) []byte {
+	repl = NormalizeReplace(repl)
+	mtxt := tb.Region(reg.Start, reg.End).ToBytes()
+	eln := tb.Line(reg.End.Ln)
+	ctx := tb.Region(lex.Pos{Ln: reg.Start.Ln}, lex.Pos{Ln: reg.End.Ln, Ch: len(eln)}).ToBytes()
+	sln := tb.Line(reg.Start.Ln)
+	if reg.Start.Ch > len(sln) {
+		return []byte(repl)
+	}
+	off := len(string(sln[:reg.Start.Ch]))
+	for _, loc := range re.FindAllSubmatchIndex(ctx, -1) {
+		if loc[0] == off && loc[1] == off+len(mtxt) {
+			return re.Expand(nil, []byte(repl), ctx, loc)
+		}
+	}
+	loc := re.FindSubmatchIndex(mtxt)
+	if loc == nil {
+		return []byte(repl)
+	}
+	return re.Expand(nil, []byte(repl), mtxt, loc)
+}
+
+// PreserveCase returns repl with the case pattern of src applied: if src is
+// all upper case, so is the result; if all lower case, so is the result; if
+// src is capitalized, the first letter of the result is upper case, with the
+// rest of repl kept as is.  Otherwise repl is returned as is.
+func PreserveCase(src, repl string) string {
+	hasUpper, hasLower := false, false
+	for _, r := range src {
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		} else if unicode.IsLower(r) {
+			hasLower = true
+		}
+	}
+	switch {
+	case hasUpper && !hasLower:
+		return strings.ToUpper(repl)
+	case hasLower && !hasUpper:
+		return strings.ToLower(repl)
+	case hasUpper && hasLower:
+		fr, sz := utf8.DecodeRuneInString(src)
+		if !unicode.IsUpper(fr) || strings.ToLower(src[sz:]) != src[sz:] {
+			return repl
+		}
+		rr, rsz := utf8.DecodeRuneInString(repl)
+		return string(unicode.ToUpper(rr)) + repl[rsz:]
+	}
+	return repl
+}
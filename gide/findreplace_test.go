@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSearchMultiLine(t *testing.T) {
+	txt := []byte("func a() {\n\treturn\n}\n\nfunc b() {\n\treturn\n}\n")
+	re, err := FindRegexp(&FindParams{Find: `\{\n\treturn\n\}`, Regexp: true, MultiLine: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnt, matches := SearchMultiLine(txt, re)
+	if cnt != 2 {
+		t.Fatalf("count should have been: %v  was: %v\n", 2, cnt)
+	}
+	reg := matches[1].Reg
+	if reg.Start.Ln != 4 || reg.Start.Ch != 9 || reg.End.Ln != 6 || reg.End.Ch != 1 {
+		t.Errorf("region should have been: 4:9-6:1  was: %v\n", reg)
+	}
+
+	re, _ = FindRegexp(&FindParams{Find: "return\n}", IgnoreCase: true, MultiLine: true})
+	if cnt, _ = SearchMultiLine(txt, re); cnt != 2 {
+		t.Errorf("literal count should have been: %v  was: %v\n", 2, cnt)
+	}
+}
+
+func TestNormalizeReplace(t *testing.T) {
+	re := regexp.MustCompile(`(\w+)\.(?P<fld>\w+)`)
+	src := []byte("obj.Field")
+	loc := re.FindSubmatchIndex(src)
+	tests := []struct {
+		repl string
+		res  string
+	}{
+		{"$2_$1", "Field_obj"},
+		{"$1x", "objx"},
+		{"${1}x", "objx"},
+		{"$fld", "Field"},
+		{"$$1", "$1"},
+		{"cost $", "cost $"},
+	}
+	for _, tst := range tests {
+		res := string(re.Expand(nil, []byte(NormalizeReplace(tst.repl)), src, loc))
+		if res != tst.res {
+			t.Errorf("repl: %v should have been: %v  was: %v\n", tst.repl, tst.res, res)
+		}
+	}
+}
+
+func TestPreserveCase(t *testing.T) {
+	tests := []struct {
+		src  string
+		repl string
+		res  string
+	}{
+		{"foo", "bar", "bar"},
+		{"Foo", "bar", "Bar"},
+		{"Foo", "fooBar", "FooBar"},
+		{"FOO", "bar", "BAR"},
+		{"fooBar", "bazQux", "bazQux"},
+		{"123", "bar", "bar"},
+	}
+	for _, tst := range tests {
+		res := PreserveCase(tst.src, tst.repl)
+		if res != tst.res {
+			t.Errorf("src: %v repl: %v should have been: %v  was: %v\n", tst.src, tst.repl, tst.res, res)
+		}
+	}
+}
@@ -8,15 +8,18 @@ import (
 	"bytes"
 	"fmt"
 	"html"
+	"log"
 	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/giv"
 	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
 	"github.com/goki/pi/filecat"
@@ -52,24 +55,35 @@ func (ev *FindLoc) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(
 
 // FindParams are parameters for find / replace
 type FindParams struct {
-	Find       string              `desc:"find string"`
-	Replace    string              `desc:"replace string"`
-	IgnoreCase bool                `desc:"ignore case"`
-	Regexp     bool                `desc:"use regexp regular expression search and replace"`
-	Langs      []filecat.Supported `desc:"languages for files to search"`
-	Loc        FindLoc             `desc:"locations to search in"`
-	FindHist   []string            `desc:"history of finds"`
-	ReplHist   []string            `desc:"history of replaces"`
+	Find         string              `desc:"find string"`
+	Replace      string              `desc:"replace string"`
+	IgnoreCase   bool                `desc:"ignore case"`
+	Regexp       bool                `desc:"use regexp regular expression search and replace"`
+	MultiLine    bool                `desc:"multi-line mode: the find string is matched against the entire file, so matches can span lines -- in a regexp, \\n matches a newline, ^ and $ match at line boundaries, and (?s) makes . match newlines"`
+	Structural   bool                `desc:"structural search of Go files: the find string is a Go expression, statement list or declaration, in which $name matches any single expression, statement, identifier etc, and $*name matches any number of them (e.g., arguments or statements) -- the replace string is a Go rewrite template using the same $name metavariables"`
+	PreserveCase bool                `desc:"preserve the case of the found text in the replacement: replacing foo with bar turns Foo into Bar and FOO into BAR"`
+	Langs        []filecat.Supported `desc:"languages for files to search"`
+	Loc          FindLoc             `desc:"locations to search in"`
+	Include      []string            `desc:"glob patterns for files to include in the search (e.g., *.go) -- if empty, all files are included -- patterns without a / match the file name at any level, otherwise the path relative to the project root, with ** matching any number of directories"`
+	Exclude      []string            `desc:"glob patterns for files and directories to exclude from the search (e.g., vendor, *_test.go) -- same syntax as Include"`
+	UseIgnore    bool                `desc:"honor .gitignore and .ignore files -- files and directories matching their patterns are not searched"`
+	ClosedDirs   bool                `desc:"search everything on disk, including directories that are closed in the file browser -- these are read directly from disk without opening them in the tree"`
+	Index        bool                `desc:"use a persistent trigram index of file contents to skip files that cannot match, without reading them -- the index is saved next to the project file, and updated as files change"`
+	FindHist     []string            `desc:"history of finds"`
+	ReplHist     []string            `desc:"history of replaces"`
 }
 
 // FindView is a find / replace widget that displays results in a TextView
 // and has a toolbar for controlling find / replace process.
 type FindView struct {
 	gi.Layout
-	Gide   Gide           `json:"-" xml:"-" desc:"parent gide project"`
-	LangVV giv.ValueView  `desc:"langs value view"`
-	Time   time.Time      `desc:"time of last find"`
-	Re     *regexp.Regexp `desc:"compiled regexp"`
+	Gide    Gide                `json:"-" xml:"-" desc:"parent gide project"`
+	LangVV  giv.ValueView       `desc:"langs value view"`
+	Time    time.Time           `desc:"time of last find"`
+	Re      *regexp.Regexp      `desc:"compiled regexp"`
+	Search  *FileSearch         `json:"-" xml:"-" view:"-" desc:"current search in progress, if any"`
+	Results []FileSearchResults `json:"-" xml:"-" view:"-" desc:"results of last search, as shown in the buffer"`
+	mu      sync.Mutex          // guards Search and Results, which are updated by the search goroutine
 }
 
 var KiT_FindView = kit.Types.AddType(&FindView{}, FindViewProps)
@@ -79,16 +93,39 @@ func (fv *FindView) Params() *FindParams {
 	return &fv.Gide.ProjPrefs().Find
 }
 
-// ShowResults shows the results in the buffer
+// ShowResults shows the results in the buffer, opening the first result
 func (fv *FindView) ShowResults(res []FileSearchResults) {
+	fv.mu.Lock()
+	fv.Results = nil
+	fv.appendResults(res)
+	fv.mu.Unlock()
+	fv.OpenFirstResult()
+}
+
+// AppendResults appends given results to the end of the results buffer
+func (fv *FindView) AppendResults(res []FileSearchResults) {
+	fv.mu.Lock()
+	fv.appendResults(res)
+	fv.mu.Unlock()
+}
+
+// appendResults appends given results to the end of the results buffer --
+// must be called under the lock
+func (fv *FindView) appendResults(res []FileSearchResults) {
+	if len(res) == 0 {
+		return
+	}
+	fv.Results = append(fv.Results, res...)
 	ftv := fv.TextView()
 	fbuf := ftv.Buf
+	stLn := fbuf.NumLines() - 1 // buffer always ends in a blank line
 	outlns := make([][]byte, 0, 100)
 	outmus := make([][]byte, 0, 100) // markups
+	root := string(fv.Gide.FileTree().FPath)
 	for _, fs := range res {
-		fp := fs.Node.Info.Path
-		fn := fs.Node.MyRelPath()
-		fbStLn := len(outlns) // find buf start ln
+		fp := fs.FPath
+		fn := fs.RelPath(root)
+		fbStLn := stLn + len(outlns) // find buf start ln
 		lstr := fmt.Sprintf(`%v: %v`, fn, fs.Count)
 		outlns = append(outlns, []byte(lstr))
 		mstr := fmt.Sprintf(`<b>%v</b>`, lstr)
@@ -98,6 +135,7 @@ func (fv *FindView) ShowResults(res []FileSearchResults) {
 			txt = append([]byte{'\t'}, txt...)
 			ln := mt.Reg.Start.Ln + 1
 			ch := mt.Reg.Start.Ch + 1
+			eln := mt.Reg.End.Ln + 1
 			ech := mt.Reg.End.Ch + 1
 			fnstr := fmt.Sprintf("%v:%d:%d", fn, ln, ch)
 			nomu := bytes.Replace(txt, []byte("<mark>"), nil, -1)
@@ -106,7 +144,7 @@ func (fv *FindView) ShowResults(res []FileSearchResults) {
 			lstr = fmt.Sprintf(`%v: %s`, fnstr, nomus) // note: has tab embedded at start of lstr
 
 			outlns = append(outlns, []byte(lstr))
-			mstr = fmt.Sprintf(`	<a href="find:///%v#R%vN%vL%vC%v-L%vC%v">%v</a>: %s`, fp, fbStLn, fs.Count, ln, ch, ln, ech, fnstr, txt)
+			mstr = fmt.Sprintf(`	<a href="find:///%v#R%vN%vL%vC%v-L%vC%v">%v</a>: %s`, fp, fbStLn, fs.Count, ln, ch, eln, ech, fnstr, txt)
 			outmus = append(outmus, []byte(mstr))
 		}
 		outlns = append(outlns, []byte(""))
@@ -116,6 +154,11 @@ func (fv *FindView) ShowResults(res []FileSearchResults) {
 	mtxt := bytes.Join(outmus, []byte("\n"))
 	fbuf.SetInactive(true)
 	fbuf.AppendTextMarkup(ltxt, mtxt, giv.EditSignal)
+}
+
+// OpenFirstResult moves the cursor to the first result link and opens it
+func (fv *FindView) OpenFirstResult() {
+	ftv := fv.TextView()
 	ftv.CursorStartDoc()
 	ok := ftv.CursorNextLink(false) // no wrap
 	if ok {
@@ -123,6 +166,103 @@ func (fv *FindView) ShowResults(res []FileSearchResults) {
 	}
 }
 
+// FindSearchBatch is the max number of results appended to the find
+// buffer at one time during an incremental search
+var FindSearchBatch = 50
+
+// StartSearch starts given search running, canceling any search already in
+// progress, and appends its results to the buffer incrementally as they
+// arrive.  The first result is opened as soon as it is available, and stats
+// are shown when the search completes.
+func (fv *FindView) StartSearch(fs *FileSearch) {
+	fv.CancelSearch()
+	fv.mu.Lock()
+	fv.Search = fs
+	fv.Results = nil
+	fv.mu.Unlock()
+	fv.SetStats("searching...")
+	fv.FindBar().UpdateActions()
+	res := fs.Run()
+	go func() {
+		first := true
+		for r := range res {
+			batch := []FileSearchResults{r}
+		drain:
+			for len(batch) < FindSearchBatch {
+				select {
+				case nr, ok := <-res:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, nr)
+				default:
+					break drain
+				}
+			}
+			if fv.IsDeleted() {
+				continue
+			}
+			fv.mu.Lock()
+			// searches are canceled and replaced under the lock, so no results
+			// are appended once this search is canceled
+			if fv.Search != fs || fs.IsCanceled() {
+				fv.mu.Unlock()
+				continue
+			}
+			wupdt := fv.TopUpdateStart()
+			fv.appendResults(batch)
+			fv.mu.Unlock()
+			if first {
+				fv.OpenFirstResult()
+				first = false
+			}
+			fv.TopUpdateEnd(wupdt)
+		}
+		fs.Wait()
+		if fv.IsDeleted() {
+			return
+		}
+		fv.mu.Lock()
+		if fv.Search != fs { // a newer search has been started since
+			fv.mu.Unlock()
+			return
+		}
+		fv.Search = nil
+		fv.mu.Unlock()
+		wupdt := fv.TopUpdateStart()
+		st := fs.Stats.String()
+		fv.SetStats(st)
+		fv.FindBar().UpdateActions()
+		fv.TopUpdateEnd(wupdt)
+		fv.Gide.SetStatus("Find: " + st)
+	}()
+}
+
+// CancelSearch cancels the current search in progress, if any -- it
+// remains the current search until it stops, when its stats are shown
+func (fv *FindView) CancelSearch() {
+	fv.mu.Lock()
+	if fv.Search == nil {
+		fv.mu.Unlock()
+		return
+	}
+	fv.Search.Cancel()
+	fv.mu.Unlock()
+	fv.FindBar().UpdateActions()
+}
+
+// IsSearching returns true if a search is currently in progress
+func (fv *FindView) IsSearching() bool {
+	fv.mu.Lock()
+	defer fv.mu.Unlock()
+	return fv.Search != nil && !fv.Search.IsDone() && !fv.Search.IsCanceled()
+}
+
+// SetStats sets the search stats label
+func (fv *FindView) SetStats(st string) {
+	fv.StatsLabel().SetText(st)
+}
+
 // SaveFindString saves the given find string to the find params history and current str
 func (fv *FindView) SaveFindString(find string) {
 	fv.Params().Find = find
@@ -156,7 +296,7 @@ func (fv *FindView) FindAction() {
 // CheckValidRegexp returns false if using regexp and it is not valid
 func (fv *FindView) CheckValidRegexp() bool {
 	fp := fv.Params()
-	if !fp.Regexp {
+	if !fp.Regexp || fp.Structural {
 		return true
 	}
 	if fv.Re == nil {
@@ -211,15 +351,8 @@ func (fv *FindView) ReplaceAction() bool {
 	reg.Time.SetTime(fv.Time)
 	reg = tv.Buf.AdjustReg(reg)
 	if !reg.IsNil() {
-		if fp.Regexp {
-			rg := tv.Buf.Region(reg.Start, reg.End)
-			b := rg.ToBytes()
-			rb := fv.Re.ReplaceAll(b, []byte(fp.Replace))
-			tv.Buf.ReplaceText(reg.Start, reg.End, reg.Start, string(rb), giv.EditSignal, false)
-		} else {
-			// MatchCase only if doing IgnoreCase
-			tv.Buf.ReplaceText(reg.Start, reg.End, reg.Start, fp.Replace, giv.EditSignal, fp.IgnoreCase)
-		}
+		_, repl := FindReplaceText(tv.Buf, reg, fp, fv.Re)
+		tv.Buf.ReplaceText(reg.Start, reg.End, reg.Start, repl, giv.EditSignal, false)
 
 		// delete the link for the just done replace
 		ftvln := ftv.CursorPos.Ln
@@ -238,6 +371,30 @@ func (fv *FindView) ReplaceAction() bool {
 	return ok
 }
 
+// ReplacePreview shows the replacements that would be made for all the
+// current results in the Replace tab, where they can be reviewed and applied
+func (fv *FindView) ReplacePreview() {
+	if !fv.CompileRegexp() {
+		return
+	}
+	if fv.IsSearching() {
+		gi.PromptDialog(nil, gi.DlgOpts{Title: "Search in Progress", Prompt: "The search is still in progress -- wait until it completes or cancel it before previewing replacements"}, gi.AddOk, gi.NoCancel, nil, nil)
+		return
+	}
+	fp := fv.Params()
+	fv.SaveReplString(fp.Replace)
+	gi.StringsInsertFirstUnique(&fp.ReplHist, fp.Replace, gi.Prefs.Params.SavedPathsMax)
+	rvi := fv.Gide.RecycleTab("Replace", KiT_ReplaceView, true)
+	if rvi == nil {
+		return
+	}
+	rv := rvi.Embed(KiT_ReplaceView).(*ReplaceView)
+	fv.mu.Lock()
+	res := fv.Results
+	fv.mu.Unlock()
+	rv.Config(fv.Gide, res, fp, fv.Re, fv.Time)
+}
+
 // ReplaceAllAction performs replace all, prompting before proceeding
 func (fv *FindView) ReplaceAllAction() {
 	gi.PromptDialog(nil, gi.DlgOpts{Title: "Confirm Replace All", Prompt: "Are you sure you want to Replace All?"}, gi.AddOk, gi.AddCancel, fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
@@ -250,12 +407,20 @@ func (fv *FindView) ReplaceAllAction() {
 // CompileRegexp compiles the regexp if necessary -- returns false if it is invalid
 func (fv *FindView) CompileRegexp() bool {
 	fp := fv.Params()
+	if fp.Structural {
+		fv.Re = nil
+		if _, err := ParseStructPattern(fp.Find); err != nil {
+			gi.PromptDialog(nil, gi.DlgOpts{Title: "Structural Pattern is Invalid", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+			return false
+		}
+		return true
+	}
 	if !fp.Regexp {
 		fv.Re = nil
 		return true
 	}
 	var err error
-	fv.Re, err = regexp.Compile(fp.Find)
+	fv.Re, err = FindRegexp(fp)
 	if err != nil {
 		gi.PromptDialog(nil, gi.DlgOpts{Title: "Regexp is Invalid", Prompt: fmt.Sprintf("The regular expression was invalid: %v", err)}, gi.AddOk, gi.NoCancel, nil, nil)
 		return false
@@ -349,6 +514,158 @@ func (fv *FindView) HighlightFinds(tv, ftv *giv.TextView, fbStLn, fCount int, fi
 //////////////////////////////////////////////////////////////////////////////////////
 //    GUI config
 
+// SaveSearch saves the current find params as a named search in the project
+// prefs, replacing any existing search of that name
+func (fv *FindView) SaveSearch(name string) {
+	if name == "" {
+		return
+	}
+	pp := fv.Gide.ProjPrefs()
+	pp.Searches.Add(name, fv.Params())
+	pp.Changed = true
+	fv.Gide.SetStatus(fmt.Sprintf("saved search: %v", name))
+}
+
+// SaveSearchPrompt prompts for a name to save the current find params under
+func (fv *FindView) SaveSearchPrompt() {
+	gi.StringPromptDialog(fv.Viewport, "", "Search Name",
+		gi.DlgOpts{Title: "Save Search", Prompt: "Name to save the current find params under -- an existing search of the same name is replaced"},
+		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			dlg := send.(*gi.Dialog)
+			if sig == int64(gi.DialogAccepted) {
+				fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+				fvv.SaveSearch(gi.StringPromptDialogValue(dlg))
+			}
+		})
+}
+
+// RunSavedSearch sets the find params from the saved search of given name
+// and runs it
+func (fv *FindView) RunSavedSearch(name string) {
+	ss, _ := fv.Gide.ProjPrefs().Searches.ByName(name)
+	if ss == nil {
+		return
+	}
+	ss.Apply(fv.Params())
+	fv.FindAction()
+}
+
+// DeleteSavedSearch deletes the saved search of given name
+func (fv *FindView) DeleteSavedSearch(name string) {
+	pp := fv.Gide.ProjPrefs()
+	if pp.Searches.Delete(name) {
+		pp.Changed = true
+		fv.Gide.SetStatus(fmt.Sprintf("deleted saved search: %v", name))
+	}
+}
+
+// ExportResults saves the current results to given file, in JSON format if
+// it has a .json extension, and file:line:col: text quickfix format otherwise
+func (fv *FindView) ExportResults(fname gi.FileName) {
+	fv.mu.Lock()
+	res := fv.Results
+	fv.mu.Unlock()
+	if len(res) == 0 {
+		fv.Gide.SetStatus("no find results to export")
+		return
+	}
+	root := string(fv.Gide.FileTree().FPath)
+	err := SaveFindResults(res, root, string(fname))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	fv.Gide.SetStatus(fmt.Sprintf("exported find results to: %v", fname))
+}
+
+// ImportResults loads results from given file, in JSON or file:line:col: text
+// quickfix format (e.g., from a linter), replacing the current results --
+// relative paths are relative to the project root
+func (fv *FindView) ImportResults(fname gi.FileName) {
+	root := string(fv.Gide.FileTree().FPath)
+	res, err := OpenFindResults(string(fname), root)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	fv.CancelSearch()
+	fv.mu.Lock()
+	fv.Search = nil // results below replace its results
+	fv.mu.Unlock()
+	fv.Time = time.Now()
+	fv.TextView().Buf.New(0)
+	fv.ShowResults(res)
+	n := 0
+	for _, fs := range res {
+		n += fs.Count
+	}
+	fv.SetStats(fmt.Sprintf("%v files, %v items imported", len(res), n))
+}
+
+// ExportResultsDialog prompts for a file to export the current results to
+func (fv *FindView) ExportResultsDialog() {
+	giv.FileViewDialog(fv.Viewport, "", ".json,.txt", giv.DlgOpts{Title: "Export Find Results", Prompt: "File to save the find results to -- .json for JSON, otherwise file:line:col: text lines"}, nil,
+		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.DialogAccepted) {
+				fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+				dlg, _ := send.(*gi.Dialog)
+				fvv.ExportResults(gi.FileName(giv.FileViewDialogValue(dlg)))
+			}
+		})
+}
+
+// ImportResultsDialog prompts for a file to import results from
+func (fv *FindView) ImportResultsDialog() {
+	giv.FileViewDialog(fv.Viewport, "", ".json,.txt", giv.DlgOpts{Title: "Import Find Results", Prompt: "File with results to show -- JSON as exported, or file:line:col: text lines as output by compilers and linters"}, nil,
+		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.DialogAccepted) {
+				fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+				dlg, _ := send.(*gi.Dialog)
+				fvv.ImportResults(gi.FileName(giv.FileViewDialogValue(dlg)))
+			}
+		})
+}
+
+// SearchesMenu makes the menu of saved searches and result export / import
+func (fv *FindView) SearchesMenu(obj ki.Ki, m *gi.Menu) {
+	*m = gi.Menu{}
+	m.AddAction(gi.ActOpts{Label: "Save Search...", Tooltip: "save the current find params as a named search, stored in the project"}, fv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			fvv.SaveSearchPrompt()
+		})
+	m.AddAction(gi.ActOpts{Label: "Export Results...", Tooltip: "save the current results to a JSON or file:line:col: text file"}, fv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			fvv.ExportResultsDialog()
+		})
+	m.AddAction(gi.ActOpts{Label: "Import Results...", Tooltip: "show results from a JSON or file:line:col: text file, e.g., linter output"}, fv.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			fvv.ImportResultsDialog()
+		})
+	ss := fv.Gide.ProjPrefs().Searches
+	if len(ss) == 0 {
+		return
+	}
+	m.AddSeparator("search-sep")
+	for _, s := range ss {
+		m.AddAction(gi.ActOpts{Label: s.Name, Data: s.Name, Tooltip: "find: " + s.Params.Find}, fv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+				fvv.RunSavedSearch(send.(*gi.Action).Data.(string))
+			})
+	}
+	da := m.AddAction(gi.ActOpts{Label: "Delete Search"}, nil, nil)
+	for _, s := range ss {
+		da.Menu.AddAction(gi.ActOpts{Label: s.Name, Data: s.Name}, fv.This(),
+			func(recv, send ki.Ki, sig int64, data interface{}) {
+				fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+				fvv.DeleteSavedSearch(send.(*gi.Action).Data.(string))
+			})
+	}
+}
+
 // Config configures the view
 func (fv *FindView) Config(ge Gide) {
 	fv.Gide = ge
@@ -357,6 +674,7 @@ func (fv *FindView) Config(ge Gide) {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "findbar")
 	config.Add(gi.KiT_ToolBar, "replbar")
+	config.Add(gi.KiT_ToolBar, "filtbar")
 	config.Add(gi.KiT_Layout, "findtext")
 	mods, updt := fv.ConfigChildren(config)
 	if !mods {
@@ -374,8 +692,16 @@ func (fv *FindView) Config(ge Gide) {
 	ib.SetChecked(fp.IgnoreCase)
 	rb := fv.RegexpBox()
 	rb.SetChecked(fp.Regexp)
+	fv.MultiLineBox().SetChecked(fp.MultiLine)
+	fv.StructuralBox().SetChecked(fp.Structural)
+	fv.PreserveCaseBox().SetChecked(fp.PreserveCase)
 	cf := fv.LocCombo()
 	cf.SetCurIndex(int(fp.Loc))
+	fv.IncludeText().SetText(strings.Join(fp.Include, " "))
+	fv.ExcludeText().SetText(strings.Join(fp.Exclude, " "))
+	fv.UseIgnoreBox().SetChecked(fp.UseIgnore)
+	fv.ClosedDirsBox().SetChecked(fp.ClosedDirs)
+	fv.IndexBox().SetChecked(fp.Index)
 	tvly := fv.TextViewLay()
 	ConfigOutputTextView(tvly)
 	if mods {
@@ -395,6 +721,11 @@ func (fv *FindView) ReplBar() *gi.ToolBar {
 	return fv.ChildByName("replbar", 1).(*gi.ToolBar)
 }
 
+// FiltBar returns the file filter toolbar
+func (fv *FindView) FiltBar() *gi.ToolBar {
+	return fv.ChildByName("filtbar", 2).(*gi.ToolBar)
+}
+
 // FindText returns the find textfield in toolbar
 func (fv *FindView) FindText() *gi.ComboBox {
 	return fv.FindBar().ChildByName("find-str", 1).(*gi.ComboBox)
@@ -415,6 +746,21 @@ func (fv *FindView) RegexpBox() *gi.CheckBox {
 	return fv.FindBar().ChildByName("regexp", 3).(*gi.CheckBox)
 }
 
+// MultiLineBox returns the multi-line checkbox in toolbar
+func (fv *FindView) MultiLineBox() *gi.CheckBox {
+	return fv.FindBar().ChildByName("multi-line", 4).(*gi.CheckBox)
+}
+
+// StructuralBox returns the structural checkbox
+func (fv *FindView) StructuralBox() *gi.CheckBox {
+	return fv.FindBar().ChildByName("structural", 5).(*gi.CheckBox)
+}
+
+// PreserveCaseBox returns the preserve case checkbox in toolbar
+func (fv *FindView) PreserveCaseBox() *gi.CheckBox {
+	return fv.ReplBar().ChildByName("preserve-case", 4).(*gi.CheckBox)
+}
+
 // LocCombo returns the loc combobox
 func (fv *FindView) LocCombo() *gi.ComboBox {
 	return fv.ReplBar().ChildByName("loc", 5).(*gi.ComboBox)
@@ -425,6 +771,36 @@ func (fv *FindView) CurDirBox() *gi.CheckBox {
 	return fv.ReplBar().ChildByName("cur-dir", 6).(*gi.CheckBox)
 }
 
+// IncludeText returns the include patterns textfield in toolbar
+func (fv *FindView) IncludeText() *gi.TextField {
+	return fv.FiltBar().ChildByName("include", 1).(*gi.TextField)
+}
+
+// ExcludeText returns the exclude patterns textfield in toolbar
+func (fv *FindView) ExcludeText() *gi.TextField {
+	return fv.FiltBar().ChildByName("exclude", 3).(*gi.TextField)
+}
+
+// UseIgnoreBox returns the use ignore files checkbox in toolbar
+func (fv *FindView) UseIgnoreBox() *gi.CheckBox {
+	return fv.FiltBar().ChildByName("use-ignore", 4).(*gi.CheckBox)
+}
+
+// ClosedDirsBox returns the search closed dirs checkbox in toolbar
+func (fv *FindView) ClosedDirsBox() *gi.CheckBox {
+	return fv.FiltBar().ChildByName("closed-dirs", 5).(*gi.CheckBox)
+}
+
+// StatsLabel returns the search stats label in toolbar
+func (fv *FindView) StatsLabel() *gi.Label {
+	return fv.FindBar().ChildByName("stats", 9).(*gi.Label)
+}
+
+// IndexBox returns the use trigram index checkbox in toolbar
+func (fv *FindView) IndexBox() *gi.CheckBox {
+	return fv.FiltBar().ChildByName("index", 6).(*gi.CheckBox)
+}
+
 // FindNextAct returns the find next action in toolbar -- selected first
 func (fv *FindView) FindNextAct() *gi.Action {
 	return fv.FindBar().ChildByName("next", 3).(*gi.Action)
@@ -432,7 +808,7 @@ func (fv *FindView) FindNextAct() *gi.Action {
 
 // TextViewLay returns the find results TextView layout
 func (fv *FindView) TextViewLay() *gi.Layout {
-	return fv.ChildByName("findtext", 1).(*gi.Layout)
+	return fv.ChildByName("findtext", 3).(*gi.Layout)
 }
 
 // TextView returns the find results TextView
@@ -451,6 +827,9 @@ func (fv *FindView) ConfigToolbar() {
 	rb := fv.ReplBar()
 	rb.SetStretchMaxWidth()
 
+	fl := fv.FiltBar()
+	fl.SetStretchMaxWidth()
+
 	fb.AddAction(gi.ActOpts{Label: "Find:", Tooltip: "Find given string in project files. Only open folders in file browser will be searched -- adjust those to scope the search"},
 		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
@@ -509,6 +888,28 @@ func (fv *FindView) ConfigToolbar() {
 		}
 	})
 
+	ml := fb.AddNewChild(gi.KiT_CheckBox, "multi-line").(*gi.CheckBox)
+	ml.SetText("Multi-line")
+	ml.Tooltip = "match the find string against the entire file, so matches can span lines -- in a regexp, \\n matches a newline, ^ and $ match at line boundaries, and (?s) makes . match newlines"
+	ml.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().MultiLine = cb.IsChecked()
+		}
+	})
+
+	st := fb.AddNewChild(gi.KiT_CheckBox, "structural").(*gi.CheckBox)
+	st.SetText("Structural")
+	st.Tooltip = "structural search of Go files: the find string is a Go expression, statement list or declaration, in which $name matches any single expression, statement, identifier etc, and $*name matches any number of them, e.g., log.Printf($fmt, $*args) -- the replace string is a Go rewrite template using the same $name metavariables"
+	st.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().Structural = cb.IsChecked()
+		}
+	})
+
 	fb.AddAction(gi.ActOpts{Name: "next", Icon: "wedge-down", Tooltip: "go to next result"},
 		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
@@ -521,6 +922,22 @@ func (fv *FindView) ConfigToolbar() {
 			fvv.PrevFind()
 		})
 
+	fb.AddAction(gi.ActOpts{Name: "cancel", Icon: "close", Tooltip: "cancel the search in progress -- results found so far are kept", UpdateFunc: func(act *gi.Action) {
+		act.SetActiveStateUpdt(fv.IsSearching())
+	}}, fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+		fvv.CancelSearch()
+	})
+
+	stl := fb.AddNewChild(gi.KiT_Label, "stats").(*gi.Label)
+	stl.SetProp("min-width", units.NewCh(40))
+	stl.Tooltip = "statistics for the last search"
+
+	ssb := gi.AddNewMenuButton(fb, "searches")
+	ssb.SetText("Searches")
+	ssb.Tooltip = "save the current find params as a named search, re-run or delete saved searches, and export or import results"
+	ssb.MakeMenuFunc = fv.SearchesMenu
+
 	rb.AddAction(gi.ActOpts{Label: "Replace:", Tooltip: "Replace find string with replace string for currently-selected find result"}, fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 		fvv, _ := recv.Embed(KiT_FindView).(*FindView)
 		fvv.CompileRegexp()
@@ -530,7 +947,7 @@ func (fv *FindView) ConfigToolbar() {
 	repls := rb.AddNewChild(gi.KiT_ComboBox, "repl-str").(*gi.ComboBox)
 	repls.Editable = true
 	repls.SetStretchMaxWidth()
-	repls.Tooltip = "String to replace find string -- click for history -- use ${n} for regexp submatch where n = 1 for first submatch, etc"
+	repls.Tooltip = "String to replace find string -- click for history -- use $n or ${n} for regexp submatch where n = 1 for first submatch, etc, ${name} for named submatches, and $$ for a literal $"
 	repls.ConfigParts()
 	repls.ItemsFromStringList(fv.Params().ReplHist, true, 0)
 	rtf, _ := repls.TextField()
@@ -553,6 +970,23 @@ func (fv *FindView) ConfigToolbar() {
 			fvv.ReplaceAllAction()
 		})
 
+	rb.AddAction(gi.ActOpts{Label: "Preview", Tooltip: "preview all the replacements as a diff in the Replace tab, where each can be accepted or rejected before applying"},
+		fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			fvv.ReplacePreview()
+		})
+
+	pc := rb.AddNewChild(gi.KiT_CheckBox, "preserve-case").(*gi.CheckBox)
+	pc.SetText("Preserve Case")
+	pc.Tooltip = "preserve the case of the found text in the replacement: replacing foo with bar turns Foo into Bar and FOO into BAR"
+	pc.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().PreserveCase = cb.IsChecked()
+		}
+	})
+
 	locl := rb.AddNewChild(gi.KiT_Label, "loc-lbl").(*gi.Label)
 	locl.SetText("Loc:")
 	locl.Tooltip = "location to find in: all = all open folders in browser; file = current active file; dir = directory of current active file; nottop = all except the top-level in browser"
@@ -580,6 +1014,69 @@ func (fv *FindView) ConfigToolbar() {
 	langw := rb.AddNewChild(vtyp, "langs").(gi.Node2D)
 	fv.LangVV.ConfigWidget(langw)
 	langw.AsWidget().Tooltip = langl.Tooltip
+
+	incl := fl.AddNewChild(gi.KiT_Label, "include-lbl").(*gi.Label)
+	incl.SetText("Include:")
+	incl.Tooltip = "glob patterns for files to include in the search, separated by spaces or commas (e.g., *.go) -- if empty, all files are included -- patterns without a / match the file name at any level, otherwise the path relative to the project root, with ** matching any number of directories"
+
+	inct := fl.AddNewChild(gi.KiT_TextField, "include").(*gi.TextField)
+	inct.SetStretchMaxWidth()
+	inct.Tooltip = incl.Tooltip
+	inct.TextFieldSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			tf := send.(*gi.TextField)
+			fvv.Params().Include = FindPatternsFromString(tf.Text())
+		}
+	})
+
+	excl := fl.AddNewChild(gi.KiT_Label, "exclude-lbl").(*gi.Label)
+	excl.SetText("Exclude:")
+	excl.Tooltip = "glob patterns for files and directories to exclude from the search, separated by spaces or commas (e.g., vendor *_test.go) -- same syntax as Include"
+
+	exct := fl.AddNewChild(gi.KiT_TextField, "exclude").(*gi.TextField)
+	exct.SetStretchMaxWidth()
+	exct.Tooltip = excl.Tooltip
+	exct.TextFieldSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			tf := send.(*gi.TextField)
+			fvv.Params().Exclude = FindPatternsFromString(tf.Text())
+		}
+	})
+
+	ig := fl.AddNewChild(gi.KiT_CheckBox, "use-ignore").(*gi.CheckBox)
+	ig.SetText("Use .gitignore")
+	ig.Tooltip = "honor .gitignore and .ignore files -- files and directories matching their patterns are not searched"
+	ig.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().UseIgnore = cb.IsChecked()
+		}
+	})
+
+	cd := fl.AddNewChild(gi.KiT_CheckBox, "closed-dirs").(*gi.CheckBox)
+	cd.SetText("All on Disk")
+	cd.Tooltip = "search everything on disk, including folders that are closed in the file browser -- these are read directly from disk without opening them"
+	cd.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().ClosedDirs = cb.IsChecked()
+		}
+	})
+
+	ix := fl.AddNewChild(gi.KiT_CheckBox, "index").(*gi.CheckBox)
+	ix.SetText("Index")
+	ix.Tooltip = "use a persistent trigram index of file contents to skip files that cannot match, without reading them -- the index is saved next to the project file, and updated as files change"
+	ix.ButtonSig.Connect(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			fvv, _ := recv.Embed(KiT_FindView).(*FindView)
+			cb := send.(*gi.CheckBox)
+			fvv.Params().Index = cb.IsChecked()
+		}
+	})
 	//	vvb := vv.AsValueViewBase()
 	//	vvb.ViewSig.ConnectOnly(fv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 	//		fvv, _ := recv.Embed(KiT_FindView).(*FindView)
@@ -588,6 +1085,14 @@ func (fv *FindView) ConfigToolbar() {
 
 }
 
+// FindPatternsFromString returns the list of glob patterns in given string,
+// separated by spaces or commas
+func FindPatternsFromString(pats string) []string {
+	return strings.FieldsFunc(pats, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
 // FindViewProps are style properties for FindView
 var FindViewProps = ki.Props{
 	"EnumType:Flag":    gi.KiT_NodeFlags,
@@ -0,0 +1,655 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"image"
+	"sort"
+	"unicode"
+
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/lex"
+	"github.com/goki/pi/parse"
+	"github.com/goki/pi/token"
+)
+
+// Fold is a region of lines that can be folded, which hides all of its
+// lines after the first
+type Fold struct {
+	St int `desc:"first line of the region, which remains visible when folded"`
+	Ed int `desc:"last line of the region"`
+}
+
+// Contains returns true if the fold region contains given line
+func (fl Fold) Contains(ln int) bool {
+	return ln >= fl.St && ln <= fl.Ed
+}
+
+// Hides returns true if given line is hidden when the region is folded
+func (fl Fold) Hides(ln int) bool {
+	return ln > fl.St && ln <= fl.Ed
+}
+
+// FoldAstNames are the names of the nodes in the Ast from the pi parser whose
+// source regions can be folded: functions, types and blocks
+var FoldAstNames = map[string]bool{
+	"FuncDecl":       true,
+	"MethDecl":       true,
+	"FuncLit":        true,
+	"TypeDecl":       true,
+	"StructType":     true,
+	"InterfaceType":  true,
+	"Block":          true,
+	"Imports":        true,
+	"Consts":         true,
+	"Vars":           true,
+	"Types":          true,
+	"CompositeLit":   true,
+	"SwitchExpr":     true,
+	"SwitchInit":     true,
+	"SwitchTypeName": true,
+	"SwitchTypeAnon": true,
+	"SelectStmt":     true,
+}
+
+// AstFolds returns the fold regions for the nodes in given Ast that are
+// in FoldAstNames and span more than one line
+func AstFolds(ast *parse.Ast) []Fold {
+	var fls []Fold
+	ast.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d interface{}) bool {
+		an, ok := k.(*parse.Ast)
+		if !ok || level == 0 || !FoldAstNames[an.Nm] {
+			return ki.Continue
+		}
+		if an.SrcReg.St.Ln < an.SrcReg.Ed.Ln {
+			fls = append(fls, Fold{St: an.SrcReg.St.Ln, Ed: an.SrcReg.Ed.Ln})
+		}
+		return ki.Continue
+	})
+	return fls
+}
+
+// CommentFolds returns the fold regions for blocks of two or more lines
+// that only have comments, using the lexical tags for each line
+func CommentFolds(lines [][]rune, tags []lex.Line) []Fold {
+	var fls []Fold
+	st := -1
+	for ln := 0; ln <= len(lines); ln++ {
+		if ln < len(lines) && ln < len(tags) && commentLine(lines[ln], tags[ln]) {
+			if st < 0 {
+				st = ln
+			}
+			continue
+		}
+		if st >= 0 && ln-1 > st {
+			fls = append(fls, Fold{St: st, Ed: ln - 1})
+		}
+		st = -1
+	}
+	return fls
+}
+
+// commentLine returns true if the text of given line is all in a comment
+func commentLine(txt []rune, tags lex.Line) bool {
+	st := 0
+	for st < len(txt) && unicode.IsSpace(txt[st]) {
+		st++
+	}
+	ed := len(txt)
+	for ed > st && unicode.IsSpace(txt[ed-1]) {
+		ed--
+	}
+	if st == ed {
+		return false
+	}
+	for _, tg := range tags {
+		if tg.Tok.Tok.InCat(token.Comment) && tg.St <= st && tg.Ed >= ed {
+			return true
+		}
+	}
+	return false
+}
+
+// IndentFolds returns the fold regions for the lines that are followed by
+// more indented lines, ending with the last line before one that is not more
+// indented -- blank lines do not end regions.  Tabs are given tab size.
+func IndentFolds(lines [][]rune, tabSz int) []Fold {
+	nl := len(lines)
+	inds := make([]int, nl)
+	for ln, txt := range lines {
+		inds[ln] = indentWidth(txt, tabSz)
+	}
+	var fls []Fold
+	for ln := 0; ln < nl; ln++ {
+		if inds[ln] < 0 {
+			continue
+		}
+		ed := -1
+		for nx := ln + 1; nx < nl; nx++ {
+			if inds[nx] < 0 {
+				continue
+			}
+			if inds[nx] <= inds[ln] {
+				break
+			}
+			ed = nx
+		}
+		if ed > ln {
+			fls = append(fls, Fold{St: ln, Ed: ed})
+		}
+	}
+	return fls
+}
+
+// indentWidth returns the width of the indentation of given line, with
+// tabs of given size, or -1 if the line is blank
+func indentWidth(txt []rune, tabSz int) int {
+	wd := 0
+	for _, r := range txt {
+		switch {
+		case r == '\t':
+			wd += tabSz - wd%tabSz
+		case unicode.IsSpace(r):
+			wd++
+		default:
+			return wd
+		}
+	}
+	return -1
+}
+
+// MergeFolds merges given lists of fold regions into one list, sorted by
+// starting line, with only the largest region for each starting line
+func MergeFolds(flss ...[]Fold) []Fold {
+	eds := make(map[int]int)
+	for _, fls := range flss {
+		for _, fl := range fls {
+			if fl.Ed <= fl.St {
+				continue
+			}
+			if ed, has := eds[fl.St]; !has || fl.Ed > ed {
+				eds[fl.St] = fl.Ed
+			}
+		}
+	}
+	fls := make([]Fold, 0, len(eds))
+	for st, ed := range eds {
+		fls = append(fls, Fold{St: st, Ed: ed})
+	}
+	sort.Slice(fls, func(i, j int) bool {
+		return fls[i].St < fls[j].St
+	})
+	return fls
+}
+
+// HiddenLines returns which of given number of lines are hidden by given
+// folded regions
+func HiddenLines(folded []Fold, nl int) []bool {
+	hid := make([]bool, nl)
+	for _, fl := range folded {
+		for ln := fl.St + 1; ln <= fl.Ed && ln < nl; ln++ {
+			hid[ln] = true
+		}
+	}
+	return hid
+}
+
+// AdjustFolds returns given folded regions adjusted for an edit starting
+// at given line, which inserts dl lines after it, or deletes -dl lines
+// after it if negative: regions after the edit are moved, and those that the
+// edit is within are unfolded.
+func AdjustFolds(folded []Fold, ln, dl int) []Fold {
+	ed := ln
+	if dl < 0 {
+		ed -= dl
+	}
+	var fls []Fold
+	for _, fl := range folded {
+		switch {
+		case fl.Ed < ln, dl == 0 && fl.St == ln: // before, or within first line
+		case fl.St > ed:
+			fl.St += dl
+			fl.Ed += dl
+		default:
+			continue
+		}
+		fls = append(fls, fl)
+	}
+	return fls
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  TextView folding
+
+// AvailFolds returns the regions that can be folded in the text, computed
+// from the Ast of the pi parser where available, falling back on
+// indentation, along with blocks of comments
+func (tv *TextView) AvailFolds() []Fold {
+	if tv.Folds != nil || tv.Buf == nil {
+		return tv.Folds
+	}
+	nl := tv.Buf.NumLines()
+	lines := make([][]rune, nl)
+	for ln := range lines {
+		lines[ln] = tv.Buf.Line(ln)
+	}
+	var fls []Fold
+	if tv.Buf.Hi.UsingPi() {
+		pfs := tv.Buf.PiState.Done()
+		fls = AstFolds(&pfs.Ast)
+	}
+	if len(fls) == 0 {
+		fls = IndentFolds(lines, tv.Sty.Text.TabSize)
+	}
+	tv.Buf.MarkupMu.RLock()
+	cfls := CommentFolds(lines, tv.Buf.HiTags)
+	tv.Buf.MarkupMu.RUnlock()
+	tv.Folds = MergeFolds(fls, cfls)
+	return tv.Folds
+}
+
+// FoldAt returns the innermost region that can be folded containing given line
+func (tv *TextView) FoldAt(ln int) (Fold, bool) {
+	var fold Fold
+	got := false
+	for _, fl := range tv.AvailFolds() {
+		if fl.St > ln {
+			break
+		}
+		if fl.Contains(ln) && (!got || fl.St > fold.St) {
+			fold = fl
+			got = true
+		}
+	}
+	return fold, got
+}
+
+// IsFolded returns true if a folded region starts at given line
+func (tv *TextView) IsFolded(ln int) bool {
+	for _, fl := range tv.Folded {
+		if fl.St == ln {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHidden returns true if given line is hidden within a folded region
+func (tv *TextView) IsHidden(ln int) bool {
+	for _, fl := range tv.Folded {
+		if fl.Hides(ln) {
+			return true
+		}
+	}
+	return false
+}
+
+// FoldLine folds the innermost region containing given line that is not
+// already folded
+func (tv *TextView) FoldLine(ln int) {
+	var fold Fold
+	got := false
+	for _, fl := range tv.AvailFolds() {
+		if fl.St > ln {
+			break
+		}
+		if fl.Contains(ln) && !tv.IsFolded(fl.St) && (!got || fl.St > fold.St) {
+			fold = fl
+			got = true
+		}
+	}
+	if !got {
+		return
+	}
+	tv.Folded = append(tv.Folded, fold)
+	tv.RefreshFolds()
+}
+
+// UnfoldLine unfolds the folded regions that start at or hide given line
+func (tv *TextView) UnfoldLine(ln int) {
+	var fls []Fold
+	for _, fl := range tv.Folded {
+		if !fl.Contains(ln) {
+			fls = append(fls, fl)
+		}
+	}
+	if len(fls) == len(tv.Folded) {
+		return
+	}
+	tv.Folded = fls
+	tv.RefreshFolds()
+}
+
+// ToggleFold unfolds the region starting at given line if it is folded,
+// and otherwise folds the innermost region containing it
+func (tv *TextView) ToggleFold(ln int) {
+	if tv.IsFolded(ln) {
+		tv.UnfoldLine(ln)
+	} else {
+		tv.FoldLine(ln)
+	}
+}
+
+// FoldAll folds all of the regions that can be folded
+func (tv *TextView) FoldAll() {
+	fls := tv.AvailFolds()
+	if len(fls) == 0 {
+		return
+	}
+	tv.Folded = append([]Fold{}, fls...)
+	tv.RefreshFolds()
+}
+
+// UnfoldAll unfolds all of the folded regions
+func (tv *TextView) UnfoldAll() {
+	if len(tv.Folded) == 0 {
+		return
+	}
+	tv.Folded = nil
+	tv.RefreshFolds()
+}
+
+// RefreshFolds lays out and renders the text after a change in the folded
+// regions, moving the cursor out of any that hide it, and saves the
+// folded regions in the project prefs
+func (tv *TextView) RefreshFolds() {
+	tv.SaveFolds()
+	if tv.Buf == nil {
+		return
+	}
+	for _, fl := range tv.Folded {
+		if fl.Hides(tv.CursorPos.Ln) {
+			tv.SetCursorShow(lex.Pos{Ln: fl.St})
+		}
+	}
+	tv.FoldCursorLn = tv.CursorPos.Ln
+	if !tv.IsVisible() || tv.NLines == 0 {
+		return
+	}
+	tv.LayoutAllLines(false)
+	tv.LayoutFolds()
+	tv.RenderAllLines()
+	tv.RenderFolds()
+	tv.RenderBookmarks()
+}
+
+// LayoutFolds lays out the lines hidden by folded regions, with no text and
+// at the position of the first line of their region, and moves all of the
+// lines after them up -- returns true if the layout changed
+func (tv *TextView) LayoutFolds() bool {
+	if len(tv.Folded) == 0 || tv.NLines == 0 || len(tv.Offs) != tv.NLines || len(tv.Renders) != tv.NLines {
+		return false
+	}
+	hid := HiddenLines(tv.Folded, tv.NLines)
+	chg := false
+	off := float32(0)
+	vis := float32(0)
+	for ln := 0; ln < tv.NLines; ln++ {
+		noff := off
+		if hid[ln] {
+			noff = vis
+			if len(tv.Renders[ln].Spans) > 0 {
+				tv.Renders[ln] = girl.Text{}
+				chg = true
+			}
+		} else {
+			vis = off
+			off += mat32.Max(tv.Renders[ln].Size.Y, tv.LineHeight)
+		}
+		if tv.Offs[ln] != noff {
+			tv.Offs[ln] = noff
+			chg = true
+		}
+	}
+	if chg {
+		extraHalf := tv.LineHeight * 0.5 * float32(tv.VisSize.Y)
+		nwSz := mat32.Vec2{float32(tv.LinesSize.X), off + extraHalf}.ToPointCeil()
+		tv.ResizeIfNeeded(nwSz)
+	}
+	return chg
+}
+
+// foldMarkX returns the horizontal offset of the fold markers, relative to
+// the start of the line numbers, and their size
+func (tv *TextView) foldMarkX() (float32, float32) {
+	ch := tv.Sty.Font.Face.Metrics.Ch
+	return tv.Sty.BoxSpace() + float32(tv.LineNoDigs)*ch + 0.5*ch, ch
+}
+
+// RenderFolds renders the fold markers in the line numbers of the visible
+// lines that start regions that can be folded, with an ellipsis after
+// those that are folded
+func (tv *TextView) RenderFolds() {
+	if tv.Buf == nil || !tv.HasLineNos() || !tv.IsVisible() || tv.NLines == 0 || len(tv.Offs) != tv.NLines {
+		return
+	}
+	fls := tv.AvailFolds()
+	if len(fls) == 0 && len(tv.Folded) == 0 {
+		return
+	}
+	starts := make(map[int]bool, len(fls))
+	for _, fl := range fls {
+		starts[fl.St] = true
+	}
+	hid := HiddenLines(tv.Folded, tv.NLines)
+	rs := tv.Render()
+	rs.PushBounds(tv.VpBBox)
+	rs.Lock()
+	pc := &rs.Paint
+	fsty := pc.FillStyle
+	spos := tv.RenderStartPos()
+	mx, msz := tv.foldMarkX()
+	fnt := tv.Sty.Font
+	fnt.Color.SetString("grey", nil)
+	bgclr := tv.Sty.Font.BgColor.Color.Highlight(10)
+	var tr girl.Text
+	for ln := 0; ln < tv.NLines; ln++ {
+		if hid[ln] {
+			continue
+		}
+		y := spos.Y + tv.Offs[ln]
+		if int(mat32.Ceil(y+tv.LineHeight)) < tv.VpBBox.Min.Y || int(mat32.Floor(y)) > tv.VpBBox.Max.Y {
+			continue
+		}
+		folded := tv.IsFolded(ln)
+		if folded { // hidden lines had their numbers drawn on top of this one
+			lnpos := mat32.Vec2{float32(tv.VpBBox.Min.X), y}
+			pc.FillBoxColor(rs, lnpos, mat32.Vec2{tv.LineNoOff - tv.Sty.BoxSpace(), tv.LineHeight}, bgclr)
+			tv.RenderLineNo(ln, false, false)
+		}
+		if !folded && !starts[ln] {
+			continue
+		}
+		x := float32(tv.VpBBox.Min.X) + mx
+		cy := y + 0.5*tv.LineHeight
+		pc.FillStyle.SetColor(tv.Sty.Font.Color)
+		if folded {
+			pc.MoveTo(rs, x, cy-0.4*msz)
+			pc.LineTo(rs, x+0.7*msz, cy)
+			pc.LineTo(rs, x, cy+0.4*msz)
+		} else {
+			pc.MoveTo(rs, x, cy-0.3*msz)
+			pc.LineTo(rs, x+0.8*msz, cy-0.3*msz)
+			pc.LineTo(rs, x+0.4*msz, cy+0.4*msz)
+		}
+		pc.ClosePath(rs)
+		pc.Fill(rs)
+		if folded {
+			pos := tv.CharStartPos(lex.Pos{Ln: ln, Ch: len(tv.Buf.Line(ln))})
+			pos.Y = y
+			tr.SetString(" ...", &fnt, &tv.Sty.UnContext, &tv.Sty.Text, true, 0, 1)
+			tr.Render(rs, pos)
+		}
+	}
+	pc.FillStyle = fsty
+	rs.Unlock()
+	rs.PopBounds()
+}
+
+// FoldMarkAt returns the line of the fold marker at given point, relative to
+// the view as for PixelToCursor, and true if there is a marker there
+func (tv *TextView) FoldMarkAt(pt image.Point) (int, bool) {
+	if !tv.HasLineNos() || tv.Buf == nil {
+		return 0, false
+	}
+	mx, _ := tv.foldMarkX()
+	if pt.X < int(mx) || pt.X >= int(tv.LineNoOff) {
+		return 0, false
+	}
+	ln := tv.PixelToCursor(pt).Ln
+	if tv.IsFolded(ln) {
+		return ln, true
+	}
+	for _, fl := range tv.AvailFolds() {
+		if fl.St == ln {
+			return ln, true
+		}
+	}
+	return 0, false
+}
+
+// FoldsBufSig updates the folds for given signal from the buffer: regions
+// that can be folded are recomputed, and folded regions are adjusted for
+// edits, which are then laid out again
+func (tv *TextView) FoldsBufSig(sig giv.TextBufSignals, data interface{}) {
+	switch sig {
+	case giv.TextBufNew:
+		tv.Folds = nil
+		tv.Folded = nil
+		tv.RestoreFolds()
+	case giv.TextBufInsert, giv.TextBufDelete:
+		tv.Folds = nil
+		tbe, ok := data.(*textbuf.Edit)
+		if !ok || len(tv.Folded) == 0 {
+			return
+		}
+		dl := tbe.Reg.End.Ln - tbe.Reg.Start.Ln
+		if tbe.Delete {
+			dl = -dl
+		}
+		nf := len(tv.Folded)
+		tv.Folded = AdjustFolds(tv.Folded, tbe.Reg.Start.Ln, dl)
+		if len(tv.Folded) != nf || dl != 0 {
+			tv.RefreshFolds()
+		}
+	case giv.TextBufMarkUpdt:
+		tv.Folds = nil
+	case giv.TextBufClosed:
+		tv.SaveFolds()
+	}
+}
+
+// FoldsCursorMoved moves the cursor out of any folded region it moved into:
+// moving one line skips over the region, and otherwise (e.g., jumping to a
+// search match) the regions are unfolded
+func (tv *TextView) FoldsCursorMoved() {
+	prv := tv.FoldCursorLn
+	ln := tv.CursorPos.Ln
+	tv.FoldCursorLn = ln
+	if !tv.IsHidden(ln) {
+		return
+	}
+	if ln-prv != 1 && prv-ln != 1 {
+		tv.UnfoldLine(ln)
+		return
+	}
+	for _, fl := range tv.Folded {
+		if !fl.Hides(ln) {
+			continue
+		}
+		if ln < prv || fl.Ed+1 >= tv.NLines {
+			ln = fl.St
+		} else {
+			ln = fl.Ed + 1
+		}
+	}
+	if tv.IsHidden(ln) { // within nested folds
+		for _, fl := range tv.Folded {
+			if fl.Hides(ln) {
+				ln = fl.St
+			}
+		}
+	}
+	tv.SetCursorShow(lex.Pos{Ln: ln, Ch: tv.CursorPos.Ch})
+	tv.FoldCursorLn = tv.CursorPos.Ln
+	tv.RenderAllLines()
+	tv.RenderFolds()
+	tv.RenderBookmarks()
+}
+
+// SaveFolds saves the folded regions of the file in the project prefs
+func (tv *TextView) SaveFolds() {
+	if tv.Buf == nil || tv.Buf.Filename == "" {
+		return
+	}
+	ge, ok := ParentGide(tv)
+	if !ok {
+		return
+	}
+	pf := ge.ProjPrefs()
+	fn := ProjRelPath(ge.ProjPrefs(), string(tv.Buf.Filename))
+	if len(tv.Folded) == 0 {
+		delete(pf.Folds, fn)
+		return
+	}
+	if pf.Folds == nil {
+		pf.Folds = make(map[string][]Fold)
+	}
+	pf.Folds[fn] = append([]Fold{}, tv.Folded...)
+}
+
+// RestoreFolds restores the folded regions of the file from the project
+// prefs, dropping any that are no longer within the text
+func (tv *TextView) RestoreFolds() {
+	if tv.Buf == nil || tv.Buf.Filename == "" {
+		return
+	}
+	ge, ok := ParentGide(tv)
+	if !ok {
+		return
+	}
+	nl := tv.Buf.NumLines()
+	tv.Folded = nil
+	for _, fl := range ge.ProjPrefs().Folds[ProjRelPath(ge.ProjPrefs(), string(tv.Buf.Filename))] {
+		if fl.St < fl.Ed && fl.Ed < nl {
+			tv.Folded = append(tv.Folded, fl)
+		}
+	}
+	tv.FoldCursorLn = tv.CursorPos.Ln
+}
+
+// SetBuf sets the TextBuf that this is a view of, restoring the folded
+// regions of its file from the project
+func (tv *TextView) SetBuf(buf *giv.TextBuf) {
+	if buf != nil && tv.Buf == buf {
+		return
+	}
+	tv.SaveFolds()
+	tv.Folds = nil
+	tv.Folded = nil
+	tv.TextView.SetBuf(buf)
+	if buf != nil {
+		buf.TextBufSig.Connect(tv.This(), TextViewBufSigRecv)
+		tv.RestoreFolds()
+	}
+}
+
+// TextViewBufSigRecv receives a signal from the buffer and updates view
+// accordingly, including its folds and the fields of any snippet
+func TextViewBufSigRecv(rvwki ki.Ki, sbufki ki.Ki, sig int64, data interface{}) {
+	tv := rvwki.Embed(KiT_TextView).(*TextView)
+	if giv.TextBufSignals(sig) == giv.TextBufClosed {
+		tv.FoldsBufSig(giv.TextBufClosed, data)
+	}
+	giv.TextViewBufSigRecv(rvwki, sbufki, sig, data)
+	if giv.TextBufSignals(sig) != giv.TextBufClosed {
+		tv.FoldsBufSig(giv.TextBufSignals(sig), data)
+	}
+	tv.SnippetBufSig(giv.TextBufSignals(sig), data)
+}
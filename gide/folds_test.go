@@ -0,0 +1,102 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"testing"
+
+	"github.com/goki/pi/lex"
+	"github.com/goki/pi/token"
+)
+
+func runeLines(strs ...string) [][]rune {
+	lines := make([][]rune, len(strs))
+	for i, s := range strs {
+		lines[i] = []rune(s)
+	}
+	return lines
+}
+
+func TestIndentFolds(t *testing.T) {
+	lines := runeLines(
+		"def foo():",
+		"    x = 1",
+		"",
+		"    if x:",
+		"\ty = 2",
+		"foo()",
+	)
+	fls := IndentFolds(lines, 8)
+	exp := []Fold{{0, 4}, {3, 4}}
+	if len(fls) != len(exp) {
+		t.Fatalf("should have folds: %v  was: %v", exp, fls)
+	}
+	for i, fl := range fls {
+		if fl != exp[i] {
+			t.Errorf("fold %d should be: %v  was: %v", i, exp[i], fl)
+		}
+	}
+}
+
+func TestCommentFolds(t *testing.T) {
+	lines := runeLines(
+		"// a",
+		"  // b",
+		"x := 1 // c",
+		"// d",
+		"/* e",
+		"f */",
+	)
+	cmt := func(st, ed int) lex.Line {
+		return lex.Line{lex.Lex{Tok: token.KeyToken{Tok: token.Comment}, St: st, Ed: ed}}
+	}
+	tags := []lex.Line{
+		cmt(0, 4),
+		cmt(2, 6),
+		{lex.Lex{Tok: token.KeyToken{Tok: token.Name}, St: 0, Ed: 1}, lex.Lex{Tok: token.KeyToken{Tok: token.Comment}, St: 7, Ed: 11}},
+		cmt(0, 4),
+		cmt(0, 4),
+		cmt(0, 4),
+	}
+	fls := CommentFolds(lines, tags)
+	exp := []Fold{{0, 1}, {3, 5}}
+	if len(fls) != len(exp) {
+		t.Fatalf("should have folds: %v  was: %v", exp, fls)
+	}
+	for i, fl := range fls {
+		if fl != exp[i] {
+			t.Errorf("fold %d should be: %v  was: %v", i, exp[i], fl)
+		}
+	}
+}
+
+func TestMergeFolds(t *testing.T) {
+	fls := MergeFolds([]Fold{{4, 6}, {0, 8}, {2, 2}}, []Fold{{4, 9}, {1, 3}})
+	exp := []Fold{{0, 8}, {1, 3}, {4, 9}}
+	if len(fls) != len(exp) {
+		t.Fatalf("should have folds: %v  was: %v", exp, fls)
+	}
+	for i, fl := range fls {
+		if fl != exp[i] {
+			t.Errorf("fold %d should be: %v  was: %v", i, exp[i], fl)
+		}
+	}
+}
+
+func TestAdjustFolds(t *testing.T) {
+	folded := []Fold{{2, 4}, {8, 10}}
+	fls := AdjustFolds(folded, 6, 2) // insert 2 lines at 6
+	if len(fls) != 2 || fls[0] != (Fold{2, 4}) || fls[1] != (Fold{10, 12}) {
+		t.Errorf("insert should move second fold, was: %v", fls)
+	}
+	fls = AdjustFolds(folded, 3, 1) // insert within first
+	if len(fls) != 1 || fls[0] != (Fold{9, 11}) {
+		t.Errorf("insert within should unfold first fold, was: %v", fls)
+	}
+	fls = AdjustFolds(folded, 5, -2) // delete lines 6..7
+	if len(fls) != 2 || fls[1] != (Fold{6, 8}) {
+		t.Errorf("delete should move second fold, was: %v", fls)
+	}
+}
@@ -48,6 +48,11 @@ type Gide interface {
 	// SetStatus updates the statusbar label with given message, along with other status info
 	SetStatus(msg string)
 
+	// RecycleTab returns a tab with given name, first by looking for an existing one,
+	// and if not found, making a new one with widget of given type.
+	// If sel, then select it.  returns widget for tab.
+	RecycleTab(label string, typ reflect.Type, sel bool) gi.Node2D
+
 	// SelectTabByName Selects given main tab, and returns all of its contents as well.
 	SelectTabByName(label string) gi.Node2D
 
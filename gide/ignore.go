@@ -0,0 +1,225 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// IgnoreFileNames are the names of the files, within any directory of the
+// project, whose patterns are honored when FindParams.UseIgnore is set.
+// The syntax is that of .gitignore files.
+var IgnoreFileNames = []string{".gitignore", ".ignore"}
+
+// IgnoreRule is one pattern from an ignore file
+type IgnoreRule struct {
+	Pattern string         `desc:"original pattern as given in the file"`
+	Negate  bool           `desc:"pattern started with ! -- re-includes anything matched by earlier patterns"`
+	DirOnly bool           `desc:"pattern ended with / -- only matches directories"`
+	Re      *regexp.Regexp `desc:"compiled version of the pattern, matched against path relative to the directory of the ignore file"`
+}
+
+// IgnoreRules are all the rules from the ignore files in one directory
+type IgnoreRules struct {
+	Dir   string        `desc:"directory containing the ignore files -- rules apply to paths relative to this"`
+	Rules []*IgnoreRule `desc:"the rules, in order -- the last matching rule wins"`
+}
+
+// Ignorer determines whether files are ignored according to the ignore files
+// (IgnoreFileNames) found from Root on down to the file.  Rules are read lazily
+// and cached per directory, so one Ignorer should be used for an entire search.
+// It is safe for concurrent use by the goroutines of a search.
+type Ignorer struct {
+	Root string                  `desc:"root directory -- ignore files above this are not consulted"`
+	Dirs map[string]*IgnoreRules `desc:"cache of rules per directory -- nil if directory has no ignore files"`
+	mu   sync.RWMutex
+}
+
+// NewIgnorer returns a new Ignorer for given root path
+func NewIgnorer(root string) *Ignorer {
+	return &Ignorer{Root: filepath.Clean(root), Dirs: make(map[string]*IgnoreRules)}
+}
+
+// IsIgnored returns true if given full file path is ignored, based on all the
+// ignore files in the directories from Root down to the directory containing
+// the file.  Deeper files take precedence, as do later patterns within a file.
+func (ig *Ignorer) IsIgnored(fpath string, isDir bool) bool {
+	fpath = filepath.Clean(fpath)
+	if fpath == ig.Root || !strings.HasPrefix(fpath, ig.Root+string(filepath.Separator)) {
+		return false
+	}
+	var dirs []string
+	for dir := filepath.Dir(fpath); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		if dir == ig.Root || len(dir) <= len(ig.Root) {
+			break
+		}
+	}
+	ignored := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		ir := ig.DirRules(dirs[i])
+		if ir == nil {
+			continue
+		}
+		rel, err := filepath.Rel(ir.Dir, fpath)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, rl := range ir.Rules {
+			if rl.DirOnly && !isDir {
+				continue
+			}
+			if rl.Re.MatchString(rel) {
+				ignored = !rl.Negate
+			}
+		}
+	}
+	return ignored
+}
+
+// DirRules returns the rules for given directory, reading the ignore files
+// there if not already cached -- returns nil if there are none.
+func (ig *Ignorer) DirRules(dir string) *IgnoreRules {
+	ig.mu.RLock()
+	ir, has := ig.Dirs[dir]
+	ig.mu.RUnlock()
+	if has {
+		return ir
+	}
+	for _, fn := range IgnoreFileNames {
+		rls := ReadIgnoreFile(filepath.Join(dir, fn))
+		if len(rls) == 0 {
+			continue
+		}
+		if ir == nil {
+			ir = &IgnoreRules{Dir: dir}
+		}
+		ir.Rules = append(ir.Rules, rls...)
+	}
+	ig.mu.Lock()
+	ig.Dirs[dir] = ir
+	ig.mu.Unlock()
+	return ir
+}
+
+// ReadIgnoreFile reads the rules from given ignore file -- returns nil if
+// the file does not exist or has no valid rules.
+func ReadIgnoreFile(fname string) []*IgnoreRule {
+	fp, err := os.Open(fname)
+	if err != nil {
+		return nil
+	}
+	defer fp.Close()
+	var rls []*IgnoreRule
+	scan := bufio.NewScanner(fp)
+	for scan.Scan() {
+		rl := ParseIgnoreRule(scan.Text())
+		if rl != nil {
+			rls = append(rls, rl)
+		}
+	}
+	return rls
+}
+
+// ParseIgnoreRule parses one line of an ignore file, returning nil for blank
+// lines, comments, or invalid patterns.
+func ParseIgnoreRule(ln string) *IgnoreRule {
+	ln = strings.TrimRight(ln, " \t\r")
+	if ln == "" || ln[0] == '#' {
+		return nil
+	}
+	rl := &IgnoreRule{Pattern: ln}
+	switch {
+	case ln[0] == '!':
+		rl.Negate = true
+		ln = ln[1:]
+	case strings.HasPrefix(ln, `\!`) || strings.HasPrefix(ln, `\#`):
+		ln = ln[1:]
+	}
+	if strings.HasSuffix(ln, "/") {
+		rl.DirOnly = true
+		ln = strings.TrimSuffix(ln, "/")
+	}
+	if ln == "" {
+		return nil
+	}
+	anchored := strings.Contains(ln, "/")
+	ln = strings.TrimPrefix(ln, "/")
+	rs := GlobToRegexp(ln)
+	if anchored {
+		rs = "^" + rs + "$"
+	} else {
+		rs = "^(?:.*/)?" + rs + "$"
+	}
+	re, err := regexp.Compile(rs)
+	if err != nil {
+		return nil
+	}
+	rl.Re = re
+	return rl
+}
+
+// GlobToRegexp converts a glob pattern, using / as the path separator, into
+// an equivalent (unanchored) regular expression string.  In addition to the
+// standard * ? and [ ] elements, ** matches any number of directories.
+func GlobToRegexp(glob string) string {
+	var b strings.Builder
+	rn := []rune(glob)
+	sz := len(rn)
+	for i := 0; i < sz; i++ {
+		r := rn[i]
+		switch r {
+		case '*':
+			if i+1 < sz && rn[i+1] == '*' {
+				i++
+				if i+1 < sz && rn[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			ei := -1
+			for j := i + 1; j < sz; j++ {
+				if rn[j] == ']' {
+					ei = j
+					break
+				}
+			}
+			if ei < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			cls := rn[i+1 : ei]
+			b.WriteRune('[')
+			if len(cls) > 0 && (cls[0] == '!' || cls[0] == '^') {
+				b.WriteRune('^')
+				cls = cls[1:]
+			}
+			b.WriteString(strings.Replace(string(cls), `\`, `\\`, -1))
+			b.WriteRune(']')
+			i = ei
+		case '\\':
+			if i+1 < sz {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(rn[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRule(t *testing.T) {
+	tests := []struct {
+		pat   string
+		path  string
+		isDir bool
+		match bool
+	}{
+		{"*.o", "main.o", false, true},
+		{"*.o", "sub/dir/main.o", false, true},
+		{"*.o", "main.go", false, false},
+		{"/build", "build", true, true},
+		{"/build", "sub/build", true, false},
+		{"docs/*.md", "docs/README.md", false, true},
+		{"docs/*.md", "docs/sub/README.md", false, false},
+		{"docs/**/*.md", "docs/sub/README.md", false, true},
+		{"**/testdata", "a/b/testdata", true, true},
+		{"logs/", "logs", true, true},
+		{"logs/", "logs", false, false},
+		{"file[0-9].txt", "file3.txt", false, true},
+		{"file[!0-9].txt", "file3.txt", false, false},
+	}
+	for _, tst := range tests {
+		rl := ParseIgnoreRule(tst.pat)
+		if rl == nil {
+			t.Errorf("pattern: %v did not parse\n", tst.pat)
+			continue
+		}
+		match := (!rl.DirOnly || tst.isDir) && rl.Re.MatchString(tst.path)
+		if match != tst.match {
+			t.Errorf("pattern: %v path: %v match should have been: %v  was: %v\n", tst.pat, tst.path, tst.match, match)
+		}
+	}
+	if ParseIgnoreRule("# comment") != nil || ParseIgnoreRule("   ") != nil {
+		t.Errorf("comments and blank lines should not parse as rules\n")
+	}
+}
+
+func TestIgnorer(t *testing.T) {
+	root, err := ioutil.TempDir("", "gide-ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	sub := filepath.Join(root, "sub")
+	os.MkdirAll(sub, 0755)
+	ioutil.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\nbuild/\n"), 0644)
+	ioutil.WriteFile(filepath.Join(sub, ".ignore"), []byte("!keep.log\n"), 0644)
+
+	ig := NewIgnorer(root)
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"out.log", false, true},
+		{"main.go", false, false},
+		{"build", true, true},
+		{"sub/other.log", false, true},
+		{"sub/keep.log", false, false},
+	}
+	for _, tst := range tests {
+		ign := ig.IsIgnored(filepath.Join(root, tst.path), tst.isDir)
+		if ign != tst.ignored {
+			t.Errorf("path: %v ignored should have been: %v  was: %v\n", tst.path, tst.ignored, ign)
+		}
+	}
+}
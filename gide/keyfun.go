@@ -32,28 +32,42 @@ import (
 type KeyFuns int32
 
 const (
-	KeyFunNil        KeyFuns = iota
-	KeyFunNeeds2             // special internal signal returned by KeyFun indicating need for second key
-	KeyFunNextPanel          // move to next panel to the right
-	KeyFunPrevPanel          // move to prev panel to the left
-	KeyFunFileOpen           // open a new file in active textview
-	KeyFunBufSelect          // select an open buffer to edit in active textview
-	KeyFunBufClone           // open active file in other view
-	KeyFunBufSave            // save active textview buffer to its file
-	KeyFunBufSaveAs          // save as active textview buffer to its file
-	KeyFunBufClose           // close active textview buffer
-	KeyFunExecCmd            // execute a command on active textview buffer
-	KeyFunRectCopy           // copy rectangle
-	KeyFunRectCut            // cut rectangle
-	KeyFunRectPaste          // paste rectangle
-	KeyFunRegCopy            // copy selection to named register
-	KeyFunRegPaste           // paste selection from named register
-	KeyFunCommentOut         // comment out region
-	KeyFunIndent             // indent region
-	KeyFunJump               // jump to line (same as gi.KeyFunJump)
-	KeyFunSetSplit           // set named splitter config
-	KeyFunBuildProj          // build overall project
-	KeyFunRunProj            // run overall project
+	KeyFunNil              KeyFuns = iota
+	KeyFunNeeds2                   // special internal signal returned by KeyFun indicating need for second key
+	KeyFunNextPanel                // move to next panel to the right
+	KeyFunPrevPanel                // move to prev panel to the left
+	KeyFunFileOpen                 // open a new file in active textview
+	KeyFunBufSelect                // select an open buffer to edit in active textview
+	KeyFunBufClone                 // open active file in other view
+	KeyFunBufSave                  // save active textview buffer to its file
+	KeyFunBufSaveAs                // save as active textview buffer to its file
+	KeyFunBufClose                 // close active textview buffer
+	KeyFunExecCmd                  // execute a command on active textview buffer
+	KeyFunRectCopy                 // copy rectangle
+	KeyFunRectCut                  // cut rectangle
+	KeyFunRectPaste                // paste rectangle
+	KeyFunRegCopy                  // copy selection to named register
+	KeyFunRegPaste                 // paste selection from named register
+	KeyFunCommentOut               // comment out region
+	KeyFunIndent                   // indent region
+	KeyFunJump                     // jump to line (same as gi.KeyFunJump)
+	KeyFunSetSplit                 // set named splitter config
+	KeyFunBuildProj                // build overall project
+	KeyFunRunProj                  // run overall project
+	KeyFunCursorAbove              // add a cursor on the line above
+	KeyFunCursorBelow              // add a cursor on the line below
+	KeyFunCursorNextMatch          // add a cursor at the next occurrence of the selection
+	KeyFunCursorSplitLines         // split selection into a cursor per line
+	KeyFunFoldToggle               // fold or unfold the region at the cursor
+	KeyFunFoldAll                  // fold all regions
+	KeyFunUnfoldAll                // unfold all regions
+	KeyFunSnippet                  // insert the snippet for the word before the cursor, or choose one
+	KeyFunNavBack                  // go back to the previous position in the navigation history
+	KeyFunNavForward               // go forward to the next position in the navigation history
+	KeyFunBookmarkToggle           // add or remove a numbered bookmark at the cursor line
+	KeyFunBookmarkNamed            // add a named bookmark at the cursor line
+	KeyFunBookmarkNext             // go to the next bookmark
+	KeyFunBookmarkPrev             // go to the previous bookmark
 	KeyFunsN
 )
 
@@ -491,257 +505,341 @@ var KeyMapsProps = ki.Props{
 // the lastest key functions bound to standard key chords.
 var StdKeyMaps = KeyMaps{
 	{"MacStd", "Standard Mac KeyMap", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+M", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+M", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+M", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+M", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+O", ""}:          KeyFunFileOpen,
-		KeySeq{"Control+M", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+M", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+M", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+M", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+S", ""}:          KeyFunBufSave,
-		KeySeq{"Shift+Control+S", ""}:    KeyFunBufSaveAs,
-		KeySeq{"Control+M", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+M", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+M", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+M", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+M", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+M", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+M", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+M", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+M", "n"}:         KeyFunBufClone,
-		KeySeq{"Control+M", "Control+N"}: KeyFunBufClone,
-		KeySeq{"Control+M", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+M", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+M", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+M", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+M", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+/", ""}:          KeyFunCommentOut,
-		KeySeq{"Control+M", "t"}:         KeyFunCommentOut,
-		KeySeq{"Control+M", "Control+T"}: KeyFunCommentOut,
-		KeySeq{"Control+M", "i"}:         KeyFunIndent,
-		KeySeq{"Control+M", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+M", "j"}:         KeyFunJump,
-		KeySeq{"Control+M", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+M", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+M", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+M", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+M", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+M", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+M", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+M", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+M", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+M", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+M", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+O", ""}:           KeyFunFileOpen,
+		KeySeq{"Control+M", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+M", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+M", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+M", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+S", ""}:           KeyFunBufSave,
+		KeySeq{"Shift+Control+S", ""}:     KeyFunBufSaveAs,
+		KeySeq{"Control+M", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+M", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+M", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+M", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+M", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+M", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+M", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+M", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+M", "n"}:          KeyFunBufClone,
+		KeySeq{"Control+M", "Control+N"}:  KeyFunBufClone,
+		KeySeq{"Control+M", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+M", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+M", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+M", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+M", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+/", ""}:           KeyFunCommentOut,
+		KeySeq{"Control+M", "t"}:          KeyFunCommentOut,
+		KeySeq{"Control+M", "Control+T"}:  KeyFunCommentOut,
+		KeySeq{"Control+M", "i"}:          KeyFunIndent,
+		KeySeq{"Control+M", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+M", "j"}:          KeyFunJump,
+		KeySeq{"Control+M", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+M", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+M", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+M", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+M", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+M", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+M", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+M", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+M", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+M", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+M", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+M", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+M", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+M", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+M", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+M", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+M", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+M", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+M", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+M", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+M", ","}:          KeyFunBookmarkPrev,
 	}},
 	{"MacEmacs", "Mac with emacs-style navigation -- emacs wins in conflicts", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+X", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+X", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+X", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+X", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+X", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+X", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+X", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+X", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+X", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+X", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+X", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+X", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+X", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+X", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+X", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+X", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+C", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+C", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+C", "o"}:         KeyFunBufClone,
-		KeySeq{"Control+C", "Control+O"}: KeyFunBufClone,
-		KeySeq{"Control+X", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+X", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+X", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+X", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+X", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+C", "k"}:         KeyFunCommentOut,
-		KeySeq{"Control+C", "Control+K"}: KeyFunCommentOut,
-		KeySeq{"Control+X", "i"}:         KeyFunIndent,
-		KeySeq{"Control+X", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+X", "j"}:         KeyFunJump,
-		KeySeq{"Control+X", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+X", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+X", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+X", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+X", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+X", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+X", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+X", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+X", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+X", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+X", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+X", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+X", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+X", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+X", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+X", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+X", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+X", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+X", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+X", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+X", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+X", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+X", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+C", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+C", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+C", "o"}:          KeyFunBufClone,
+		KeySeq{"Control+C", "Control+O"}:  KeyFunBufClone,
+		KeySeq{"Control+X", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+X", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+X", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+X", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+X", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+C", "k"}:          KeyFunCommentOut,
+		KeySeq{"Control+C", "Control+K"}:  KeyFunCommentOut,
+		KeySeq{"Control+X", "i"}:          KeyFunIndent,
+		KeySeq{"Control+X", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+X", "j"}:          KeyFunJump,
+		KeySeq{"Control+X", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+X", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+X", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+X", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+X", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+X", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+X", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+X", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+X", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+X", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+X", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+X", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+X", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+X", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+X", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+X", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+X", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+X", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+X", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+X", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+X", ","}:          KeyFunBookmarkPrev,
 	}},
 	{"LinuxEmacs", "Linux with emacs-style navigation -- emacs wins in conflicts", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+X", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+X", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+X", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+X", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+X", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+X", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+X", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+X", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+X", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+X", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+X", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+X", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+X", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+X", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+X", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+X", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+C", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+C", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+C", "o"}:         KeyFunBufClone,
-		KeySeq{"Control+C", "Control+O"}: KeyFunBufClone,
-		KeySeq{"Control+X", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+X", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+X", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+X", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+X", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+C", "k"}:         KeyFunCommentOut,
-		KeySeq{"Control+C", "Control+K"}: KeyFunCommentOut,
-		KeySeq{"Control+X", "i"}:         KeyFunIndent,
-		KeySeq{"Control+X", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+X", "j"}:         KeyFunJump,
-		KeySeq{"Control+X", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+X", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+X", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+M", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+M", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+M", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+M", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+X", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+X", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+X", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+X", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+X", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+X", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+X", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+X", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+X", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+X", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+X", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+X", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+X", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+X", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+X", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+X", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+C", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+C", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+C", "o"}:          KeyFunBufClone,
+		KeySeq{"Control+C", "Control+O"}:  KeyFunBufClone,
+		KeySeq{"Control+X", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+X", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+X", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+X", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+X", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+C", "k"}:          KeyFunCommentOut,
+		KeySeq{"Control+C", "Control+K"}:  KeyFunCommentOut,
+		KeySeq{"Control+X", "i"}:          KeyFunIndent,
+		KeySeq{"Control+X", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+X", "j"}:          KeyFunJump,
+		KeySeq{"Control+X", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+X", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+X", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+M", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+M", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+M", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+M", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+M", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+M", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+M", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+M", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+M", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+M", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+M", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+M", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+M", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+M", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+M", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+M", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+M", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+M", ","}:          KeyFunBookmarkPrev,
 	}},
 	{"LinuxStd", "Standard Linux KeySeqMap", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+M", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+M", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+M", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+M", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+O", ""}:          KeyFunFileOpen,
-		KeySeq{"Control+M", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+M", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+M", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+M", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+S", ""}:          KeyFunBufSave,
-		KeySeq{"Shift+Control+S", ""}:    KeyFunBufSaveAs,
-		KeySeq{"Control+M", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+M", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+M", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+M", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+M", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+M", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+M", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+M", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+M", "n"}:         KeyFunBufClone,
-		KeySeq{"Control+M", "Control+N"}: KeyFunBufClone,
-		KeySeq{"Control+M", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+M", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+M", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+M", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+M", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+/", ""}:          KeyFunCommentOut,
-		KeySeq{"Control+M", "t"}:         KeyFunCommentOut,
-		KeySeq{"Control+M", "Control+T"}: KeyFunCommentOut,
-		KeySeq{"Control+M", "i"}:         KeyFunIndent,
-		KeySeq{"Control+M", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+M", "j"}:         KeyFunJump,
-		KeySeq{"Control+M", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+M", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+M", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+M", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+M", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+M", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+M", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+M", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+M", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+M", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+M", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+O", ""}:           KeyFunFileOpen,
+		KeySeq{"Control+M", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+M", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+M", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+M", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+S", ""}:           KeyFunBufSave,
+		KeySeq{"Shift+Control+S", ""}:     KeyFunBufSaveAs,
+		KeySeq{"Control+M", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+M", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+M", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+M", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+M", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+M", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+M", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+M", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+M", "n"}:          KeyFunBufClone,
+		KeySeq{"Control+M", "Control+N"}:  KeyFunBufClone,
+		KeySeq{"Control+M", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+M", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+M", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+M", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+M", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+/", ""}:           KeyFunCommentOut,
+		KeySeq{"Control+M", "t"}:          KeyFunCommentOut,
+		KeySeq{"Control+M", "Control+T"}:  KeyFunCommentOut,
+		KeySeq{"Control+M", "i"}:          KeyFunIndent,
+		KeySeq{"Control+M", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+M", "j"}:          KeyFunJump,
+		KeySeq{"Control+M", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+M", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+M", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+M", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+M", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+M", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+M", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+M", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+M", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+M", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+M", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+M", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+M", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+M", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+M", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+M", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+M", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+M", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+M", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+M", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+M", ","}:          KeyFunBookmarkPrev,
 	}},
 	{"WindowsStd", "Standard Windows KeySeqMap", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+M", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+M", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+M", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+M", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+O", ""}:          KeyFunFileOpen,
-		KeySeq{"Control+M", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+M", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+M", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+M", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+S", ""}:          KeyFunBufSave,
-		KeySeq{"Shift+Control+S", ""}:    KeyFunBufSaveAs,
-		KeySeq{"Control+M", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+M", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+M", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+M", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+M", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+M", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+M", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+M", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+M", "n"}:         KeyFunBufClone,
-		KeySeq{"Control+M", "Control+N"}: KeyFunBufClone,
-		KeySeq{"Control+M", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+M", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+M", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+M", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+M", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+/", ""}:          KeyFunCommentOut,
-		KeySeq{"Control+M", "t"}:         KeyFunCommentOut,
-		KeySeq{"Control+M", "Control+T"}: KeyFunCommentOut,
-		KeySeq{"Control+M", "i"}:         KeyFunIndent,
-		KeySeq{"Control+M", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+M", "j"}:         KeyFunJump,
-		KeySeq{"Control+M", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+M", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+M", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+M", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+M", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+M", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+M", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+M", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+M", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+M", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+M", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+O", ""}:           KeyFunFileOpen,
+		KeySeq{"Control+M", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+M", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+M", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+M", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+S", ""}:           KeyFunBufSave,
+		KeySeq{"Shift+Control+S", ""}:     KeyFunBufSaveAs,
+		KeySeq{"Control+M", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+M", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+M", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+M", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+M", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+M", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+M", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+M", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+M", "n"}:          KeyFunBufClone,
+		KeySeq{"Control+M", "Control+N"}:  KeyFunBufClone,
+		KeySeq{"Control+M", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+M", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+M", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+M", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+M", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+/", ""}:           KeyFunCommentOut,
+		KeySeq{"Control+M", "t"}:          KeyFunCommentOut,
+		KeySeq{"Control+M", "Control+T"}:  KeyFunCommentOut,
+		KeySeq{"Control+M", "i"}:          KeyFunIndent,
+		KeySeq{"Control+M", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+M", "j"}:          KeyFunJump,
+		KeySeq{"Control+M", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+M", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+M", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+M", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+M", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+M", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+M", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+M", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+M", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+M", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+M", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+M", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+M", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+M", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+M", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+M", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+M", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+M", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+M", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+M", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+M", ","}:          KeyFunBookmarkPrev,
 	}},
 	{"ChromeStd", "Standard chrome-browser and linux-under-chrome bindings", KeySeqMap{
-		KeySeq{"Control+Tab", ""}:        KeyFunNextPanel,
-		KeySeq{"Shift+Control+Tab", ""}:  KeyFunPrevPanel,
-		KeySeq{"Control+M", "o"}:         KeyFunNextPanel,
-		KeySeq{"Control+M", "Control+O"}: KeyFunNextPanel,
-		KeySeq{"Control+M", "p"}:         KeyFunPrevPanel,
-		KeySeq{"Control+M", "Control+P"}: KeyFunPrevPanel,
-		KeySeq{"Control+O", ""}:          KeyFunFileOpen,
-		KeySeq{"Control+M", "f"}:         KeyFunFileOpen,
-		KeySeq{"Control+M", "Control+F"}: KeyFunFileOpen,
-		KeySeq{"Control+M", "b"}:         KeyFunBufSelect,
-		KeySeq{"Control+M", "Control+B"}: KeyFunBufSelect,
-		KeySeq{"Control+S", ""}:          KeyFunBufSave,
-		KeySeq{"Shift+Control+S", ""}:    KeyFunBufSaveAs,
-		KeySeq{"Control+M", "s"}:         KeyFunBufSave,
-		KeySeq{"Control+M", "Control+S"}: KeyFunBufSave,
-		KeySeq{"Control+M", "w"}:         KeyFunBufSaveAs,
-		KeySeq{"Control+M", "Control+W"}: KeyFunBufSaveAs,
-		KeySeq{"Control+M", "k"}:         KeyFunBufClose,
-		KeySeq{"Control+M", "Control+K"}: KeyFunBufClose,
-		KeySeq{"Control+M", "c"}:         KeyFunExecCmd,
-		KeySeq{"Control+M", "Control+C"}: KeyFunExecCmd,
-		KeySeq{"Control+M", "n"}:         KeyFunBufClone,
-		KeySeq{"Control+M", "Control+N"}: KeyFunBufClone,
-		KeySeq{"Control+M", "x"}:         KeyFunRegCopy,
-		KeySeq{"Control+M", "g"}:         KeyFunRegPaste,
-		KeySeq{"Control+M", "Control+X"}: KeyFunRectCut,
-		KeySeq{"Control+M", "Control+Y"}: KeyFunRectPaste,
-		KeySeq{"Control+M", "Alt+∑"}:     KeyFunRectCopy,
-		KeySeq{"Control+/", ""}:          KeyFunCommentOut,
-		KeySeq{"Control+M", "t"}:         KeyFunCommentOut,
-		KeySeq{"Control+M", "Control+T"}: KeyFunCommentOut,
-		KeySeq{"Control+M", "i"}:         KeyFunIndent,
-		KeySeq{"Control+M", "Control+I"}: KeyFunIndent,
-		KeySeq{"Control+M", "j"}:         KeyFunJump,
-		KeySeq{"Control+M", "Control+J"}: KeyFunJump,
-		KeySeq{"Control+M", "v"}:         KeyFunSetSplit,
-		KeySeq{"Control+M", "Control+V"}: KeyFunSetSplit,
-		KeySeq{"Control+M", "m"}:         KeyFunBuildProj,
-		KeySeq{"Control+M", "Control+M"}: KeyFunBuildProj,
-		KeySeq{"Control+M", "r"}:         KeyFunRunProj,
-		KeySeq{"Control+M", "Control+R"}: KeyFunRunProj,
+		KeySeq{"Control+Tab", ""}:         KeyFunNextPanel,
+		KeySeq{"Shift+Control+Tab", ""}:   KeyFunPrevPanel,
+		KeySeq{"Control+M", "o"}:          KeyFunNextPanel,
+		KeySeq{"Control+M", "Control+O"}:  KeyFunNextPanel,
+		KeySeq{"Control+M", "p"}:          KeyFunPrevPanel,
+		KeySeq{"Control+M", "Control+P"}:  KeyFunPrevPanel,
+		KeySeq{"Control+O", ""}:           KeyFunFileOpen,
+		KeySeq{"Control+M", "f"}:          KeyFunFileOpen,
+		KeySeq{"Control+M", "Control+F"}:  KeyFunFileOpen,
+		KeySeq{"Control+M", "b"}:          KeyFunBufSelect,
+		KeySeq{"Control+M", "Control+B"}:  KeyFunBufSelect,
+		KeySeq{"Control+S", ""}:           KeyFunBufSave,
+		KeySeq{"Shift+Control+S", ""}:     KeyFunBufSaveAs,
+		KeySeq{"Control+M", "s"}:          KeyFunBufSave,
+		KeySeq{"Control+M", "Control+S"}:  KeyFunBufSave,
+		KeySeq{"Control+M", "w"}:          KeyFunBufSaveAs,
+		KeySeq{"Control+M", "Control+W"}:  KeyFunBufSaveAs,
+		KeySeq{"Control+M", "k"}:          KeyFunBufClose,
+		KeySeq{"Control+M", "Control+K"}:  KeyFunBufClose,
+		KeySeq{"Control+M", "c"}:          KeyFunExecCmd,
+		KeySeq{"Control+M", "Control+C"}:  KeyFunExecCmd,
+		KeySeq{"Control+M", "n"}:          KeyFunBufClone,
+		KeySeq{"Control+M", "Control+N"}:  KeyFunBufClone,
+		KeySeq{"Control+M", "x"}:          KeyFunRegCopy,
+		KeySeq{"Control+M", "g"}:          KeyFunRegPaste,
+		KeySeq{"Control+M", "Control+X"}:  KeyFunRectCut,
+		KeySeq{"Control+M", "Control+Y"}:  KeyFunRectPaste,
+		KeySeq{"Control+M", "Alt+∑"}:      KeyFunRectCopy,
+		KeySeq{"Control+/", ""}:           KeyFunCommentOut,
+		KeySeq{"Control+M", "t"}:          KeyFunCommentOut,
+		KeySeq{"Control+M", "Control+T"}:  KeyFunCommentOut,
+		KeySeq{"Control+M", "i"}:          KeyFunIndent,
+		KeySeq{"Control+M", "Control+I"}:  KeyFunIndent,
+		KeySeq{"Control+M", "j"}:          KeyFunJump,
+		KeySeq{"Control+M", "Control+J"}:  KeyFunJump,
+		KeySeq{"Control+M", "v"}:          KeyFunSetSplit,
+		KeySeq{"Control+M", "Control+V"}:  KeyFunSetSplit,
+		KeySeq{"Control+M", "m"}:          KeyFunBuildProj,
+		KeySeq{"Control+M", "Control+M"}:  KeyFunBuildProj,
+		KeySeq{"Control+M", "r"}:          KeyFunRunProj,
+		KeySeq{"Control+M", "Control+R"}:  KeyFunRunProj,
+		KeySeq{"Control+M", "UpArrow"}:    KeyFunCursorAbove,
+		KeySeq{"Control+M", "DownArrow"}:  KeyFunCursorBelow,
+		KeySeq{"Control+M", "d"}:          KeyFunCursorNextMatch,
+		KeySeq{"Control+M", "l"}:          KeyFunCursorSplitLines,
+		KeySeq{"Control+M", "z"}:          KeyFunFoldToggle,
+		KeySeq{"Control+M", "["}:          KeyFunFoldAll,
+		KeySeq{"Control+M", "]"}:          KeyFunUnfoldAll,
+		KeySeq{"Control+M", "e"}:          KeyFunSnippet,
+		KeySeq{"Control+M", "LeftArrow"}:  KeyFunNavBack,
+		KeySeq{"Control+M", "RightArrow"}: KeyFunNavForward,
+		KeySeq{"Control+M", "h"}:          KeyFunBookmarkToggle,
+		KeySeq{"Control+M", "a"}:          KeyFunBookmarkNamed,
+		KeySeq{"Control+M", "."}:          KeyFunBookmarkNext,
+		KeySeq{"Control+M", ","}:          KeyFunBookmarkPrev,
 	}},
 }
@@ -35,12 +35,26 @@ func _() {
 	_ = x[KeyFunSetSplit-19]
 	_ = x[KeyFunBuildProj-20]
 	_ = x[KeyFunRunProj-21]
-	_ = x[KeyFunsN-22]
+	_ = x[KeyFunCursorAbove-22]
+	_ = x[KeyFunCursorBelow-23]
+	_ = x[KeyFunCursorNextMatch-24]
+	_ = x[KeyFunCursorSplitLines-25]
+	_ = x[KeyFunFoldToggle-26]
+	_ = x[KeyFunFoldAll-27]
+	_ = x[KeyFunUnfoldAll-28]
+	_ = x[KeyFunSnippet-29]
+	_ = x[KeyFunNavBack-30]
+	_ = x[KeyFunNavForward-31]
+	_ = x[KeyFunBookmarkToggle-32]
+	_ = x[KeyFunBookmarkNamed-33]
+	_ = x[KeyFunBookmarkNext-34]
+	_ = x[KeyFunBookmarkPrev-35]
+	_ = x[KeyFunsN-36]
 }
 
-const _KeyFuns_name = "KeyFunNilKeyFunNeeds2KeyFunNextPanelKeyFunPrevPanelKeyFunFileOpenKeyFunBufSelectKeyFunBufCloneKeyFunBufSaveKeyFunBufSaveAsKeyFunBufCloseKeyFunExecCmdKeyFunRectCopyKeyFunRectCutKeyFunRectPasteKeyFunRegCopyKeyFunRegPasteKeyFunCommentOutKeyFunIndentKeyFunJumpKeyFunSetSplitKeyFunBuildProjKeyFunRunProjKeyFunsN"
+const _KeyFuns_name = "KeyFunNilKeyFunNeeds2KeyFunNextPanelKeyFunPrevPanelKeyFunFileOpenKeyFunBufSelectKeyFunBufCloneKeyFunBufSaveKeyFunBufSaveAsKeyFunBufCloseKeyFunExecCmdKeyFunRectCopyKeyFunRectCutKeyFunRectPasteKeyFunRegCopyKeyFunRegPasteKeyFunCommentOutKeyFunIndentKeyFunJumpKeyFunSetSplitKeyFunBuildProjKeyFunRunProjKeyFunCursorAboveKeyFunCursorBelowKeyFunCursorNextMatchKeyFunCursorSplitLinesKeyFunFoldToggleKeyFunFoldAllKeyFunUnfoldAllKeyFunSnippetKeyFunNavBackKeyFunNavForwardKeyFunBookmarkToggleKeyFunBookmarkNamedKeyFunBookmarkNextKeyFunBookmarkPrevKeyFunsN"
 
-var _KeyFuns_index = [...]uint16{0, 9, 21, 36, 51, 65, 80, 94, 107, 122, 136, 149, 163, 176, 191, 204, 218, 234, 246, 256, 270, 285, 298, 306}
+var _KeyFuns_index = [...]uint16{0, 9, 21, 36, 51, 65, 80, 94, 107, 122, 136, 149, 163, 176, 191, 204, 218, 234, 246, 256, 270, 285, 298, 315, 332, 353, 375, 391, 404, 419, 432, 445, 461, 481, 500, 518, 536, 544}
 
 func (i KeyFuns) String() string {
 	if i < 0 || i >= KeyFuns(len(_KeyFuns_index)-1) {
@@ -0,0 +1,584 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bytes"
+	"sort"
+	"unicode"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mimedata"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+)
+
+// TextCursor is an additional cursor in a TextView, for multi-cursor
+// editing, with its own selection
+type TextCursor struct {
+	Pos lex.Pos        `desc:"position of the cursor"`
+	Sel textbuf.Region `desc:"selected region, if any -- the cursor is at one end of it"`
+}
+
+// HasSelection returns true if the cursor has a selected region
+func (tc *TextCursor) HasSelection() bool {
+	return tc.Sel.Start.IsLess(tc.Sel.End)
+}
+
+// HasCursors returns true if there are additional cursors beyond the main one
+func (tv *TextView) HasCursors() bool {
+	return len(tv.Cursors) > 0
+}
+
+// ClearCursors removes all the additional cursors, leaving just the main one
+func (tv *TextView) ClearCursors() {
+	if !tv.HasCursors() {
+		return
+	}
+	tv.Cursors = nil
+	tv.UpdateSig()
+}
+
+// AllCursors returns the main cursor, with its selection, followed by the
+// additional cursors
+func (tv *TextView) AllCursors() []TextCursor {
+	cs := make([]TextCursor, 0, len(tv.Cursors)+1)
+	main := TextCursor{Pos: tv.CursorPos}
+	if tv.HasSelection() {
+		main.Sel = tv.SelectReg
+	}
+	cs = append(cs, main)
+	return append(cs, tv.Cursors...)
+}
+
+// SetAllCursors sets the main cursor from the first of given cursors, and
+// the additional cursors from the rest, dropping any at the same position
+// as another cursor
+func (tv *TextView) SetAllCursors(cs []TextCursor) {
+	if len(cs) == 0 {
+		return
+	}
+	main := cs[0]
+	var ecs []TextCursor
+	for i := 1; i < len(cs); i++ {
+		tc := cs[i]
+		dup := tc.Pos == main.Pos
+		for _, ec := range ecs {
+			if tc.Pos == ec.Pos {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			ecs = append(ecs, tc)
+		}
+	}
+	tv.Cursors = ecs
+	if main.HasSelection() {
+		tv.SelectReg = main.Sel
+		tv.SelectStart = main.Sel.Start
+	} else {
+		tv.SelectReset()
+	}
+	tv.SetCursorShow(main.Pos)
+	tv.SetCursorCol(tv.CursorPos)
+	tv.UpdateSig()
+}
+
+// AddCursor adds an additional cursor at given position, with given
+// selection (which can be empty)
+func (tv *TextView) AddCursor(pos lex.Pos, sel textbuf.Region) {
+	cs := append(tv.AllCursors(), TextCursor{Pos: pos, Sel: sel})
+	tv.SetAllCursors(cs)
+}
+
+// AddCursorAbove adds a cursor on the line above the topmost cursor, at the
+// column of the main cursor
+func (tv *TextView) AddCursorAbove() {
+	tv.addCursorLine(-1)
+}
+
+// AddCursorBelow adds a cursor on the line below the bottommost cursor, at the
+// column of the main cursor
+func (tv *TextView) AddCursorBelow() {
+	tv.addCursorLine(1)
+}
+
+// addCursorLine adds a cursor on the line beyond the cursors in given direction
+func (tv *TextView) addCursorLine(dir int) {
+	if tv.Buf == nil {
+		return
+	}
+	ln := tv.CursorPos.Ln
+	for _, tc := range tv.Cursors {
+		if (dir < 0 && tc.Pos.Ln < ln) || (dir > 0 && tc.Pos.Ln > ln) {
+			ln = tc.Pos.Ln
+		}
+	}
+	ln += dir
+	if !tv.Buf.IsValidLine(ln) {
+		return
+	}
+	ch := tv.CursorPos.Ch
+	if ll := tv.Buf.LineLen(ln); ch > ll {
+		ch = ll
+	}
+	tv.AddCursor(lex.Pos{Ln: ln, Ch: ch}, textbuf.Region{})
+}
+
+// AddNextMatch adds a cursor selecting the next occurrence of the text
+// selected by the main cursor, after the last cursor added.  If there is no
+// selection, the word at the cursor is selected first.  Only selections
+// within one line are matched.
+func (tv *TextView) AddNextMatch() {
+	if tv.Buf == nil {
+		return
+	}
+	if !tv.HasSelection() {
+		if tv.SelectWord() {
+			tv.SetCursorShow(tv.SelectReg.End)
+			tv.UpdateSig()
+		}
+		return
+	}
+	sel := tv.SelectReg
+	if sel.Start.Ln != sel.End.Ln {
+		return
+	}
+	find := tv.Buf.Line(sel.Start.Ln)[sel.Start.Ch:sel.End.Ch]
+	from := sel.End
+	if n := len(tv.Cursors); n > 0 {
+		from = tv.Cursors[n-1].Pos
+	}
+	cs := tv.AllCursors()
+	lines := make([][]rune, tv.Buf.NumLines())
+	for ln := range lines {
+		lines[ln] = tv.Buf.Line(ln)
+	}
+	for i := 0; i < len(cs); i++ { // each match found can only be a cursor once
+		reg, ok := FindNextRunes(lines, find, from)
+		if !ok {
+			return
+		}
+		dup := false
+		for _, tc := range cs {
+			if tc.Sel.Start == reg.Start {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			tv.AddCursor(reg.End, reg)
+			return
+		}
+		from = reg.End
+	}
+}
+
+// FindNextRunes returns the region of the next occurrence of given text in
+// given lines, starting at given position and wrapping around to the start
+// -- the text must not contain any newlines.
+func FindNextRunes(lines [][]rune, find []rune, from lex.Pos) (textbuf.Region, bool) {
+	nf := len(find)
+	nl := len(lines)
+	if nf == 0 || nl == 0 {
+		return textbuf.Region{}, false
+	}
+	for i := 0; i <= nl; i++ {
+		ln := (from.Ln + i) % nl
+		st := 0
+		if i == 0 {
+			st = from.Ch
+		}
+		lr := lines[ln]
+		for ch := st; ch+nf <= len(lr); ch++ {
+			if i == nl && ch >= from.Ch { // back to the start
+				break
+			}
+			if runesEqual(lr[ch:ch+nf], find) {
+				return textbuf.NewRegion(ln, ch, ln, ch+nf), true
+			}
+		}
+	}
+	return textbuf.Region{}, false
+}
+
+// runesEqual returns true if given rune slices are equal
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitSelectionLines splits the selection into one cursor per line, each
+// selecting the part of its line within the selection, with the cursor at
+// its end
+func (tv *TextView) SplitSelectionLines() {
+	if tv.Buf == nil || !tv.HasSelection() {
+		return
+	}
+	cs := SplitRegionLines(tv.SelectReg, tv.Buf.LineLen)
+	if len(cs) > 0 {
+		tv.SetAllCursors(cs)
+	}
+}
+
+// SplitRegionLines returns a cursor for each line of given region, selecting
+// the part of the line within the region, using given function for the
+// length of each line -- the last line is skipped if the region ends at its
+// start.
+func SplitRegionLines(reg textbuf.Region, lineLen func(ln int) int) []TextCursor {
+	var cs []TextCursor
+	for ln := reg.Start.Ln; ln <= reg.End.Ln; ln++ {
+		if ln == reg.End.Ln && ln > reg.Start.Ln && reg.End.Ch == 0 {
+			break
+		}
+		sel := textbuf.Region{Start: lex.Pos{Ln: ln}, End: lex.Pos{Ln: ln, Ch: lineLen(ln)}}
+		if ln == reg.Start.Ln {
+			sel.Start.Ch = reg.Start.Ch
+		}
+		if ln == reg.End.Ln {
+			sel.End.Ch = reg.End.Ch
+		}
+		cs = append(cs, TextCursor{Pos: sel.End, Sel: sel})
+	}
+	return cs
+}
+
+// AdjustCursorPos returns given position adjusted for given edit: positions
+// after an insertion move along with the text after it, and positions
+// within a deleted region move to its start.
+func AdjustCursorPos(tbe *textbuf.Edit, pos lex.Pos) lex.Pos {
+	if tbe == nil {
+		return pos
+	}
+	st := tbe.Reg.Start
+	ed := tbe.Reg.End
+	if !st.IsLess(pos) {
+		return pos
+	}
+	if tbe.Delete {
+		if pos.IsLess(ed) {
+			return st
+		}
+		if pos.Ln == ed.Ln {
+			pos.Ch = st.Ch + pos.Ch - ed.Ch
+		}
+		pos.Ln -= ed.Ln - st.Ln
+		return pos
+	}
+	if pos.Ln == st.Ln {
+		pos.Ch = ed.Ch + pos.Ch - st.Ch
+	}
+	pos.Ln += ed.Ln - st.Ln
+	return pos
+}
+
+// adjustCursors adjusts all the cursors other than the one at given index
+// for given edit
+func adjustCursors(cs []TextCursor, idx int, tbe *textbuf.Edit) {
+	if tbe == nil {
+		return
+	}
+	for j := range cs {
+		if j == idx {
+			continue
+		}
+		tc := &cs[j]
+		tc.Pos = AdjustCursorPos(tbe, tc.Pos)
+		tc.Sel.Start = AdjustCursorPos(tbe, tc.Sel.Start)
+		tc.Sel.End = AdjustCursorPos(tbe, tc.Sel.End)
+	}
+}
+
+// EditAtCursors calls given function to edit the text at each of the
+// cursors in turn, from the last in the buffer to the first, keeping all
+// the cursors in place as the text changes.  The function is passed the
+// index of the cursor in buffer order, and returns the edits it made, in
+// order, and the new position of the cursor, whose selection is reset.
+// All of the edits are made as one undo step.
+func (tv *TextView) EditAtCursors(fun func(idx int, tc TextCursor) ([]*textbuf.Edit, lex.Pos)) {
+	if tv.Buf == nil {
+		return
+	}
+	cs := tv.AllCursors()
+	ord := make([]int, len(cs))
+	for i := range ord {
+		ord[i] = i
+	}
+	sort.Slice(ord, func(i, j int) bool {
+		return cs[ord[i]].Pos.IsLess(cs[ord[j]].Pos)
+	})
+	wupdt := tv.TopUpdateStart()
+	defer tv.TopUpdateEnd(wupdt)
+	bufUpdt, winUpdt, autoSave := tv.Buf.BatchUpdateStart()
+	tv.Buf.Undos.NewGroup()
+	for oi := len(ord) - 1; oi >= 0; oi-- {
+		ci := ord[oi]
+		tbes, pos := fun(oi, cs[ci])
+		for _, tbe := range tbes {
+			adjustCursors(cs, ci, tbe)
+		}
+		cs[ci] = TextCursor{Pos: pos}
+	}
+	tv.Buf.Undos.NewGroup()
+	tv.Buf.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	tv.SetAllCursors(cs)
+}
+
+// InsertAtCursors inserts given text at each of the cursors, replacing their
+// selections
+func (tv *TextView) InsertAtCursors(txt []byte) {
+	tv.InsertLinesAtCursors([][]byte{txt})
+}
+
+// InsertLinesAtCursors inserts the given texts at the cursors, replacing
+// their selections: if there is one text per cursor, each cursor gets its
+// own text, in buffer order, else all the texts are inserted at each cursor,
+// joined by newlines.
+func (tv *TextView) InsertLinesAtCursors(txts [][]byte) {
+	var all []byte
+	if len(txts) != len(tv.Cursors)+1 {
+		all = bytes.Join(txts, []byte("\n"))
+	}
+	tv.EditAtCursors(func(idx int, tc TextCursor) ([]*textbuf.Edit, lex.Pos) {
+		var tbes []*textbuf.Edit
+		pos := tc.Pos
+		if tc.HasSelection() {
+			tbe := tv.Buf.DeleteText(tc.Sel.Start, tc.Sel.End, giv.EditSignal)
+			tbes = append(tbes, tbe)
+			pos = tc.Sel.Start
+		}
+		txt := all
+		if all == nil {
+			txt = txts[idx]
+		}
+		if len(txt) == 0 {
+			return tbes, pos
+		}
+		if tbe := tv.Buf.InsertText(pos, txt, giv.EditSignal); tbe != nil {
+			tbes = append(tbes, tbe)
+			pos = tbe.Reg.End
+		}
+		return tbes, pos
+	})
+}
+
+// DeleteAtCursors deletes the selections of the cursors, and for those
+// without a selection, deletes given number of characters before (steps < 0,
+// i.e., backspace) or after (steps > 0) the cursor, within its line or
+// joining it with the previous or next one
+func (tv *TextView) DeleteAtCursors(steps int) {
+	tv.EditAtCursors(func(idx int, tc TextCursor) ([]*textbuf.Edit, lex.Pos) {
+		st, ed := tc.Pos, tc.Pos
+		if tc.HasSelection() {
+			st, ed = tc.Sel.Start, tc.Sel.End
+		} else if steps < 0 {
+			st = tv.cursorStep(st, steps)
+		} else {
+			ed = tv.cursorStep(ed, steps)
+		}
+		if st == ed {
+			return nil, st
+		}
+		tbe := tv.Buf.DeleteText(st, ed, giv.EditSignal)
+		return []*textbuf.Edit{tbe}, st
+	})
+}
+
+// cursorStep returns given position moved by given number of characters,
+// backward if negative, moving across line ends
+func (tv *TextView) cursorStep(pos lex.Pos, steps int) lex.Pos {
+	for ; steps < 0; steps++ {
+		if pos.Ch > 0 {
+			pos.Ch--
+		} else if pos.Ln > 0 {
+			pos.Ln--
+			pos.Ch = tv.Buf.LineLen(pos.Ln)
+		}
+	}
+	nl := tv.Buf.NumLines()
+	for ; steps > 0; steps-- {
+		if pos.Ch < tv.Buf.LineLen(pos.Ln) {
+			pos.Ch++
+		} else if pos.Ln < nl-1 {
+			pos.Ln++
+			pos.Ch = 0
+		}
+	}
+	return pos
+}
+
+// MoveCursors moves all of the cursors by given number of characters,
+// backward if negative, resetting their selections
+func (tv *TextView) MoveCursors(steps int) {
+	cs := tv.AllCursors()
+	for i := range cs {
+		cs[i] = TextCursor{Pos: tv.cursorStep(cs[i].Pos, steps)}
+	}
+	tv.SetAllCursors(cs)
+}
+
+// MoveCursorsLine moves all of the cursors to the start (end = false) or end
+// of their lines, resetting their selections
+func (tv *TextView) MoveCursorsLine(end bool) {
+	cs := tv.AllCursors()
+	for i := range cs {
+		pos := lex.Pos{Ln: cs[i].Pos.Ln}
+		if end {
+			pos.Ch = tv.Buf.LineLen(pos.Ln)
+		}
+		cs[i] = TextCursor{Pos: pos}
+	}
+	tv.SetAllCursors(cs)
+}
+
+// CursorsText returns the text selected by all of the cursors, in buffer
+// order, joined by newlines
+func (tv *TextView) CursorsText() []byte {
+	cs := tv.AllCursors()
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].Pos.IsLess(cs[j].Pos)
+	})
+	var txts [][]byte
+	for _, tc := range cs {
+		if !tc.HasSelection() {
+			continue
+		}
+		if tbe := tv.Buf.Region(tc.Sel.Start, tc.Sel.End); tbe != nil {
+			txts = append(txts, tbe.ToBytes())
+		}
+	}
+	return bytes.Join(txts, []byte("\n"))
+}
+
+// CopyCursors copies the text selected by all of the cursors to the clipboard,
+// one selection per line
+func (tv *TextView) CopyCursors() {
+	txt := tv.CursorsText()
+	if len(txt) == 0 {
+		return
+	}
+	giv.TextViewClipHistAdd(txt)
+	oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).Write(mimedata.NewTextBytes(txt))
+}
+
+// PasteCursors pastes the clipboard at all of the cursors: if it has one line
+// per cursor, each cursor gets its own line
+func (tv *TextView) PasteCursors() {
+	data := oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).Read([]string{filecat.TextPlain})
+	if data == nil {
+		return
+	}
+	txt := data.TypeData(filecat.TextPlain)
+	tv.InsertLinesAtCursors(bytes.Split(txt, []byte("\n")))
+}
+
+// CursorsKeyInput handles key input when there are additional cursors,
+// applying typing, deleting and pasting at all of them.  Keys for other
+// functions remove the additional cursors, and are not processed here.
+func (tv *TextView) CursorsKeyInput(kt *key.ChordEvent) {
+	if !tv.HasCursors() || tv.ISearch.On || tv.QReplace.On {
+		return
+	}
+	win := tv.ParentWindow()
+	if win != nil && gi.PopupIsCompleter(win.CurPopup()) {
+		return
+	}
+	kf := gi.KeyFun(kt.Chord())
+	switch kf {
+	case gi.KeyFunNil:
+		if unicode.IsPrint(kt.Rune) && !kt.HasAnyModifier(key.Control, key.Meta) {
+			kt.SetProcessed()
+			tv.InsertAtCursors([]byte(string(kt.Rune)))
+		}
+		return
+	case gi.KeyFunEnter:
+		kt.SetProcessed()
+		tv.InsertAtCursors([]byte("\n"))
+		return
+	case gi.KeyFunFocusNext: // tab
+		kt.SetProcessed()
+		tv.InsertAtCursors([]byte("\t"))
+		return
+	case gi.KeyFunBackspace:
+		kt.SetProcessed()
+		tv.DeleteAtCursors(-1)
+		return
+	case gi.KeyFunDelete:
+		kt.SetProcessed()
+		tv.DeleteAtCursors(1)
+		return
+	case gi.KeyFunCopy:
+		kt.SetProcessed()
+		tv.CopyCursors()
+		return
+	case gi.KeyFunCut:
+		kt.SetProcessed()
+		tv.CopyCursors()
+		tv.DeleteAtCursors(0)
+		return
+	case gi.KeyFunPaste:
+		kt.SetProcessed()
+		tv.PasteCursors()
+		return
+	case gi.KeyFunMoveLeft:
+		kt.SetProcessed()
+		tv.MoveCursors(-1)
+		return
+	case gi.KeyFunMoveRight:
+		kt.SetProcessed()
+		tv.MoveCursors(1)
+		return
+	case gi.KeyFunHome:
+		kt.SetProcessed()
+		tv.MoveCursorsLine(false)
+		return
+	case gi.KeyFunEnd:
+		kt.SetProcessed()
+		tv.MoveCursorsLine(true)
+		return
+	case gi.KeyFunAbort:
+		kt.SetProcessed()
+		tv.ClearCursors()
+		return
+	}
+	tv.ClearCursors()
+}
+
+// RenderCursors renders the additional cursors, and their selections
+func (tv *TextView) RenderCursors() {
+	if !tv.HasCursors() || !tv.IsVisible() {
+		return
+	}
+	rs := tv.Render()
+	rs.PushBounds(tv.VpBBox)
+	rs.Lock()
+	pc := &rs.Paint
+	sty := &tv.StateStyles[giv.TextViewSel]
+	selclr := sty.Font.BgColor
+	selclr.Color.A = 0x60
+	selclr.Color.SetAlphaPreMult()
+	for _, tc := range tv.Cursors {
+		if tc.HasSelection() {
+			tv.RenderRegionBoxSty(tc.Sel, sty, &selclr)
+		}
+		pos := tv.CharStartPos(tc.Pos)
+		pc.FillBoxColor(rs, pos, mat32.Vec2{X: 1, Y: tv.FontHeight}, tv.Sty.Font.Color)
+	}
+	rs.Unlock()
+	rs.PopBounds()
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"testing"
+
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/pi/lex"
+)
+
+func TestFindNextRunes(t *testing.T) {
+	lines := [][]rune{[]rune("foo bar foo"), []rune(""), []rune("x foo")}
+	find := []rune("foo")
+	tests := []struct {
+		from lex.Pos
+		reg  textbuf.Region
+	}{
+		{lex.Pos{0, 0}, textbuf.NewRegion(0, 0, 0, 3)},
+		{lex.Pos{0, 3}, textbuf.NewRegion(0, 8, 0, 11)},
+		{lex.Pos{0, 11}, textbuf.NewRegion(2, 2, 2, 5)},
+		{lex.Pos{2, 5}, textbuf.NewRegion(0, 0, 0, 3)}, // wraps around
+	}
+	for _, ts := range tests {
+		reg, ok := FindNextRunes(lines, find, ts.from)
+		if !ok || reg.Start != ts.reg.Start || reg.End != ts.reg.End {
+			t.Errorf("from: %v should have found: %v  was: %v %v", ts.from, ts.reg, reg, ok)
+		}
+	}
+	if _, ok := FindNextRunes(lines, []rune("baz"), lex.Pos{}); ok {
+		t.Errorf("should not have found baz")
+	}
+}
+
+func TestSplitRegionLines(t *testing.T) {
+	lens := []int{10, 4, 8, 6}
+	lineLen := func(ln int) int { return lens[ln] }
+	cs := SplitRegionLines(textbuf.NewRegion(0, 3, 3, 0), lineLen)
+	if len(cs) != 3 {
+		t.Fatalf("should have 3 cursors, has: %d", len(cs))
+	}
+	if cs[0].Sel.Start != (lex.Pos{0, 3}) || cs[0].Pos != (lex.Pos{0, 10}) {
+		t.Errorf("first cursor should select from 0:3 to 0:10, was: %v", cs[0])
+	}
+	if cs[2].Sel.Start != (lex.Pos{2, 0}) || cs[2].Pos != (lex.Pos{2, 8}) {
+		t.Errorf("last cursor should select line 2, was: %v", cs[2])
+	}
+	cs = SplitRegionLines(textbuf.NewRegion(1, 1, 2, 5), lineLen)
+	if len(cs) != 2 || cs[1].Sel.End != (lex.Pos{2, 5}) {
+		t.Errorf("last cursor should end at 2:5, was: %v", cs)
+	}
+}
+
+func TestAdjustCursorPos(t *testing.T) {
+	ins := &textbuf.Edit{Reg: textbuf.NewRegion(1, 4, 2, 2)} // insert of "...\n.." at 1:4
+	del := &textbuf.Edit{Reg: textbuf.NewRegion(1, 4, 2, 2), Delete: true}
+	tests := []struct {
+		tbe *textbuf.Edit
+		pos lex.Pos
+		res lex.Pos
+	}{
+		{ins, lex.Pos{1, 2}, lex.Pos{1, 2}},
+		{ins, lex.Pos{1, 4}, lex.Pos{1, 4}},
+		{ins, lex.Pos{1, 6}, lex.Pos{2, 4}},
+		{ins, lex.Pos{3, 1}, lex.Pos{4, 1}},
+		{del, lex.Pos{1, 8}, lex.Pos{1, 4}},
+		{del, lex.Pos{2, 5}, lex.Pos{1, 7}},
+		{del, lex.Pos{4, 1}, lex.Pos{3, 1}},
+	}
+	for _, ts := range tests {
+		if res := AdjustCursorPos(ts.tbe, ts.pos); res != ts.res {
+			t.Errorf("delete: %v pos: %v should be: %v  was: %v", ts.tbe.Delete, ts.pos, ts.res, res)
+		}
+	}
+}
@@ -135,6 +135,7 @@ func (pf *Preferences) Open() error {
 	}
 	AvailSplits.OpenPrefs()
 	AvailRegisters.OpenPrefs()
+	AvailSnippets.OpenPrefs()
 	pf.Apply()
 	pf.Changed = false
 	return err
@@ -164,6 +165,7 @@ func (pf *Preferences) Save() error {
 	}
 	AvailSplits.SavePrefs()
 	AvailRegisters.SavePrefs()
+	AvailSnippets.SavePrefs()
 	pf.Changed = false
 	return err
 }
@@ -213,6 +215,11 @@ func (pf *Preferences) EditRegisters() {
 	RegistersView(&AvailRegisters)
 }
 
+// EditSnippets opens the SnippetsView editor of snippets for each language
+func (pf *Preferences) EditSnippets() {
+	SnippetsView(&AvailSnippets)
+}
+
 // PreferencesProps define the ToolBar and MenuBar for StructView, e.g., giv.PrefsView
 var PreferencesProps = ki.Props{
 	"MainMenu": ki.PropSlice{
@@ -273,6 +280,10 @@ var PreferencesProps = ki.Props{
 			"icon": "file-binary",
 			"desc": "opens the RegistersView editor of saved named text registers.  Current values are saved and loaded with preferences automatically.",
 		}},
+		{"EditSnippets", ki.Props{
+			"icon": "file-text",
+			"desc": "opens the SnippetsView editor of snippets for each language, which are offered as completions and inserted with their fields to fill in.  Current values are saved and loaded with preferences automatically.",
+		}},
 	},
 }
 
@@ -281,25 +292,34 @@ var PreferencesProps = ki.Props{
 
 // ProjPrefs are the preferences for saving for a project -- this IS the project file
 type ProjPrefs struct {
-	Files        FilePrefs         `desc:"file view preferences"`
-	Editor       gi.EditorPrefs    `view:"inline" desc:"editor preferences"`
-	SplitName    SplitName         `desc:"current named-split config in use for configuring the splitters"`
-	MainLang     filecat.Supported `desc:"the language associated with the most frequently-encountered file extension in the file tree -- can be manually set here as well"`
-	VersCtrl     giv.VersCtrlName  `desc:"the type of version control system used in this project (git, svn, etc) -- filters commands available"`
-	ProjFilename gi.FileName       `ext:".gide" desc:"current project filename for saving / loading specific Gide configuration information in a .gide file (optional)"`
-	ProjRoot     gi.FileName       `desc:"root directory for the project -- all projects must be organized within a top-level root directory, with all the files therein constituting the scope of the project -- by default it is the path for ProjFilename"`
-	BuildCmds    CmdNames          `desc:"command(s) to run for main Build button"`
-	BuildDir     gi.FileName       `desc:"build directory for main Build button -- set this to the directory where you want to build the main target for this project -- avail as {BuildDir} in commands"`
-	BuildTarg    gi.FileName       `desc:"build target for main Build button, if relevant for your  BuildCmds"`
-	RunExec      gi.FileName       `desc:"executable to run for this project via main Run button -- called by standard Run Proj command"`
-	RunCmds      CmdNames          `desc:"command(s) to run for main Run button (typically Run Proj)"`
-	Debug        gidebug.Params    `desc:"custom debugger parameters for this project"`
-	Find         FindParams        `view:"-" desc:"saved find params"`
-	Symbols      SymbolsParams     `view:"-" desc:"saved structure params"`
-	Dirs         giv.DirFlagMap    `view:"-" desc:"directory properties"`
-	Register     RegisterName      `view:"-" desc:"last register used"`
-	Splits       []float32         `view:"-" desc:"current splitter splits"`
-	Changed      bool              `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
+	Files        FilePrefs             `desc:"file view preferences"`
+	Editor       gi.EditorPrefs        `view:"inline" desc:"editor preferences"`
+	SplitName    SplitName             `desc:"current named-split config in use for configuring the splitters"`
+	MainLang     filecat.Supported     `desc:"the language associated with the most frequently-encountered file extension in the file tree -- can be manually set here as well"`
+	VersCtrl     giv.VersCtrlName      `desc:"the type of version control system used in this project (git, svn, etc) -- filters commands available"`
+	ProjFilename gi.FileName           `ext:".gide" desc:"current project filename for saving / loading specific Gide configuration information in a .gide file (optional)"`
+	ProjRoot     gi.FileName           `desc:"root directory for the project -- all projects must be organized within a top-level root directory, with all the files therein constituting the scope of the project -- by default it is the path for ProjFilename"`
+	BuildCmds    CmdNames              `desc:"command(s) to run for main Build button"`
+	BuildDir     gi.FileName           `desc:"build directory for main Build button -- set this to the directory where you want to build the main target for this project -- avail as {BuildDir} in commands"`
+	BuildTarg    gi.FileName           `desc:"build target for main Build button, if relevant for your  BuildCmds"`
+	RunExec      gi.FileName           `desc:"executable to run for this project via main Run button -- called by standard Run Proj command"`
+	RunCmds      CmdNames              `desc:"command(s) to run for main Run button (typically Run Proj)"`
+	Debug        gidebug.Params        `desc:"custom debugger parameters for this project"`
+	Launches     gidebug.LaunchConfigs `desc:"named debug launch configurations (program, mode, args, env, working dir, build tags, test filter), selectable from the debug toolbar"`
+	CurLaunch    string                `desc:"name of the launch configuration used by the Debug command -- if empty, Debug runs the RunExec executable"`
+	Breaks       []*gidebug.Break      `view:"-" desc:"saved debugger breakpoints (line, instruction, function and watch), restored when the debugger is started"`
+	DebugHist    []string              `view:"-" desc:"history of input to the debugger console"`
+	Find         FindParams            `view:"-" desc:"saved find params"`
+	Searches     SavedSearches         `view:"-" desc:"named saved searches, re-run from the Searches menu in the Find tab"`
+	Symbols      SymbolsParams         `view:"-" desc:"saved structure params"`
+	Dirs         giv.DirFlagMap        `view:"-" desc:"directory properties"`
+	Register     RegisterName          `view:"-" desc:"last register used"`
+	Splits       []float32             `view:"-" desc:"current splitter splits"`
+	Snippets     Snippets              `desc:"snippets for this project, by language name, in addition to (and overriding those with the same prefix in) the snippets in the preferences"`
+	Folds        map[string][]Fold     `view:"-" desc:"folded regions of lines in each file, by file name relative to the project root"`
+	Bookmarks    Bookmarks             `view:"-" desc:"named and numbered bookmarks of lines in files -- see the Bookmarks tab"`
+	NavHist      NavHistory            `view:"-" desc:"history of positions jumped from, for navigating back and forward"`
+	Changed      bool                  `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
 }
 
 var KiT_ProjPrefs = kit.Types.AddType(&ProjPrefs{}, ProjPrefsProps)
@@ -0,0 +1,399 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/lex"
+)
+
+// ReplaceItem is one pending replacement in the ReplaceView
+type ReplaceItem struct {
+	Accept bool           `width:"6" desc:"apply this replacement"`
+	File   string         `inactive:"+" desc:"file name (relative to project root)"`
+	Line   int            `inactive:"+" desc:"line within file"`
+	Orig   string         `inactive:"+" desc:"text that will be replaced"`
+	Repl   string         `inactive:"+" desc:"replacement text"`
+	FPath  string         `inactive:"+" view:"-" tableview:"-" desc:"full path to file"`
+	Reg    textbuf.Region `view:"-" tableview:"-" desc:"region of the match in the file buffer, as of the preview"`
+}
+
+// FindReplaceText returns the original text at given region of the buffer,
+// and the text it is to be replaced with according to given params -- re
+// must be the compiled regexp if using regexp mode
+func FindReplaceText(tb *giv.TextBuf, reg textbuf.Region, fp *FindParams, re *regexp.Regexp) (orig, repl string) {
+	orig = string(tb.Region(reg.Start, reg.End).ToBytes())
+	repl = fp.Replace
+	if fp.Structural {
+		repl = StructReplaceText(orig, fp.Find, repl)
+		return
+	}
+	if fp.Regexp && re != nil {
+		repl = string(RegexpReplaceText(tb, reg, re, repl))
+	}
+	switch {
+	case fp.PreserveCase:
+		repl = PreserveCase(orig, repl)
+	case fp.IgnoreCase && !fp.Regexp:
+		repl = lex.MatchCase(orig, repl)
+	}
+	return
+}
+
+// ReplaceView shows a preview of the replacements that would be made for
+// the current find results, as a diff per file, and allows each replacement
+// to be accepted or rejected before applying the accepted ones.
+type ReplaceView struct {
+	gi.Layout
+	Gide      Gide                    `json:"-" xml:"-" desc:"parent gide project"`
+	Items     []*ReplaceItem          `desc:"pending replacements, in file and then position order"`
+	SaveFiles bool                    `desc:"save files after applying the replacements"`
+	Bufs      map[string]*giv.TextBuf `json:"-" xml:"-" desc:"buffers for the files, by full path"`
+	Files     []string                `json:"-" xml:"-" desc:"full paths of files, in order"`
+}
+
+var KiT_ReplaceView = kit.Types.AddType(&ReplaceView{}, ReplaceViewProps)
+
+// SetResults sets the pending replacements from given find results, using
+// given find params and compiled regexp (if using regexp), for a search done
+// at given time.  All replacements start out accepted.
+func (rv *ReplaceView) SetResults(res []FileSearchResults, fp *FindParams, re *regexp.Regexp, tm time.Time) {
+	rv.Items = nil
+	rv.Files = nil
+	rv.Bufs = make(map[string]*giv.TextBuf)
+	root := string(rv.Gide.FileTree().FPath)
+	for _, fs := range res {
+		tb := rv.Gide.TextBufForFile(fs.FPath, true)
+		if tb == nil {
+			continue
+		}
+		rv.Bufs[fs.FPath] = tb
+		rv.Files = append(rv.Files, fs.FPath)
+		fn := fs.RelPath(root)
+		for _, mt := range fs.Matches {
+			reg := mt.Reg
+			reg.Time.SetTime(tm)
+			reg = tb.AdjustReg(reg)
+			if reg.IsNil() {
+				continue
+			}
+			orig, repl := FindReplaceText(tb, reg, fp, re)
+			rv.Items = append(rv.Items, &ReplaceItem{Accept: true, File: fn, Line: reg.Start.Ln + 1, Orig: orig, Repl: repl, FPath: fs.FPath, Reg: reg})
+		}
+	}
+}
+
+// FileItems returns the items for given file
+func (rv *ReplaceView) FileItems(fpath string) []*ReplaceItem {
+	var its []*ReplaceItem
+	for _, it := range rv.Items {
+		if it.FPath == fpath {
+			its = append(its, it)
+		}
+	}
+	return its
+}
+
+// NAccepted returns the number of accepted items, and files with accepted items
+func (rv *ReplaceView) NAccepted() (items, files int) {
+	for _, fp := range rv.Files {
+		got := false
+		for _, it := range rv.FileItems(fp) {
+			if it.Accept {
+				items++
+				got = true
+			}
+		}
+		if got {
+			files++
+		}
+	}
+	return
+}
+
+// SetAccept sets the accept status of all items
+func (rv *ReplaceView) SetAccept(accept bool) {
+	for _, it := range rv.Items {
+		it.Accept = accept
+	}
+	rv.UpdateView()
+}
+
+// Diff returns a unified-diff style text showing the effect of the accepted
+// replacements in each file
+func (rv *ReplaceView) Diff() string {
+	var b strings.Builder
+	for _, fp := range rv.Files {
+		tb := rv.Bufs[fp]
+		var its []*ReplaceItem
+		for _, it := range rv.FileItems(fp) {
+			if it.Accept {
+				its = append(its, it)
+			}
+		}
+		if len(its) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "--- a/%v\n+++ b/%v\n", its[0].File, its[0].File)
+		for len(its) > 0 {
+			stln := its[0].Reg.Start.Ln
+			edln := its[0].Reg.End.Ln
+			n := 1
+			for ; n < len(its) && its[n].Reg.Start.Ln <= edln; n++ {
+				if its[n].Reg.End.Ln > edln {
+					edln = its[n].Reg.End.Ln
+				}
+			}
+			ReplaceDiffHunk(&b, tb, stln, edln, its[:n])
+			its = its[n:]
+		}
+	}
+	return b.String()
+}
+
+// ReplaceDiffHunk writes one diff hunk for lines stln through edln of the
+// buffer, with the given replacements (which must all be within those lines)
+func ReplaceDiffHunk(b *strings.Builder, tb *giv.TextBuf, stln, edln int, its []*ReplaceItem) {
+	var olns []string
+	for ln := stln; ln <= edln; ln++ {
+		olns = append(olns, string(tb.Line(ln)))
+	}
+	nlns := make([][]rune, len(olns))
+	for i, l := range olns {
+		nlns[i] = []rune(l)
+	}
+	// apply in reverse order, joining any lines spanned by a replacement
+	for i := len(its) - 1; i >= 0; i-- {
+		reg := its[i].Reg
+		sl := reg.Start.Ln - stln
+		el := reg.End.Ln - stln
+		if sl < 0 || el >= len(nlns) || reg.Start.Ch > len(nlns[sl]) || reg.End.Ch > len(nlns[el]) {
+			continue
+		}
+		nl := append([]rune{}, nlns[sl][:reg.Start.Ch]...)
+		nl = append(nl, []rune(its[i].Repl)...)
+		nl = append(nl, nlns[el][reg.End.Ch:]...)
+		rest := append([][]rune{nl}, nlns[el+1:]...)
+		nlns = append(nlns[:sl], rest...)
+	}
+	var nstr []string
+	for _, nl := range nlns {
+		nstr = append(nstr, strings.Split(string(nl), "\n")...)
+	}
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", stln+1, len(olns), stln+1, len(nstr))
+	for _, l := range olns {
+		fmt.Fprintf(b, "-%s\n", l)
+	}
+	for _, l := range nstr {
+		fmt.Fprintf(b, "+%s\n", l)
+	}
+}
+
+// Apply applies all the accepted replacements, as one undo group per
+// buffer, saving the files if SaveFiles is set.  Applied items are removed.
+func (rv *ReplaceView) Apply() {
+	wupdt := rv.TopUpdateStart()
+	defer rv.TopUpdateEnd(wupdt)
+	nitm, nfl := rv.NAccepted()
+	var left []*ReplaceItem
+	for _, fp := range rv.Files {
+		tb := rv.Bufs[fp]
+		var its []*ReplaceItem
+		for _, it := range rv.FileItems(fp) {
+			if it.Accept {
+				its = append(its, it)
+			} else {
+				left = append(left, it)
+			}
+		}
+		if len(its) == 0 {
+			continue
+		}
+		sort.Slice(its, func(i, j int) bool { // last first, so earlier regions remain valid
+			return its[j].Reg.Start.IsLess(its[i].Reg.Start)
+		})
+		ReplaceApplyBuf(tb, its)
+		if rv.SaveFiles && tb.IsChanged() {
+			if err := tb.Save(); err != nil {
+				gi.PromptDialog(nil, gi.DlgOpts{Title: "Could not Save File", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+			}
+		}
+	}
+	rv.Items = left
+	rv.UpdateView()
+	rv.Gide.SetStatus(fmt.Sprintf("Replaced %d matches in %d files", nitm, nfl))
+}
+
+// ReplaceApplyBuf applies given replacements to the buffer, which must be in
+// reverse order of position, as one undo group
+func ReplaceApplyBuf(tb *giv.TextBuf, its []*ReplaceItem) {
+	tb.Undos.NewGroup()
+	st := tb.Undos.Pos
+	for _, it := range its {
+		reg := tb.AdjustReg(it.Reg)
+		if reg.IsNil() {
+			continue
+		}
+		tb.ReplaceText(reg.Start, reg.End, reg.Start, it.Repl, giv.EditSignal, false)
+	}
+	// edits spaced out in time are otherwise split into separate groups
+	tb.Undos.Mu.Lock()
+	if st < len(tb.Undos.Stack) {
+		gp := tb.Undos.Stack[st].Group
+		for _, e := range tb.Undos.Stack[st:] {
+			e.Group = gp
+		}
+		tb.Undos.Group = gp
+	}
+	tb.Undos.Mu.Unlock()
+	tb.Undos.NewGroup()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//    GUI config
+
+// Config configures the view, showing the replacements for given find results
+func (rv *ReplaceView) Config(ge Gide, res []FileSearchResults, fp *FindParams, re *regexp.Regexp, tm time.Time) {
+	rv.Gide = ge
+	rv.Lay = gi.LayoutVert
+	rv.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_SplitView, "splitview")
+	mods, updt := rv.ConfigChildren(config)
+	if !mods {
+		updt = rv.UpdateStart()
+	}
+	rv.ConfigToolbar()
+	split := rv.SplitView()
+	split.Dim = mat32.Y
+	if !split.HasChildren() {
+		tv := giv.AddNewTableView(split, "items")
+		tv.SliceViewSig.Connect(rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			if sig == int64(giv.SliceViewDoubleClicked) {
+				rvv.ShowItem(data.(int))
+			}
+		})
+		tv.ViewSig.Connect(rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			rvv.UpdateDiff()
+		})
+		dly := gi.AddNewLayout(split, "diff", gi.LayoutVert)
+		ConfigOutputTextView(dly)
+		split.SetSplits(.4, .6)
+	}
+	rv.SetResults(res, fp, re, tm)
+	rv.UpdateView()
+	rv.UpdateEnd(updt)
+}
+
+// ToolBar returns the toolbar
+func (rv *ReplaceView) ToolBar() *gi.ToolBar {
+	return rv.ChildByName("toolbar", 0).(*gi.ToolBar)
+}
+
+// SplitView returns the splitview
+func (rv *ReplaceView) SplitView() *gi.SplitView {
+	return rv.ChildByName("splitview", 1).(*gi.SplitView)
+}
+
+// TableView returns the table of items
+func (rv *ReplaceView) TableView() *giv.TableView {
+	return rv.SplitView().ChildByName("items", 0).(*giv.TableView)
+}
+
+// DiffView returns the diff text view
+func (rv *ReplaceView) DiffView() *giv.TextView {
+	return rv.SplitView().ChildByName("diff", 1).Child(0).Embed(giv.KiT_TextView).(*giv.TextView)
+}
+
+// UpdateView updates the table of items and the diff
+func (rv *ReplaceView) UpdateView() {
+	tv := rv.TableView()
+	tv.SetStretchMax()
+	tv.NoAdd = true
+	tv.NoDelete = true
+	tv.SetSlice(&rv.Items)
+	rv.UpdateDiff()
+}
+
+// UpdateDiff updates the diff view based on current accepted items
+func (rv *ReplaceView) UpdateDiff() {
+	dtv := rv.DiffView()
+	if dtv.Buf == nil {
+		dtv.SetBuf(&giv.TextBuf{})
+		dtv.Buf.InitName(dtv.Buf, "replace-diff")
+		dtv.Buf.Opts.LineNos = false
+	}
+	dtv.Buf.SetText([]byte(rv.Diff()))
+	nitm, nfl := rv.NAccepted()
+	rv.ToolBar().ChildByName("summary", 4).(*gi.Label).SetText(fmt.Sprintf("%d of %d replacements accepted, in %d files", nitm, len(rv.Items), nfl))
+}
+
+// ShowItem shows the item at given index in its file
+func (rv *ReplaceView) ShowItem(idx int) {
+	if idx < 0 || idx >= len(rv.Items) {
+		return
+	}
+	it := rv.Items[idx]
+	rv.Gide.OpenFileAtRegion(gi.FileName(it.FPath), it.Reg)
+}
+
+// ConfigToolbar adds the toolbar
+func (rv *ReplaceView) ConfigToolbar() {
+	tb := rv.ToolBar()
+	if tb.HasChildren() {
+		return
+	}
+	tb.SetStretchMaxWidth()
+	tb.AddAction(gi.ActOpts{Label: "Apply", Icon: "checkmark", Tooltip: "apply all the accepted replacements -- each file's replacements can be undone together in one step"},
+		rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			rvv.Apply()
+		})
+	tb.AddAction(gi.ActOpts{Label: "All", Tooltip: "accept all replacements"},
+		rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			rvv.SetAccept(true)
+		})
+	tb.AddAction(gi.ActOpts{Label: "None", Tooltip: "reject all replacements"},
+		rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			rvv.SetAccept(false)
+		})
+	sf := tb.AddNewChild(gi.KiT_CheckBox, "save-files").(*gi.CheckBox)
+	sf.SetText("Save Files")
+	sf.Tooltip = "save the changed files after applying the replacements"
+	sf.SetChecked(rv.SaveFiles)
+	sf.ButtonSig.Connect(rv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			rvv, _ := recv.Embed(KiT_ReplaceView).(*ReplaceView)
+			rvv.SaveFiles = send.(*gi.CheckBox).IsChecked()
+		}
+	})
+	sl := tb.AddNewChild(gi.KiT_Label, "summary").(*gi.Label)
+	sl.SetStretchMaxWidth()
+}
+
+// ReplaceViewProps are style properties for ReplaceView
+var ReplaceViewProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"max-width":        -1,
+	"max-height":       -1,
+}
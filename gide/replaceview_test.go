@@ -0,0 +1,52 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+)
+
+func TestReplaceDiffHunk(t *testing.T) {
+	tb := &giv.TextBuf{}
+	tb.InitName(tb, "tb")
+	tb.Lines = [][]rune{[]rune("foo := Foo(foo)"), []rune("bar(foo,"), []rune("\tfoo)")}
+	tb.NLines = len(tb.Lines)
+	its := []*ReplaceItem{
+		{Accept: true, Reg: textbuf.NewRegion(0, 0, 0, 3), Repl: "baz"},
+		{Accept: true, Reg: textbuf.NewRegion(0, 11, 0, 14), Repl: "baz"},
+		{Accept: true, Reg: textbuf.NewRegion(1, 4, 2, 4), Repl: "baz"},
+	}
+	var b strings.Builder
+	ReplaceDiffHunk(&b, tb, 0, 0, its[:2])
+	ReplaceDiffHunk(&b, tb, 1, 2, its[2:])
+	diff := "@@ -1,1 +1,1 @@\n-foo := Foo(foo)\n+baz := Foo(baz)\n@@ -2,2 +2,1 @@\n-bar(foo,\n-\tfoo)\n+bar(baz)\n"
+	if b.String() != diff {
+		t.Errorf("diff should have been:\n%v\nwas:\n%v\n", diff, b.String())
+	}
+}
+
+func TestFindReplaceText(t *testing.T) {
+	tb := &giv.TextBuf{}
+	tb.InitName(tb, "tb")
+	tb.Lines = [][]rune{[]rune("Foo foo FOO")}
+	tb.NLines = len(tb.Lines)
+	reg := textbuf.NewRegion(0, 0, 0, 3)
+	fp := &FindParams{Find: "foo", Replace: "barBaz", IgnoreCase: true}
+	if _, repl := FindReplaceText(tb, reg, fp, nil); repl != "BarBaz" {
+		t.Errorf("IgnoreCase should match the case of the found text, was: %v", repl)
+	}
+	fp.PreserveCase = true
+	if _, repl := FindReplaceText(tb, textbuf.NewRegion(0, 8, 0, 11), fp, nil); repl != "BARBAZ" {
+		t.Errorf("PreserveCase should upper case the replacement, was: %v", repl)
+	}
+	fp.PreserveCase, fp.IgnoreCase = false, false
+	if _, repl := FindReplaceText(tb, reg, fp, nil); repl != "barBaz" {
+		t.Errorf("replacement should be as given, was: %v", repl)
+	}
+}
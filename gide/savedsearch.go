@@ -0,0 +1,206 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/pi/filecat"
+)
+
+// SavedSearch is a named set of find params that can be re-run
+type SavedSearch struct {
+	Name   string     `desc:"name of the search, as shown in the menu"`
+	Params FindParams `desc:"find params for the search -- histories are not saved"`
+}
+
+// SavedSearches is a list of named saved searches
+type SavedSearches []*SavedSearch
+
+// ByName returns the saved search with given name, and its index, or nil, -1
+// if not found
+func (ss *SavedSearches) ByName(name string) (*SavedSearch, int) {
+	for i, s := range *ss {
+		if s.Name == name {
+			return s, i
+		}
+	}
+	return nil, -1
+}
+
+// Add saves a copy of given find params under given name, replacing any
+// existing search of that name
+func (ss *SavedSearches) Add(name string, fp *FindParams) *SavedSearch {
+	s := &SavedSearch{Name: name, Params: *fp}
+	s.Params.FindHist = nil
+	s.Params.ReplHist = nil
+	s.Params.Langs = append([]filecat.Supported(nil), fp.Langs...)
+	s.Params.Include = append([]string(nil), fp.Include...)
+	s.Params.Exclude = append([]string(nil), fp.Exclude...)
+	if _, i := ss.ByName(name); i >= 0 {
+		(*ss)[i] = s
+		return s
+	}
+	*ss = append(*ss, s)
+	return s
+}
+
+// Delete deletes the saved search of given name, returning false if not found
+func (ss *SavedSearches) Delete(name string) bool {
+	_, i := ss.ByName(name)
+	if i < 0 {
+		return false
+	}
+	*ss = append((*ss)[:i], (*ss)[i+1:]...)
+	return true
+}
+
+// Apply sets given find params from the saved search, keeping the histories
+// of fp
+func (s *SavedSearch) Apply(fp *FindParams) {
+	fh, rh := fp.FindHist, fp.ReplHist
+	*fp = s.Params
+	fp.FindHist, fp.ReplHist = fh, rh
+}
+
+// FindExportItem is one find result as exported to, or imported from, a file
+// -- lines and columns are 1-based, as in editors and compiler messages
+type FindExportItem struct {
+	File    string `desc:"file path, relative to the project root where possible"`
+	Line    int    `desc:"starting line"`
+	Col     int    `desc:"starting column"`
+	EndLine int    `desc:"ending line"`
+	EndCol  int    `desc:"ending column"`
+	Text    string `desc:"text of the line, or message"`
+}
+
+// FindResultsExport returns the export items for given find results, with
+// file paths relative to given root
+func FindResultsExport(res []FileSearchResults, root string) []FindExportItem {
+	var its []FindExportItem
+	for _, fs := range res {
+		fn := fs.RelPath(root)
+		for _, mt := range fs.Matches {
+			txt := bytes.Replace(mt.Text, []byte("<mark>"), nil, -1)
+			txt = bytes.Replace(txt, []byte("</mark>"), nil, -1)
+			its = append(its, FindExportItem{File: fn, Line: mt.Reg.Start.Ln + 1, Col: mt.Reg.Start.Ch + 1,
+				EndLine: mt.Reg.End.Ln + 1, EndCol: mt.Reg.End.Ch + 1, Text: string(bytes.TrimSpace(txt))})
+		}
+	}
+	return its
+}
+
+// FindResultsQuickfix returns given items in the quickfix format used by
+// compilers, linters and editors: one file:line:col: text line per item
+func FindResultsQuickfix(its []FindExportItem) []byte {
+	var b bytes.Buffer
+	for _, it := range its {
+		fmt.Fprintf(&b, "%v:%d:%d: %v\n", it.File, it.Line, it.Col, it.Text)
+	}
+	return b.Bytes()
+}
+
+// SaveFindResults saves given find results to given file, in JSON format if
+// the file has a .json extension, and quickfix format otherwise
+func SaveFindResults(res []FileSearchResults, root, fname string) error {
+	its := FindResultsExport(res, root)
+	var b []byte
+	if strings.ToLower(filepath.Ext(fname)) == ".json" {
+		var err error
+		b, err = json.MarshalIndent(its, "", "  ")
+		if err != nil {
+			return err
+		}
+	} else {
+		b = FindResultsQuickfix(its)
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// quickfixRe matches a quickfix line: file:line[:col]: text
+var quickfixRe = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?:\s?(.*)$`)
+
+// ParseFindResults parses find results in JSON (a list of FindExportItem) or
+// quickfix (file:line[:col]: text) format -- lines that are not in quickfix
+// format are skipped
+func ParseFindResults(b []byte) ([]FindExportItem, error) {
+	tb := bytes.TrimSpace(b)
+	if len(tb) > 0 && tb[0] == '[' {
+		var its []FindExportItem
+		err := json.Unmarshal(tb, &its)
+		return its, err
+	}
+	var its []FindExportItem
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		sm := quickfixRe.FindStringSubmatch(strings.TrimRight(sc.Text(), "\r"))
+		if sm == nil {
+			continue
+		}
+		it := FindExportItem{File: sm[1], Text: sm[4]}
+		it.Line, _ = strconv.Atoi(sm[2])
+		it.Col, _ = strconv.Atoi(sm[3])
+		its = append(its, it)
+	}
+	return its, sc.Err()
+}
+
+// FindResultsImport returns find results for given items, grouped by file
+// in order of first appearance, with relative paths resolved against root
+func FindResultsImport(its []FindExportItem, root string) []FileSearchResults {
+	var res []FileSearchResults
+	fidx := map[string]int{}
+	for _, it := range its {
+		if it.Line < 1 {
+			continue
+		}
+		fp := it.File
+		if !filepath.IsAbs(fp) {
+			fp = filepath.Join(root, fp)
+		}
+		col := it.Col
+		if col < 1 {
+			col = 1
+		}
+		reg := textbuf.NewRegion(it.Line-1, col-1, it.Line-1, col-1)
+		if it.EndLine >= it.Line && it.EndCol >= 1 {
+			reg.End.Ln = it.EndLine - 1
+			reg.End.Ch = it.EndCol - 1
+		}
+		mt := textbuf.Match{Reg: reg, Text: []byte(it.Text)}
+		i, ok := fidx[fp]
+		if !ok {
+			i = len(res)
+			fidx[fp] = i
+			res = append(res, FileSearchResults{FPath: fp})
+		}
+		res[i].Count++
+		res[i].Matches = append(res[i].Matches, mt)
+	}
+	return res
+}
+
+// OpenFindResults reads find results from given file, in JSON or quickfix
+// format, with relative paths resolved against given root
+func OpenFindResults(fname, root string) ([]FileSearchResults, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	its, err := ParseFindResults(b)
+	if err != nil {
+		return nil, err
+	}
+	return FindResultsImport(its, root), nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFindResults(t *testing.T) {
+	qf := "# lint output\ngide/findview.go:12:5: unused variable x\n./gide/prefs.go:40: line too long\ngide/findview.go:20:1: missing doc\n"
+	its, err := ParseFindResults([]byte(qf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(its) != 3 {
+		t.Fatalf("number of items should have been: %v  was: %v\n", 3, len(its))
+	}
+	if its[0].File != "gide/findview.go" || its[0].Line != 12 || its[0].Col != 5 || its[0].Text != "unused variable x" {
+		t.Errorf("item 0 was: %+v\n", its[0])
+	}
+	res := FindResultsImport(its, "/proj")
+	if len(res) != 2 || res[0].Count != 2 || res[1].FPath != "/proj/gide/prefs.go" {
+		t.Fatalf("results should have been grouped into 2 files, were: %+v\n", res)
+	}
+	if reg := res[1].Matches[0].Reg; reg.Start.Ln != 39 || reg.Start.Ch != 0 {
+		t.Errorf("region without column should have been: 39:0  was: %v\n", reg)
+	}
+}
+
+func TestSaveFindResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gide-results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	its := []FindExportItem{{File: "a.go", Line: 3, Col: 2, EndLine: 4, EndCol: 1, Text: "multi\tline"}}
+	res := FindResultsImport(its, dir)
+	for _, ext := range []string{".json", ".txt"} {
+		fname := filepath.Join(dir, "results"+ext)
+		if err := SaveFindResults(res, dir, fname); err != nil {
+			t.Fatal(err)
+		}
+		nres, err := OpenFindResults(fname, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nres) != 1 || nres[0].FPath != res[0].FPath || nres[0].Matches[0].Reg.Start != res[0].Matches[0].Reg.Start {
+			t.Errorf("%v: results should have been: %+v  was: %+v\n", ext, res, nres)
+		}
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	var ss SavedSearches
+	fp := &FindParams{Find: "foo", Include: []string{"*.go"}, FindHist: []string{"foo"}}
+	ss.Add("foos", fp)
+	fp.Find = "bar"
+	ss.Add("foos", fp)
+	if len(ss) != 1 || ss[0].Params.Find != "bar" || ss[0].Params.FindHist != nil {
+		t.Errorf("search should have been replaced without history, was: %+v\n", ss)
+	}
+	nfp := &FindParams{FindHist: []string{"baz"}}
+	ss[0].Apply(nfp)
+	if nfp.Find != "bar" || len(nfp.FindHist) != 1 || nfp.FindHist[0] != "baz" {
+		t.Errorf("applied params were: %+v\n", nfp)
+	}
+	if !ss.Delete("foos") || len(ss) != 0 {
+		t.Errorf("search should have been deleted\n")
+	}
+}
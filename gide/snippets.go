@@ -0,0 +1,815 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/pi/complete"
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+	"github.com/goki/pi/pi"
+)
+
+// Snippet is a template of text that is inserted in place of its prefix,
+// with fields to fill in by tabbing through them.  In the body, $1, $2 etc
+// are tab stops, ${1:text} is a tab stop with default text, ${1|a,b,c|} is a
+// tab stop with a list of choices, and $0 is where the cursor ends up.  A
+// field used more than once is mirrored, with all of its copies edited at
+// once.  {ArgVar} variables such as {FileNameNoExt} and {CurWord} are
+// replaced with their values, and \ quotes the next character.
+type Snippet struct {
+	Prefix string `desc:"text that is typed to insert the snippet, which is offered as a completion"`
+	Desc   string `desc:"description of the snippet, shown in menus"`
+	Body   string `width:"60" desc:"text of the snippet, with fields as $1, ${1:default}, ${1|choice1,choice2|} and $0, and {ArgVar} variables"`
+}
+
+// Label returns the label of the snippet for menus
+func (sn *Snippet) Label() string {
+	if sn.Desc == "" {
+		return sn.Prefix
+	}
+	return sn.Prefix + ": " + sn.Desc
+}
+
+// SnippetList is a list of snippets
+type SnippetList []*Snippet
+
+// Snippets are lists of snippets for each language, by the name of the
+// language in filecat.Supported (e.g., Go, Python), with those for Any used
+// for all languages
+type Snippets map[string]SnippetList
+
+var KiT_Snippets = kit.Types.AddType(&Snippets{}, SnippetsProps)
+
+// AvailSnippets are the snippets available for all projects -- can be
+// loaded / saved / edited with preferences.  This is set to StdSnippets at
+// startup.
+var AvailSnippets Snippets
+
+func init() {
+	AvailSnippets.CopyFrom(StdSnippets)
+}
+
+// CopyFrom copies snippets from given other map
+func (sn *Snippets) CopyFrom(cp Snippets) {
+	*sn = make(Snippets, len(cp)) // reset
+	for ky, val := range cp {
+		(*sn)[ky] = append(SnippetList{}, val...)
+	}
+}
+
+// Lang returns the snippets for given language, including those for Any
+func (sn Snippets) Lang(lang filecat.Supported) SnippetList {
+	sl := append(SnippetList{}, sn[lang.String()]...)
+	if lang != filecat.Any {
+		sl = append(sl, sn[filecat.Any.String()]...)
+	}
+	return sl
+}
+
+// LangSnippets returns the snippets available for given language, with
+// those of given project (if non-nil) overriding those in AvailSnippets
+// with the same prefix, sorted by prefix
+func LangSnippets(lang filecat.Supported, pp *ProjPrefs) SnippetList {
+	var sl SnippetList
+	has := make(map[string]bool)
+	lsts := []SnippetList{AvailSnippets.Lang(lang)}
+	if pp != nil {
+		lsts = append([]SnippetList{pp.Snippets.Lang(lang)}, lsts...)
+	}
+	for _, lst := range lsts {
+		for _, sn := range lst {
+			if has[sn.Prefix] {
+				continue
+			}
+			has[sn.Prefix] = true
+			sl = append(sl, sn)
+		}
+	}
+	sort.SliceStable(sl, func(i, j int) bool {
+		return sl[i].Prefix < sl[j].Prefix
+	})
+	return sl
+}
+
+// Find returns the snippet with given prefix, or nil if none
+func (sl SnippetList) Find(prefix string) *Snippet {
+	for _, sn := range sl {
+		if sn.Prefix == prefix {
+			return sn
+		}
+	}
+	return nil
+}
+
+// PrefsSnippetsFileName is the name of the preferences file in App prefs
+// directory for saving / loading the default AvailSnippets
+var PrefsSnippetsFileName = "snippets_prefs.json"
+
+// OpenJSON opens snippets from a JSON-formatted file.
+func (sn *Snippets) OpenJSON(filename gi.FileName) error {
+	b, err := ioutil.ReadFile(string(filename))
+	if err != nil {
+		return err
+	}
+	*sn = make(Snippets) // reset
+	return json.Unmarshal(b, sn)
+}
+
+// SaveJSON saves snippets to a JSON-formatted file.
+func (sn *Snippets) SaveJSON(filename gi.FileName) error {
+	b, err := json.MarshalIndent(sn, "", "  ")
+	if err != nil {
+		log.Println(err) // unlikely
+		return err
+	}
+	err = ioutil.WriteFile(string(filename), b, 0644)
+	if err != nil {
+		gi.PromptDialog(nil, gi.DlgOpts{Title: "Could not Save to File", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+		log.Println(err)
+	}
+	return err
+}
+
+// OpenPrefs opens Snippets from App standard prefs directory, using PrefsSnippetsFileName
+func (sn *Snippets) OpenPrefs() error {
+	pdir := oswin.TheApp.AppPrefsDir()
+	pnm := filepath.Join(pdir, PrefsSnippetsFileName)
+	AvailSnippetsChanged = false
+	return sn.OpenJSON(gi.FileName(pnm))
+}
+
+// SavePrefs saves Snippets to App standard prefs directory, using PrefsSnippetsFileName
+func (sn *Snippets) SavePrefs() error {
+	pdir := oswin.TheApp.AppPrefsDir()
+	pnm := filepath.Join(pdir, PrefsSnippetsFileName)
+	AvailSnippetsChanged = false
+	return sn.SaveJSON(gi.FileName(pnm))
+}
+
+// RevertToStd reverts this map to using the StdSnippets that are compiled into
+// the program
+func (sn *Snippets) RevertToStd() {
+	sn.CopyFrom(StdSnippets)
+	AvailSnippetsChanged = true
+}
+
+// AvailSnippetsChanged is used to update toolbars via following menu, toolbar
+// props update methods -- not accurate if editing any other map but works for
+// now..
+var AvailSnippetsChanged = false
+
+// SnippetsProps define the ToolBar and MenuBar for MapView of Snippets
+var SnippetsProps = ki.Props{
+	"MainMenu": ki.PropSlice{
+		{"AppMenu", ki.BlankProp{}},
+		{"File", ki.PropSlice{
+			{"OpenPrefs", ki.Props{}},
+			{"SavePrefs", ki.Props{
+				"shortcut": "Command+S",
+				"updtfunc": giv.ActionUpdateFunc(func(sni interface{}, act *gi.Action) {
+					act.SetActiveState(AvailSnippetsChanged && sni.(*Snippets) == &AvailSnippets)
+				}),
+			}},
+			{"sep-file", ki.BlankProp{}},
+			{"OpenJSON", ki.Props{
+				"label":    "Open from file",
+				"desc":     "You can save and open snippets to / from files to share, experiment, transfer, etc",
+				"shortcut": "Command+O",
+				"Args": ki.PropSlice{
+					{"File Name", ki.Props{
+						"ext": ".json",
+					}},
+				},
+			}},
+			{"SaveJSON", ki.Props{
+				"label": "Save to file",
+				"desc":  "You can save and open snippets to / from files to share, experiment, transfer, etc",
+				"Args": ki.PropSlice{
+					{"File Name", ki.Props{
+						"ext": ".json",
+					}},
+				},
+			}},
+			{"RevertToStd", ki.Props{
+				"desc":    "This reverts the snippets to using the StdSnippets that are compiled into the program -- any of your own snippets will be lost!",
+				"confirm": true,
+			}},
+		}},
+		{"Edit", "Copy Cut Paste Dupe"},
+		{"Window", "Windows"},
+	},
+	"ToolBar": ki.PropSlice{
+		{"SavePrefs", ki.Props{
+			"desc": "saves Snippets to App standard prefs directory, in file snippets_prefs.json, which will be loaded automatically at startup)",
+			"icon": "file-save",
+			"updtfunc": giv.ActionUpdateFunc(func(sni interface{}, act *gi.Action) {
+				act.SetActiveState(AvailSnippetsChanged && sni.(*Snippets) == &AvailSnippets)
+			}),
+		}},
+		{"sep-file", ki.BlankProp{}},
+		{"OpenJSON", ki.Props{
+			"label": "Open from file",
+			"icon":  "file-open",
+			"desc":  "You can save and open snippets to / from files to share, experiment, transfer, etc",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"ext": ".json",
+				}},
+			},
+		}},
+		{"SaveJSON", ki.Props{
+			"label": "Save to file",
+			"icon":  "file-save",
+			"desc":  "You can save and open snippets to / from files to share, experiment, transfer, etc",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"ext": ".json",
+				}},
+			},
+		}},
+		{"sep-std", ki.BlankProp{}},
+		{"RevertToStd", ki.Props{
+			"icon":    "update",
+			"desc":    "This reverts the snippets to using the StdSnippets that are compiled into the program -- any of your own snippets will be lost!",
+			"confirm": true,
+		}},
+	},
+}
+
+// StdSnippets are the standard snippets compiled into the program
+var StdSnippets = Snippets{
+	filecat.Go.String(): {
+		{"pkg", "package clause", "package {FileDir}\n\n$0"},
+		{"func", "function", "// ${1:name} $2\nfunc $1(${3}) ${4:error} {\n\t$0\n}"},
+		{"meth", "method", "// ${3:name} $4\nfunc (${1:tv} *${2:Type}) $3(${5}) {\n\t$0\n}"},
+		{"test", "test function", "func Test${1:{FileNameNoExt}}(t *testing.T) {\n\t$0\n}"},
+		{"iferr", "return error if not nil", "if err != nil {\n\treturn ${1:err}\n}\n$0"},
+		{"for", "for loop over index", "for ${1:i} := 0; $1 < ${2:n}; $1++ {\n\t$0\n}"},
+		{"forr", "for loop over range", "for ${1:_}, ${2:v} := range ${3:{CurWord}} {\n\t$0\n}"},
+		{"print", "print formatted", "fmt.${1|Printf,Sprintf,Errorf|}(\"${2:%v}\\n\", $3)$0"},
+		{"type", "struct type", "// ${1:Name} $2\ntype $1 struct {\n\t$0\n}"},
+	},
+	filecat.Python.String(): {
+		{"def", "function", "def ${1:name}(${2}):\n\t${0:pass}"},
+		{"class", "class", "class ${1:Name}(${2:object}):\n\tdef __init__(self$3):\n\t\t${0:pass}"},
+		{"main", "main guard", "if __name__ == \"__main__\":\n\t${0:main()}"},
+	},
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Expansion
+
+// SnipField is a field of an expanded snippet: a tab stop with all of the
+// regions of text where it is mirrored, and any choices for its text
+type SnipField struct {
+	Num     int              `desc:"number of the field, which determines its tab order, with 0 last"`
+	Regs    []textbuf.Region `desc:"regions of the text of the field, where it is mirrored"`
+	Choices []string         `desc:"choices for the text of the field, if any"`
+}
+
+// snipSeg is a segment of a parsed snippet body: either plain text, or a
+// field with given number
+type snipSeg struct {
+	txt string
+	num int
+}
+
+// ExpandSnippet expands given snippet body, replacing ArgVar variables with
+// given values and fields with their default text, using given indent after
+// each newline and given text in place of each tab.  It returns the text
+// and the fields in tab order, with regions relative to the start of the
+// text, and a final $0 field that is added at the end if it is not in the
+// body.
+func ExpandSnippet(body string, avp *ArgVarVals, indent, tab string) (string, []*SnipField) {
+	segs, defs, chcs := parseSnippet([]rune(body), avp)
+	var sb strings.Builder
+	fmap := make(map[int]*SnipField)
+	pos := lex.Pos{}
+	addTxt := func(txt string) {
+		txt = strings.Replace(txt, "\t", tab, -1)
+		for i, ln := range strings.Split(txt, "\n") {
+			if i > 0 {
+				sb.WriteString("\n" + indent)
+				pos.Ln++
+				pos.Ch = len([]rune(indent))
+			}
+			sb.WriteString(ln)
+			pos.Ch += len([]rune(ln))
+		}
+	}
+	for _, sg := range segs {
+		if sg.num < 0 {
+			addTxt(sg.txt)
+			continue
+		}
+		fl, has := fmap[sg.num]
+		if !has {
+			fl = &SnipField{Num: sg.num, Choices: chcs[sg.num]}
+			fmap[sg.num] = fl
+		}
+		st := pos
+		addTxt(defs[sg.num])
+		fl.Regs = append(fl.Regs, textbuf.Region{Start: st, End: pos})
+	}
+	if _, has := fmap[0]; !has {
+		fmap[0] = &SnipField{Regs: []textbuf.Region{{Start: pos, End: pos}}}
+	}
+	fls := make([]*SnipField, 0, len(fmap))
+	for _, fl := range fmap {
+		fls = append(fls, fl)
+	}
+	sort.Slice(fls, func(i, j int) bool {
+		if fls[i].Num == 0 || fls[j].Num == 0 {
+			return fls[j].Num == 0 && fls[i].Num != 0
+		}
+		return fls[i].Num < fls[j].Num
+	})
+	return sb.String(), fls
+}
+
+// parseSnippet parses given snippet body into segments, returning the
+// default text and choices for each field
+func parseSnippet(body []rune, avp *ArgVarVals) ([]snipSeg, map[int]string, map[int][]string) {
+	var segs []snipSeg
+	defs := make(map[int]string)
+	chcs := make(map[int][]string)
+	var txt []rune
+	flush := func() {
+		if len(txt) > 0 {
+			segs = append(segs, snipSeg{txt: string(txt), num: -1})
+			txt = nil
+		}
+	}
+	sz := len(body)
+	for i := 0; i < sz; i++ {
+		r := body[i]
+		switch {
+		case r == '\\' && i+1 < sz:
+			i++
+			txt = append(txt, body[i])
+		case r == '$' && i+1 < sz && unicode.IsDigit(body[i+1]):
+			num, ed := snipNum(body, i+1)
+			flush()
+			segs = append(segs, snipSeg{num: num})
+			i = ed - 1
+		case r == '$' && i+2 < sz && body[i+1] == '{' && unicode.IsDigit(body[i+2]):
+			num, ed := snipNum(body, i+2)
+			cl := snipClose(body, ed)
+			if cl < 0 {
+				txt = append(txt, r)
+				continue
+			}
+			inner := body[ed:cl]
+			switch {
+			case len(inner) > 0 && inner[0] == ':':
+				if _, has := defs[num]; !has {
+					defs[num] = expandArgVars(inner[1:], avp)
+				}
+			case len(inner) > 1 && inner[0] == '|' && inner[len(inner)-1] == '|':
+				chs := strings.Split(string(inner[1:len(inner)-1]), ",")
+				chcs[num] = chs
+				if _, has := defs[num]; !has {
+					defs[num] = chs[0]
+				}
+			}
+			flush()
+			segs = append(segs, snipSeg{num: num})
+			i = cl
+		case r == '{':
+			cl := snipClose(body, i+1)
+			if cl < 0 {
+				txt = append(txt, r)
+				continue
+			}
+			vnm := string(body[i : cl+1])
+			if _, has := ArgVars[vnm]; !has {
+				txt = append(txt, r)
+				continue
+			}
+			if avp != nil {
+				txt = append(txt, []rune((*avp)[vnm])...)
+			}
+			i = cl
+		default:
+			txt = append(txt, r)
+		}
+	}
+	flush()
+	return segs, defs, chcs
+}
+
+// snipNum returns the number starting at given index in body, and the index
+// after it
+func snipNum(body []rune, st int) (int, int) {
+	num := 0
+	ed := st
+	for ed < len(body) && unicode.IsDigit(body[ed]) {
+		num = num*10 + int(body[ed]-'0')
+		ed++
+	}
+	return num, ed
+}
+
+// snipClose returns the index of the } closing a { before given index in
+// body, skipping over nested braces and quoted characters, or -1 if none
+func snipClose(body []rune, st int) int {
+	depth := 0
+	for i := st; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// expandArgVars returns given text with ArgVar variables replaced with
+// their values, and quoted characters unquoted
+func expandArgVars(txt []rune, avp *ArgVarVals) string {
+	segs, _, _ := parseSnippet(txt, avp)
+	var sb strings.Builder
+	for _, sg := range segs {
+		sb.WriteString(sg.txt)
+	}
+	return sb.String()
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  TextView snippets
+
+// SnippetState is the state of a snippet being filled in, which is active
+// until the last field is reached or the cursor leaves the fields
+type SnippetState struct {
+	Fields []*SnipField `desc:"fields of the snippet, in tab order, with their current regions in the text"`
+	Cur    int          `desc:"index of the current field"`
+}
+
+// InsertSnippet inserts given snippet in place of given region (e.g., the
+// prefix typed for it), with {CurSel} bound to any selected text, and
+// selects its first field for editing
+func (tv *TextView) InsertSnippet(sn *Snippet, reg textbuf.Region) {
+	if tv.Buf == nil || tv.IsInactive() {
+		return
+	}
+	tv.EndSnippet()
+	tv.ClearCursors()
+	cursel := ""
+	if sel := tv.Selection(); sel != nil {
+		cursel = string(sel.ToBytes())
+	}
+	wupdt := tv.TopUpdateStart()
+	defer tv.TopUpdateEnd(wupdt)
+	tv.Buf.Undos.NewGroup()
+	if reg.Start.IsLess(reg.End) {
+		tv.Buf.DeleteText(reg.Start, reg.End, giv.EditSignal)
+	}
+	tv.SelectReset()
+	tv.SetCursor(reg.Start)
+	var av ArgVarVals
+	if ge, ok := ParentGide(tv); ok {
+		av.Set(string(tv.Buf.Filename), ge.ProjPrefs(), &tv.TextView)
+	} else {
+		av.Set(string(tv.Buf.Filename), &ProjPrefs{}, &tv.TextView)
+	}
+	av["{CurSel}"] = cursel
+	tab := "\t"
+	if tv.Buf.Opts.SpaceIndent {
+		tab = strings.Repeat(" ", tv.Buf.Opts.TabSize)
+	}
+	txt, fls := ExpandSnippet(sn.Body, &av, lineIndent(tv.Buf.Line(reg.Start.Ln)), tab)
+	st := reg.Start
+	if len(txt) > 0 {
+		tv.Buf.InsertText(st, []byte(txt), giv.EditSignal)
+	}
+	tv.Buf.Undos.NewGroup()
+	for _, fl := range fls {
+		for i := range fl.Regs {
+			fl.Regs[i].Start = snipPos(st, fl.Regs[i].Start)
+			fl.Regs[i].End = snipPos(st, fl.Regs[i].End)
+		}
+	}
+	tv.Snippet = &SnippetState{Fields: fls}
+	tv.SnippetField(0)
+}
+
+// lineIndent returns the indentation at the start of given line
+func lineIndent(txt []rune) string {
+	i := 0
+	for i < len(txt) && (txt[i] == ' ' || txt[i] == '\t') {
+		i++
+	}
+	return string(txt[:i])
+}
+
+// snipPos returns given position relative to the start of a snippet as a
+// position in the text, with the snippet starting at given position
+func snipPos(st, pos lex.Pos) lex.Pos {
+	if pos.Ln == 0 {
+		pos.Ch += st.Ch
+	}
+	pos.Ln += st.Ln
+	return pos
+}
+
+// SnippetField selects the field of the current snippet at given index in
+// tab order, with a cursor selecting each of its mirrored regions, and
+// offers its choices if it has them -- the snippet is done when its last
+// field is reached
+func (tv *TextView) SnippetField(idx int) {
+	ss := tv.Snippet
+	if ss == nil || idx < 0 || idx >= len(ss.Fields) {
+		return
+	}
+	ss.Cur = idx
+	fl := ss.Fields[idx]
+	cs := make([]TextCursor, len(fl.Regs))
+	for i, reg := range fl.Regs {
+		cs[i] = TextCursor{Pos: reg.End, Sel: reg}
+	}
+	tv.SetAllCursors(cs)
+	if idx == len(ss.Fields)-1 {
+		tv.Snippet = nil
+		return
+	}
+	if len(fl.Choices) > 0 {
+		tv.OfferSnippetChoices(fl.Choices)
+	}
+}
+
+// NextSnippetField moves to the next (dir > 0) or previous field of the
+// current snippet, returning false if there is no snippet active
+func (tv *TextView) NextSnippetField(dir int) bool {
+	ss := tv.Snippet
+	if ss == nil {
+		return false
+	}
+	idx := ss.Cur + dir
+	if idx < 0 {
+		idx = 0
+	}
+	tv.SnippetField(idx)
+	return true
+}
+
+// EndSnippet ends filling in the current snippet, if any
+func (tv *TextView) EndSnippet() {
+	tv.Snippet = nil
+}
+
+// OfferSnippetChoices pops up a menu of given choices for the text of the
+// current snippet field
+func (tv *TextView) OfferSnippetChoices(chs []string) {
+	if tv.Viewport == nil {
+		return
+	}
+	var m gi.Menu
+	for _, ch := range chs {
+		m.AddAction(gi.ActOpts{Label: ch, Data: ch}, tv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			txf := recv.Embed(KiT_TextView).(*TextView)
+			txf.InsertAtCursors([]byte(data.(string)))
+		})
+	}
+	cpos := tv.CharStartPos(tv.CursorPos).ToPoint()
+	cpos.X += 5
+	cpos.Y += 10
+	gi.PopupMenu(m, cpos.X, cpos.Y, tv.Viewport, "tv-snippet-choices")
+}
+
+// OfferSnippets inserts the snippet in given list whose prefix is the word
+// before the cursor, if there is one, and otherwise pops up a menu of the
+// snippets, which replace the selection, if any
+func (tv *TextView) OfferSnippets(sl SnippetList) {
+	if tv.Buf == nil || tv.IsInactive() || len(sl) == 0 {
+		return
+	}
+	if !tv.HasSelection() {
+		txt := tv.Buf.Line(tv.CursorPos.Ln)
+		st := tv.CursorPos.Ch
+		if st > len(txt) {
+			st = len(txt)
+		}
+		for st > 0 && isSnippetPrefixRune(txt[st-1]) {
+			st--
+		}
+		if sn := sl.Find(string(txt[st:tv.CursorPos.Ch])); sn != nil && st < tv.CursorPos.Ch {
+			tv.InsertSnippet(sn, textbuf.Region{Start: lex.Pos{Ln: tv.CursorPos.Ln, Ch: st}, End: tv.CursorPos})
+			return
+		}
+	}
+	if tv.Viewport == nil {
+		return
+	}
+	var m gi.Menu
+	for _, sn := range sl {
+		m.AddAction(gi.ActOpts{Label: sn.Label(), Data: sn}, tv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			txf := recv.Embed(KiT_TextView).(*TextView)
+			reg := textbuf.Region{Start: txf.CursorPos, End: txf.CursorPos}
+			if txf.HasSelection() {
+				reg = txf.SelectReg
+			}
+			txf.InsertSnippet(data.(*Snippet), reg)
+		})
+	}
+	cpos := tv.CharStartPos(tv.CursorPos).ToPoint()
+	cpos.X += 5
+	cpos.Y += 10
+	gi.PopupMenu(m, cpos.X, cpos.Y, tv.Viewport, "tv-snippets")
+}
+
+// isSnippetPrefixRune returns true if given rune can be part of the prefix
+// of a snippet
+func isSnippetPrefixRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// SnippetKeyInput handles the keys for moving between the fields of the
+// current snippet: tab and shift-tab move to the next and previous field,
+// and escape ends the snippet
+func (tv *TextView) SnippetKeyInput(kt *key.ChordEvent) {
+	if tv.Snippet == nil {
+		return
+	}
+	if win := tv.ParentWindow(); win != nil && gi.PopupIsCompleter(win.CurPopup()) {
+		return
+	}
+	switch gi.KeyFun(kt.Chord()) {
+	case gi.KeyFunFocusNext:
+		kt.SetProcessed()
+		tv.NextSnippetField(1)
+	case gi.KeyFunFocusPrev:
+		kt.SetProcessed()
+		tv.NextSnippetField(-1)
+	case gi.KeyFunAbort:
+		tv.EndSnippet()
+	}
+}
+
+// AdjustSnippetReg returns given region of a snippet field adjusted for
+// given edit: insertions within the region, or at its start if it is the
+// current field being edited, extend it.
+func AdjustSnippetReg(tbe *textbuf.Edit, reg textbuf.Region, cur bool) textbuf.Region {
+	st := tbe.Reg.Start
+	if tbe.Delete || reg.End.IsLess(st) {
+		reg.Start = AdjustCursorPos(tbe, reg.Start)
+		reg.End = AdjustCursorPos(tbe, reg.End)
+		return reg
+	}
+	if st.IsLess(reg.Start) || (st == reg.Start && !cur) {
+		reg.Start = insertPos(tbe, reg.Start)
+	}
+	reg.End = insertPos(tbe, reg.End)
+	return reg
+}
+
+// insertPos returns given position, at or after the start of given
+// insertion, moved along with the text after the insertion
+func insertPos(tbe *textbuf.Edit, pos lex.Pos) lex.Pos {
+	st := tbe.Reg.Start
+	ed := tbe.Reg.End
+	if pos.Ln == st.Ln {
+		pos.Ch = ed.Ch + pos.Ch - st.Ch
+	}
+	pos.Ln += ed.Ln - st.Ln
+	return pos
+}
+
+// SnippetBufSig updates the regions of the fields of the current snippet
+// for given signal from the buffer: edits outside of the current field end
+// the snippet
+func (tv *TextView) SnippetBufSig(sig giv.TextBufSignals, data interface{}) {
+	ss := tv.Snippet
+	if ss == nil {
+		return
+	}
+	tbe, ok := data.(*textbuf.Edit)
+	if !ok || (sig != giv.TextBufInsert && sig != giv.TextBufDelete) {
+		if sig == giv.TextBufNew || sig == giv.TextBufClosed {
+			tv.EndSnippet()
+		}
+		return
+	}
+	in := false
+	for _, reg := range ss.Fields[ss.Cur].Regs {
+		if !tbe.Reg.Start.IsLess(reg.Start) && !reg.End.IsLess(tbe.Reg.Start) {
+			in = true
+			break
+		}
+	}
+	if !in {
+		tv.EndSnippet()
+		return
+	}
+	for fi, fl := range ss.Fields {
+		for i := range fl.Regs {
+			fl.Regs[i] = AdjustSnippetReg(tbe, fl.Regs[i], fi == ss.Cur)
+		}
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Completion
+
+// SetSnippetCompleter adds the snippets returned by given function to the
+// completions offered for given buffer by its standard pi completer, and
+// inserts them when they are selected
+func SetSnippetCompleter(tb *giv.TextBuf, snips func() SnippetList) {
+	if tb.Complete == nil {
+		return
+	}
+	if _, ok := tb.Complete.Context.(*pi.FileStates); !ok {
+		return
+	}
+	tb.Complete.MatchFunc = func(data interface{}, text string, posLn, posCh int) complete.Matches {
+		md := giv.CompletePi(data, text, posLn, posCh)
+		return AddSnippetMatches(md, text, snips())
+	}
+	tb.Complete.CompleteSig.ConnectOnly(tb.This(), SnippetCompleteSigRecv)
+}
+
+// AddSnippetMatches adds the snippets in given list whose prefix starts with
+// the seed of given completion matches to them, where there is not already
+// a completion with the same text, using the word at the end of given text
+// as the seed if there are no matches
+func AddSnippetMatches(md complete.Matches, text string, sl SnippetList) complete.Matches {
+	if md.Seed == "" && len(md.Matches) == 0 {
+		txt := []rune(text)
+		st := len(txt)
+		for st > 0 && isSnippetPrefixRune(txt[st-1]) {
+			st--
+		}
+		md.Seed = string(txt[st:])
+	}
+	if md.Seed == "" {
+		return md
+	}
+	has := make(map[string]bool, len(md.Matches))
+	for _, c := range md.Matches {
+		has[c.Text] = true
+	}
+	for _, sn := range sl {
+		if !strings.HasPrefix(sn.Prefix, md.Seed) || has[sn.Prefix] {
+			continue
+		}
+		has[sn.Prefix] = true
+		md.Matches = append(md.Matches, complete.Completion{Text: sn.Prefix, Label: sn.Label() + " (snippet)",
+			Desc: sn.Desc, Extra: map[string]string{"snippet": sn.Body}})
+	}
+	return md
+}
+
+// SnippetCompleteSigRecv receives the completion selected for a buffer,
+// inserting snippets in its view, and otherwise completing the text as usual
+func SnippetCompleteSigRecv(recv, send ki.Ki, sig int64, data interface{}) {
+	tb, _ := recv.Embed(giv.KiT_TextBuf).(*giv.TextBuf)
+	s, _ := data.(string)
+	if tb == nil || tb.Complete == nil || s == "" {
+		return
+	}
+	switch sig {
+	case int64(gi.CompleteSelect):
+		c := tb.Complete.GetCompletion(s)
+		body, isSnip := c.Extra["snippet"]
+		if !isSnip || tb.CurView == nil {
+			tb.CompleteText(s)
+			return
+		}
+		tv, ok := tb.CurView.This().Embed(KiT_TextView).(*TextView)
+		tb.CurView = nil
+		if !ok {
+			return
+		}
+		ed := lex.Pos{Ln: tb.Complete.SrcLn, Ch: tb.Complete.SrcCh}
+		st := ed
+		st.Ch -= len([]rune(tb.Complete.Seed))
+		if st.Ch < 0 {
+			st.Ch = 0
+		}
+		tv.InsertSnippet(&Snippet{Prefix: s, Desc: c.Desc, Body: body}, textbuf.Region{Start: st, End: ed})
+	case int64(gi.CompleteExtend):
+		tb.CompleteExtend(s)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"testing"
+
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/pi/complete"
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+)
+
+func TestExpandSnippet(t *testing.T) {
+	av := ArgVarVals{"{FileNameNoExt}": "foo", "{CurWord}": "list"}
+	txt, fls := ExpandSnippet("func Test${1:{FileNameNoExt}}() {\n\tfor $2 := range {CurWord} {\n\t\t$2$0\n\t}\n}", &av, "  ", "\t")
+	exp := "func Testfoo() {\n  \tfor  := range list {\n  \t\t\n  \t}\n  }"
+	if txt != exp {
+		t.Errorf("text should be:\n%s\nwas:\n%s", exp, txt)
+	}
+	if len(fls) != 3 || fls[0].Num != 1 || fls[1].Num != 2 || fls[2].Num != 0 {
+		t.Fatalf("should have fields 1, 2, 0, was: %v", fls)
+	}
+	if reg := fls[0].Regs[0]; reg.Start != (lex.Pos{0, 9}) || reg.End != (lex.Pos{0, 12}) {
+		t.Errorf("field 1 should be at 0:9-0:12, was: %v", reg)
+	}
+	if len(fls[1].Regs) != 2 || fls[1].Regs[0].Start != (lex.Pos{1, 7}) || fls[1].Regs[1].Start != (lex.Pos{2, 4}) {
+		t.Errorf("field 2 should be mirrored at 1:7 and 2:4, was: %v", fls[1].Regs)
+	}
+	if reg := fls[2].Regs[0]; reg.Start != (lex.Pos{2, 4}) {
+		t.Errorf("field 0 should be at 2:4, was: %v", reg)
+	}
+}
+
+func TestExpandSnippetChoices(t *testing.T) {
+	txt, fls := ExpandSnippet("fmt.${1|Printf,Sprintf|}(\"\\$x {NotAVar}\")", nil, "", "    ")
+	exp := "fmt.Printf(\"$x {NotAVar}\")"
+	if txt != exp {
+		t.Errorf("text should be: %s  was: %s", exp, txt)
+	}
+	if len(fls) != 2 || len(fls[0].Choices) != 2 || fls[0].Choices[1] != "Sprintf" {
+		t.Fatalf("field 1 should have 2 choices, was: %v", fls)
+	}
+	if reg := fls[1].Regs[0]; reg.Start != (lex.Pos{0, len([]rune(exp))}) {
+		t.Errorf("final field should be added at the end, was: %v", reg)
+	}
+}
+
+func TestAdjustSnippetReg(t *testing.T) {
+	reg := textbuf.NewRegion(0, 4, 0, 4)
+	ins := &textbuf.Edit{Reg: textbuf.NewRegion(0, 4, 0, 6)}
+	if res := AdjustSnippetReg(ins, reg, true); res.Start != (lex.Pos{0, 4}) || res.End != (lex.Pos{0, 6}) {
+		t.Errorf("insert in current field should extend it, was: %v", res)
+	}
+	if res := AdjustSnippetReg(ins, reg, false); res.Start != (lex.Pos{0, 6}) || res.End != (lex.Pos{0, 6}) {
+		t.Errorf("insert at other field should move it, was: %v", res)
+	}
+	del := &textbuf.Edit{Reg: textbuf.NewRegion(0, 1, 0, 3), Delete: true}
+	if res := AdjustSnippetReg(del, textbuf.NewRegion(0, 4, 0, 8), false); res.Start != (lex.Pos{0, 2}) || res.End != (lex.Pos{0, 6}) {
+		t.Errorf("delete before field should move it, was: %v", res)
+	}
+}
+
+func TestAddSnippetMatches(t *testing.T) {
+	sl := SnippetList{{"for", "loop", "for {}"}, {"func", "", "func"}, {"if", "", "if"}}
+	md := AddSnippetMatches(complete.Matches{}, "\tx := f", sl)
+	if md.Seed != "f" || len(md.Matches) != 2 || md.Matches[0].Extra["snippet"] != "for {}" {
+		t.Errorf("should match for and func snippets, was: %v", md)
+	}
+	md = AddSnippetMatches(complete.Matches{Seed: "f", Matches: complete.Completions{{Text: "func"}}}, "f", sl)
+	if len(md.Matches) != 2 || md.Matches[1].Text != "for" {
+		t.Errorf("should not add snippet with same text as a completion, was: %v", md)
+	}
+}
+
+func TestLangSnippets(t *testing.T) {
+	pp := &ProjPrefs{Snippets: Snippets{"Go": {{"iferr", "proj", "x"}}}}
+	sl := LangSnippets(filecat.Go, pp)
+	sn := sl.Find("iferr")
+	if sn == nil || sn.Desc != "proj" {
+		t.Errorf("project snippet should override prefs snippet, was: %v", sn)
+	}
+	if sl.Find("func") == nil {
+		t.Errorf("should include standard snippets")
+	}
+}
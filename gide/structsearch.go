@@ -0,0 +1,530 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/giv/textbuf"
+)
+
+// Structural search matches a Go code pattern against the syntax trees of Go
+// files, instead of their text.  The pattern is a Go expression, statement
+// list or declaration(s), in which $name is a metavariable that matches any
+// single node (expression, statement, identifier, etc), and $*name matches
+// any number of nodes in a list (arguments, statements, parameters, etc).
+// A metavariable that appears more than once must match the same code each
+// time, except for $_ and $*_ which match anything.  For example:
+//
+//	log.Printf($fmt, $a, $b)
+//	func ($r *FileNode) $name($*params) error { $*_ }
+//	if $err != nil { return $err }
+//
+// A rewrite template for a structural replace is Go code in which each
+// metavariable is replaced with the source of the code it matched.
+
+const (
+	// structMetaPrefix is the identifier prefix that $name metavariables are
+	// translated into so that the pattern can be parsed as Go
+	structMetaPrefix = "gide_mv_"
+
+	// structListPrefix is the identifier prefix for $*name metavariables
+	structListPrefix = "gide_mvs_"
+)
+
+// structMetaRe matches the metavariables in a pattern or rewrite template
+var structMetaRe = regexp.MustCompile(`\$(\*?)([A-Za-z_][A-Za-z0-9_]*)`)
+
+// structKind is the kind of code a structural pattern is
+type structKind int
+
+const (
+	structExpr structKind = iota
+	structStmts
+	structDecls
+)
+
+// structStmtsPrefix wraps a statement list to parse it as a file
+const structStmtsPrefix = "package p; func _() {\n"
+
+// StructPattern is a parsed structural search pattern -- use
+// ParseStructPattern to create
+type StructPattern struct {
+	Src   string     `desc:"source of the pattern, with $name metavariables"`
+	kind  structKind // kind of code in the pattern
+	nodes []ast.Node // parsed pattern: one expression, or a list of statements or declarations
+}
+
+// ParseStructPattern parses given structural search pattern, which must be a
+// Go expression, statement list or declaration list, with $name and $*name
+// metavariables
+func ParseStructPattern(pat string) (*StructPattern, error) {
+	pat = strings.TrimSpace(pat)
+	if pat == "" {
+		return nil, fmt.Errorf("structural search pattern is empty")
+	}
+	src := structMetaRe.ReplaceAllStringFunc(pat, func(mv string) string {
+		if strings.HasPrefix(mv, "$*") {
+			return structListPrefix + mv[2:]
+		}
+		return structMetaPrefix + mv[1:]
+	})
+	sp := &StructPattern{Src: pat}
+	for _, kind := range []structKind{structExpr, structStmts, structDecls} {
+		_, nodes, _, err := parseStructSnippet(kind, src)
+		if err == nil && len(nodes) > 0 {
+			sp.kind = kind
+			sp.nodes = nodes
+			return sp, nil
+		}
+	}
+	return nil, fmt.Errorf("structural search pattern is not a valid Go expression, statement list or declaration: %v", pat)
+}
+
+// parseStructSnippet parses given source as the given kind of code, returning
+// the file set, top-level nodes, and source (wrapped as needed to parse it)
+// which the node positions refer to
+func parseStructSnippet(kind structKind, src string) (*token.FileSet, []ast.Node, []byte, error) {
+	fset := token.NewFileSet()
+	switch kind {
+	case structExpr:
+		ex, err := parser.ParseExprFrom(fset, "", src, 0)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return fset, []ast.Node{ex}, []byte(src), nil
+	case structStmts:
+		wsrc := []byte(structStmtsPrefix + src + "\n}\n")
+		f, err := parser.ParseFile(fset, "", wsrc, 0)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var nodes []ast.Node
+		for _, st := range f.Decls[0].(*ast.FuncDecl).Body.List {
+			nodes = append(nodes, st)
+		}
+		return fset, nodes, wsrc, nil
+	default:
+		wsrc := []byte("package p\n" + src + "\n")
+		f, err := parser.ParseFile(fset, "", wsrc, 0)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var nodes []ast.Node
+		for _, d := range f.Decls {
+			nodes = append(nodes, d)
+		}
+		return fset, nodes, wsrc, nil
+	}
+}
+
+// structMatch is one match of a pattern, as start, end offsets in the source
+type structMatch struct {
+	St, Ed int
+}
+
+// Search returns all the matches of the pattern in given Go source file
+func (sp *StructPattern) Search(src []byte) (int, []textbuf.Match) {
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, "", src, 0)
+	if f == nil {
+		return 0, nil
+	}
+	sms := sp.searchFile(fset, f)
+	locs := make([][]int, len(sms))
+	for i, sm := range sms {
+		locs[i] = []int{sm.St, sm.Ed}
+	}
+	matches := MatchesAtOffsets(src, locs)
+	return len(matches), matches
+}
+
+// searchFile returns the matches of the pattern in given parsed file, in
+// source order
+func (sp *StructPattern) searchFile(fset *token.FileSet, f *ast.File) []structMatch {
+	var sms []structMatch
+	offs := func(st, ed ast.Node) {
+		sms = append(sms, structMatch{St: fset.Position(st.Pos()).Offset, Ed: fset.Position(ed.End()).Offset})
+	}
+	if len(sp.nodes) > 1 {
+		if sp.kind == structDecls {
+			nodes := make([]ast.Node, len(f.Decls))
+			for i, d := range f.Decls {
+				nodes[i] = d
+			}
+			sp.searchList(nodes, offs)
+			return sms
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			var list []ast.Stmt
+			switch nt := n.(type) {
+			case *ast.BlockStmt:
+				list = nt.List
+			case *ast.CaseClause:
+				list = nt.Body
+			case *ast.CommClause:
+				list = nt.Body
+			default:
+				return true
+			}
+			nodes := make([]ast.Node, len(list))
+			for i, st := range list {
+				nodes[i] = st
+			}
+			sp.searchList(nodes, offs)
+			return true
+		})
+	} else {
+		pn := reflect.ValueOf(sp.nodes[0])
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil {
+				return false
+			}
+			switch sp.kind {
+			case structExpr:
+				if _, ok := n.(ast.Expr); !ok {
+					return true
+				}
+			case structStmts:
+				if _, ok := n.(ast.Stmt); !ok {
+					return true
+				}
+			default:
+				if _, ok := n.(ast.Decl); !ok {
+					return true
+				}
+			}
+			m := &structMatcher{binds: structBinds{}}
+			if m.match(pn, reflect.ValueOf(n)) {
+				offs(n, n)
+			}
+			return true
+		})
+	}
+	sort.SliceStable(sms, func(i, j int) bool {
+		return sms[i].St < sms[j].St
+	})
+	return sms
+}
+
+// searchList finds the non-overlapping runs of consecutive nodes in given
+// list that match the pattern list, preferring the longest run from each
+// starting node
+func (sp *StructPattern) searchList(nodes []ast.Node, fun func(st, ed ast.Node)) {
+	pats := make([]reflect.Value, len(sp.nodes))
+	for i, pn := range sp.nodes {
+		pats[i] = reflect.ValueOf(pn)
+	}
+	for i := 0; i < len(nodes); i++ {
+		for j := len(nodes); j > i; j-- {
+			ns := make([]reflect.Value, j-i)
+			for k := range ns {
+				ns[k] = reflect.ValueOf(nodes[i+k])
+			}
+			m := &structMatcher{binds: structBinds{}}
+			if m.matchList(pats, ns) {
+				fun(nodes[i], nodes[j-1])
+				i = j - 1
+				break
+			}
+		}
+	}
+}
+
+// Rewrite returns the given rewrite template expanded for the match of the
+// pattern in given source code (the text of a match), and false if the
+// pattern does not match it
+func (sp *StructPattern) Rewrite(orig, tmpl string) (string, bool) {
+	fset, nodes, src, err := parseStructSnippet(sp.kind, orig)
+	if err != nil && sp.kind == structExpr {
+		fset, nodes, src, err = parseStructSnippet(structStmts, orig) // e.g., a statement-level expression
+	}
+	if err != nil {
+		return orig, false
+	}
+	pats := make([]reflect.Value, len(sp.nodes))
+	for i, pn := range sp.nodes {
+		pats[i] = reflect.ValueOf(pn)
+	}
+	ns := make([]reflect.Value, len(nodes))
+	for i, n := range nodes {
+		ns[i] = reflect.ValueOf(n)
+	}
+	m := &structMatcher{binds: structBinds{}}
+	ok := false
+	if len(pats) == 1 && len(ns) == 1 {
+		ok = m.match(pats[0], ns[0])
+	} else {
+		ok = m.matchList(pats, ns)
+	}
+	if !ok {
+		return orig, false
+	}
+	return m.binds.Expand(fset, src, tmpl), true
+}
+
+// SearchFileStruct finds all the matches of the structural pattern in given
+// Go file
+func SearchFileStruct(fpath string, sp *StructPattern) (int, []textbuf.Match) {
+	src, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return 0, nil
+	}
+	return sp.Search(src)
+}
+
+// SearchBufStruct finds all the matches of the structural pattern in given
+// buffer, which must contain Go code
+func SearchBufStruct(tb *giv.TextBuf, sp *StructPattern) (int, []textbuf.Match) {
+	tb.LinesMu.RLock()
+	src := bytes.Join(tb.LineBytes, []byte("\n"))
+	tb.LinesMu.RUnlock()
+	return sp.Search(src)
+}
+
+// StructReplaceText returns the replacement for the text of a structural
+// match, by re-matching the pattern against it and expanding the rewrite
+// template -- returns orig if the pattern is invalid or does not match
+func StructReplaceText(orig, pat, tmpl string) string {
+	sp, err := ParseStructPattern(pat)
+	if err != nil {
+		return orig
+	}
+	repl, _ := sp.Rewrite(orig, tmpl)
+	return repl
+}
+
+// structBind is the code bound to a metavariable
+type structBind struct {
+	Node reflect.Value   `desc:"node for a $name metavariable"`
+	List []reflect.Value `desc:"nodes for a $*name metavariable"`
+}
+
+// structBinds are the bindings of metavariables, by name
+type structBinds map[string]structBind
+
+// Expand returns the template with each metavariable replaced by the source
+// of the code bound to it -- unbound metavariables are left as is
+func (bs structBinds) Expand(fset *token.FileSet, src []byte, tmpl string) string {
+	srcOf := func(st, ed ast.Node) string {
+		return string(src[fset.Position(st.Pos()).Offset:fset.Position(ed.End()).Offset])
+	}
+	return structMetaRe.ReplaceAllStringFunc(tmpl, func(mv string) string {
+		sm := structMetaRe.FindStringSubmatch(mv)
+		b, ok := bs[sm[2]]
+		if !ok {
+			return mv
+		}
+		if sm[1] == "" {
+			if !b.Node.IsValid() {
+				return mv
+			}
+			n := b.Node.Interface().(ast.Node)
+			return srcOf(n, n)
+		}
+		if len(b.List) == 0 {
+			return ""
+		}
+		return srcOf(b.List[0].Interface().(ast.Node), b.List[len(b.List)-1].Interface().(ast.Node))
+	})
+}
+
+// structMatcher matches pattern syntax trees against code, recording the
+// metavariable bindings
+type structMatcher struct {
+	binds structBinds
+}
+
+var (
+	structPosType    = reflect.TypeOf(token.NoPos)
+	structObjType    = reflect.TypeOf((*ast.Object)(nil))
+	structScopeType  = reflect.TypeOf((*ast.Scope)(nil))
+	structCmtType    = reflect.TypeOf((*ast.CommentGroup)(nil))
+	structIdentType  = reflect.TypeOf((*ast.Ident)(nil))
+	structNodeIfType = reflect.TypeOf((*ast.Node)(nil)).Elem()
+)
+
+// metaName returns the metavariable name and whether it is a list
+// metavariable, if the node is a metavariable identifier, or an expression
+// statement or field consisting only of one
+func metaName(v reflect.Value) (name string, list, ok bool) {
+	v = structElem(v)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return "", false, false
+	}
+	var id *ast.Ident
+	switch nt := v.Interface().(type) {
+	case *ast.Ident:
+		id = nt
+	case *ast.ExprStmt:
+		id, _ = nt.X.(*ast.Ident)
+	case *ast.Field:
+		if len(nt.Names) == 0 && nt.Tag == nil {
+			id, _ = nt.Type.(*ast.Ident)
+		}
+	}
+	if id == nil {
+		return "", false, false
+	}
+	switch {
+	case strings.HasPrefix(id.Name, structListPrefix):
+		return strings.TrimPrefix(id.Name, structListPrefix), true, true
+	case strings.HasPrefix(id.Name, structMetaPrefix):
+		return strings.TrimPrefix(id.Name, structMetaPrefix), false, true
+	}
+	return "", false, false
+}
+
+// bind binds given metavariable to given node, returning false if it is
+// already bound to different code
+func (m *structMatcher) bind(name string, n reflect.Value) bool {
+	if name == "_" {
+		return true
+	}
+	if b, has := m.binds[name]; has {
+		if !b.Node.IsValid() {
+			return false
+		}
+		em := &structMatcher{binds: structBinds{}}
+		return em.match(b.Node, n)
+	}
+	m.binds[name] = structBind{Node: n}
+	return true
+}
+
+// bindList binds given list metavariable to given nodes, returning false if
+// it is already bound to different code
+func (m *structMatcher) bindList(name string, ns []reflect.Value) bool {
+	if name == "_" {
+		return true
+	}
+	if b, has := m.binds[name]; has {
+		if len(b.List) != len(ns) || b.Node.IsValid() {
+			return false
+		}
+		em := &structMatcher{binds: structBinds{}}
+		return em.matchList(b.List, ns)
+	}
+	m.binds[name] = structBind{List: append([]reflect.Value(nil), ns...)}
+	return true
+}
+
+// match returns true if the pattern value matches the code value
+func (m *structMatcher) match(p, n reflect.Value) bool {
+	p = structElem(p)
+	n = structElem(n)
+	if !p.IsValid() || !n.IsValid() {
+		return !p.IsValid() && !n.IsValid()
+	}
+	if name, list, ok := metaName(p); ok && !list {
+		if _, isid := p.Interface().(*ast.Ident); isid {
+			if n.Kind() != reflect.Ptr || n.IsNil() || !n.Type().Implements(structNodeIfType) {
+				return false
+			}
+			return m.bind(name, n)
+		}
+		if _, isst := n.Interface().(ast.Stmt); isst {
+			return m.bind(name, n)
+		}
+	}
+	if p.Type() != n.Type() {
+		return false
+	}
+	switch p.Kind() {
+	case reflect.Ptr:
+		if p.IsNil() || n.IsNil() {
+			return p.IsNil() && n.IsNil()
+		}
+		if p.Type() == structIdentType {
+			return p.Interface().(*ast.Ident).Name == n.Interface().(*ast.Ident).Name
+		}
+		return m.match(p.Elem(), n.Elem())
+	case reflect.Struct:
+		for i := 0; i < p.NumField(); i++ {
+			ft := p.Type().Field(i)
+			switch ft.Type {
+			case structObjType, structScopeType, structCmtType:
+				continue
+			case structPosType:
+				if ft.Name == "Ellipsis" && p.Field(i).Interface().(token.Pos).IsValid() != n.Field(i).Interface().(token.Pos).IsValid() {
+					return false
+				}
+				continue
+			}
+			if !m.match(p.Field(i), n.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		ps := make([]reflect.Value, p.Len())
+		for i := range ps {
+			ps[i] = p.Index(i)
+		}
+		ns := make([]reflect.Value, n.Len())
+		for i := range ns {
+			ns[i] = n.Index(i)
+		}
+		return m.matchList(ps, ns)
+	default:
+		return p.Interface() == n.Interface()
+	}
+}
+
+// structElem returns the concrete value of an interface value, which is
+// invalid if the interface is nil
+func structElem(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
+
+// matchList returns true if the pattern list matches the entire code list,
+// with list metavariables matching any number of elements
+func (m *structMatcher) matchList(ps, ns []reflect.Value) bool {
+	if len(ps) == 0 {
+		return len(ns) == 0
+	}
+	if name, list, ok := metaName(ps[0]); ok && list {
+		for k := 0; k <= len(ns); k++ {
+			save := m.saveBinds()
+			if m.bindList(name, ns[:k]) && m.matchList(ps[1:], ns[k:]) {
+				return true
+			}
+			m.binds = save
+		}
+		return false
+	}
+	if len(ns) == 0 {
+		return false
+	}
+	save := m.saveBinds()
+	if m.match(ps[0], ns[0]) && m.matchList(ps[1:], ns[1:]) {
+		return true
+	}
+	m.binds = save
+	return false
+}
+
+// saveBinds returns a copy of the current bindings, to restore when
+// backtracking
+func (m *structMatcher) saveBinds() structBinds {
+	bs := make(structBinds, len(m.binds))
+	for k, v := range m.binds {
+		bs[k] = v
+	}
+	return bs
+}
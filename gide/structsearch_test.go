@@ -0,0 +1,85 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"testing"
+)
+
+var structSearchSrc = `package gide
+
+func (fn *FileNode) Open() error {
+	log.Printf("open: %v %v\n", fn.Name, fn.Path)
+	return nil
+}
+
+func (fn *FileNode) Name() string {
+	log.Printf("name: %v\n", fn.Nm)
+	return fn.Nm
+}
+
+func (fn *DirNode) Close() error {
+	if err := fn.Sync(); err != nil {
+		return err
+	}
+	x := a + a
+	y := a + b
+	return nil
+}
+`
+
+func TestStructSearch(t *testing.T) {
+	tests := []struct {
+		pat string
+		cnt int
+		ln  int
+	}{
+		{`log.Printf($fmt, $a, $b)`, 1, 3},
+		{`log.Printf($*_)`, 2, 3},
+		{`func ($r *FileNode) $name($*params) error { $*_ }`, 1, 2},
+		{`if $x := $call; $x != nil { return $x }`, 1, 13},
+		{`$a + $a`, 1, 16},
+		{`$a + $b`, 2, 16},
+		{`$x := $y; $*rest; return nil`, 1, 16},
+		{`fn.Nm`, 2, 8},
+	}
+	for _, tst := range tests {
+		sp, err := ParseStructPattern(tst.pat)
+		if err != nil {
+			t.Errorf("pattern: %v error: %v\n", tst.pat, err)
+			continue
+		}
+		cnt, matches := sp.Search([]byte(structSearchSrc))
+		if cnt != tst.cnt {
+			t.Errorf("pattern: %v count should have been: %v  was: %v\n", tst.pat, tst.cnt, cnt)
+			continue
+		}
+		if matches[0].Reg.Start.Ln != tst.ln {
+			t.Errorf("pattern: %v line should have been: %v  was: %v\n", tst.pat, tst.ln, matches[0].Reg.Start.Ln)
+		}
+	}
+	if _, err := ParseStructPattern(`log.Printf(`); err == nil {
+		t.Errorf("invalid pattern should have been an error\n")
+	}
+}
+
+func TestStructRewrite(t *testing.T) {
+	tests := []struct {
+		pat  string
+		tmpl string
+		orig string
+		res  string
+	}{
+		{`log.Printf($fmt, $*args)`, `log.Println(fmt.Sprintf($fmt, $*args))`, `log.Printf("a: %v\n", x.Y)`, `log.Println(fmt.Sprintf("a: %v\n", x.Y))`},
+		{`log.Printf($fmt)`, `log.Println($fmt)`, `log.Printf("a", b)`, `log.Printf("a", b)`},
+		{`if $err != nil { return $err }`, `if $err != nil { return errors.Wrap($err) }`, "if err != nil {\n\t\treturn err\n\t}", `if err != nil { return errors.Wrap(err) }`},
+	}
+	for _, tst := range tests {
+		res := StructReplaceText(tst.orig, tst.pat, tst.tmpl)
+		if res != tst.res {
+			t.Errorf("pattern: %v should have been: %v  was: %v\n", tst.pat, tst.res, res)
+		}
+	}
+}
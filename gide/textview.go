@@ -4,6 +4,7 @@ import (
 	"image"
 
 	"github.com/goki/gi/gi"
+	"github.com/goki/gi/girl"
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/giv"
 	"github.com/goki/gi/oswin"
@@ -17,9 +18,14 @@ import (
 )
 
 // TextView is the Gide-specific version of the TextView, with support for
-// setting / clearing breakpoints, etc
+// setting / clearing breakpoints, multiple cursors, code folding, etc
 type TextView struct {
 	giv.TextView
+	Cursors      []TextCursor  `json:"-" xml:"-" desc:"additional cursors for multi-cursor editing -- the main cursor is CursorPos, with SelectReg"`
+	Folds        []Fold        `json:"-" xml:"-" desc:"regions that can be folded, computed as needed from the parsed structure or indentation of the text"`
+	Folded       []Fold        `json:"-" xml:"-" desc:"regions that are currently folded, hiding all but their first line"`
+	FoldCursorLn int           `json:"-" xml:"-" desc:"line of the cursor when last moved, to determine which way to skip over folded regions"`
+	Snippet      *SnippetState `json:"-" xml:"-" desc:"snippet whose fields are being filled in, if any"`
 }
 
 var KiT_TextView = kit.Types.AddType(&TextView{}, giv.TextViewProps)
@@ -181,6 +187,52 @@ func (tv *TextView) DebugVarValueAtPos(pos image.Point) string {
 	return ""
 }
 
+// Render2D renders the text with any folded regions hidden, followed by
+// the fold markers, bookmarks, any additional cursors and the inline values of
+// variables shown while stopped in the debugger
+func (tv *TextView) Render2D() {
+	tv.LayoutFolds()
+	tv.TextView.Render2D()
+	if tv.LayoutFolds() {
+		tv.RenderAllLines()
+	}
+	tv.RenderFolds()
+	tv.RenderBookmarks()
+	tv.RenderCursors()
+	tv.RenderDebugVals()
+}
+
+// RenderDebugVals renders the inline values of variables from the
+// debugger, after the end of the lines where they were assigned
+func (tv *TextView) RenderDebugVals() {
+	dbg, has := tv.CurDebug()
+	if !has || !tv.IsVisible() {
+		return
+	}
+	vals := dbg.InlineVals[string(tv.Buf.Filename)]
+	if len(vals) == 0 {
+		return
+	}
+	rs := tv.Render()
+	rs.PushBounds(tv.VpBBox)
+	rs.Lock()
+	fnt := tv.Sty.Font
+	fnt.Color.SetString("grey", nil)
+	spos := tv.RenderStartPos()
+	var tr girl.Text
+	for ln, txt := range vals {
+		if ln >= tv.NLines || ln >= len(tv.Offs) {
+			continue
+		}
+		pos := tv.CharStartPos(lex.Pos{Ln: ln, Ch: len(tv.Buf.Line(ln))})
+		pos.Y = spos.Y + tv.Offs[ln]
+		tr.SetString("    "+txt, &fnt, &tv.Sty.UnContext, &tv.Sty.Text, true, 0, 1)
+		tr.Render(rs, pos)
+	}
+	rs.Unlock()
+	rs.PopBounds()
+}
+
 // FindFrames finds stack frames in the debugger containing this file and line
 func (tv *TextView) FindFrames(ln int) {
 	dbg, has := tv.CurDebug()
@@ -213,6 +265,17 @@ func (tv *TextView) DoubleClickEvent(tpos lex.Pos) {
 
 // MouseEvent handles the mouse.Event
 func (tv *TextView) MouseEvent(me *mouse.Event) {
+	if me.Action == mouse.Press {
+		tv.ClearCursors()
+		tv.EndSnippet()
+		if me.Button == mouse.Left && tv.Buf != nil {
+			if ln, ok := tv.FoldMarkAt(tv.PointToRelPos(me.Pos())); ok {
+				me.SetProcessed()
+				tv.ToggleFold(ln)
+				return
+			}
+		}
+	}
 	if me.Button != mouse.Left || me.Action != mouse.DoubleClick {
 		tv.TextView.MouseEvent(me)
 		return
@@ -264,9 +327,23 @@ func (tv *TextView) TextViewEvents() {
 		txf.MouseEvent(me) // gets our new one
 	})
 	tv.MouseFocusEvent()
+	tv.TextViewSig.Connect(tv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if giv.TextViewSignals(sig) == giv.TextViewCursorMoved {
+			txf := recv.Embed(KiT_TextView).(*TextView)
+			txf.FoldsCursorMoved()
+		}
+	})
 	tv.ConnectEvent(oswin.KeyChordEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
 		txf := recv.Embed(KiT_TextView).(*TextView)
 		kt := d.(*key.ChordEvent)
+		txf.SnippetKeyInput(kt)
+		if kt.IsProcessed() {
+			return
+		}
+		txf.CursorsKeyInput(kt)
+		if kt.IsProcessed() {
+			return
+		}
 		txf.KeyInput(kt)
 	})
 }
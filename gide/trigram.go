@@ -0,0 +1,358 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+)
+
+// Trigram is three consecutive bytes of (lowercased) file content, packed
+// into the low 24 bits
+type Trigram uint32
+
+// Trigrams returns the sorted, unique trigrams in given text, which is
+// lowercased first so that the same index serves case-insensitive searches
+func Trigrams(txt []byte) []Trigram {
+	txt = bytes.ToLower(txt)
+	sz := len(txt)
+	if sz < 3 {
+		return nil
+	}
+	set := make(map[Trigram]struct{}, sz/4)
+	for i := 0; i+3 <= sz; i++ {
+		set[Trigram(txt[i])<<16|Trigram(txt[i+1])<<8|Trigram(txt[i+2])] = struct{}{}
+	}
+	tris := make([]Trigram, 0, len(set))
+	for t := range set {
+		tris = append(tris, t)
+	}
+	sort.Slice(tris, func(i, j int) bool { return tris[i] < tris[j] })
+	return tris
+}
+
+// TrigramFile is the index entry for one file
+type TrigramFile struct {
+	ModTime time.Time `desc:"modification time of file when indexed"`
+	Size    int64     `desc:"size of file when indexed"`
+	Tris    []Trigram `desc:"sorted unique trigrams in the file"`
+}
+
+// IsFresh returns true if the entry is up-to-date with given file info
+func (tf *TrigramFile) IsFresh(info os.FileInfo) bool {
+	return tf.Size == info.Size() && tf.ModTime.Equal(info.ModTime())
+}
+
+// HasAll returns true if the file has all of the given (sorted) trigrams
+func (tf *TrigramFile) HasAll(tris []Trigram) bool {
+	rest := tf.Tris
+	for _, t := range tris {
+		i := sort.Search(len(rest), func(i int) bool { return rest[i] >= t })
+		if i >= len(rest) || rest[i] != t {
+			return false
+		}
+		rest = rest[i+1:]
+	}
+	return true
+}
+
+// TrigramQuery is the set of trigrams that any file matching a search must
+// contain -- if Tris is empty, the query cannot be narrowed by the index.
+type TrigramQuery struct {
+	Tris []Trigram `desc:"sorted unique trigrams required for a match"`
+}
+
+// NewTrigramQuery returns the query for given find string.  For a regexp,
+// only the literal strings that every match must contain are used, so the
+// query never excludes a file that could match.
+func NewTrigramQuery(find string, isRegexp bool) *TrigramQuery {
+	q := &TrigramQuery{}
+	var lits []string
+	if isRegexp {
+		re, err := syntax.Parse(find, syntax.Perl)
+		if err != nil {
+			return q
+		}
+		lits = RegexpLiterals(re.Simplify())
+	} else {
+		lits = []string{find}
+	}
+	set := make(map[Trigram]struct{})
+	for _, l := range lits {
+		for _, t := range Trigrams([]byte(l)) {
+			set[t] = struct{}{}
+		}
+	}
+	for t := range set {
+		q.Tris = append(q.Tris, t)
+	}
+	sort.Slice(q.Tris, func(i, j int) bool { return q.Tris[i] < q.Tris[j] })
+	return q
+}
+
+// IsEmpty returns true if the query does not narrow the search
+func (q *TrigramQuery) IsEmpty() bool {
+	return q == nil || len(q.Tris) == 0
+}
+
+// RegexpLiterals returns literal strings that must appear in any text matched
+// by given parsed regexp -- conservatively returns nil when unsure
+func RegexpLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return RegexpLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min >= 1 {
+			return RegexpLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var lits []string
+		var cur strings.Builder
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpLiteral {
+				cur.WriteString(string(sub.Rune))
+				continue
+			}
+			if cur.Len() > 0 {
+				lits = append(lits, cur.String())
+				cur.Reset()
+			}
+			lits = append(lits, RegexpLiterals(sub)...)
+		}
+		if cur.Len() > 0 {
+			lits = append(lits, cur.String())
+		}
+		return lits
+	}
+	return nil
+}
+
+// TrigramMaxFileSize is the largest file that is indexed -- larger files are
+// always searched
+var TrigramMaxFileSize int64 = 4 << 20
+
+// TrigramIndexVersion is the version of the saved index format -- saved
+// indexes with a different version are discarded
+const TrigramIndexVersion = 1
+
+// TrigramIndex is a persistent index of the trigrams in each file of a
+// project, used to skip files that cannot match a search without reading
+// them.  Files are added as they are searched, entries are checked against
+// the file modification time and size before use, and the whole index is
+// refreshed in the background when the file tree reports changes.
+type TrigramIndex struct {
+	Version int                     `desc:"version of index format"`
+	Root    string                  `desc:"root path of project -- files are stored relative to this"`
+	Files   map[string]*TrigramFile `desc:"index entries, keyed by path relative to Root"`
+	FName   string                  `desc:"file name where index is saved"`
+	Changed bool                    `desc:"index has changed since last saved"`
+	mu      sync.RWMutex
+	refresh *time.Timer
+}
+
+// trigramIndexData is the part of the index that is saved
+type trigramIndexData struct {
+	Version int
+	Root    string
+	Files   map[string]*TrigramFile
+}
+
+// NewTrigramIndex returns a new empty index for given root, saved in fname
+func NewTrigramIndex(root, fname string) *TrigramIndex {
+	return &TrigramIndex{Version: TrigramIndexVersion, Root: filepath.Clean(root), Files: make(map[string]*TrigramFile), FName: fname}
+}
+
+// RelPath returns the path of the file relative to root, used as the key
+func (ix *TrigramIndex) RelPath(fpath string) string {
+	rp, err := filepath.Rel(ix.Root, fpath)
+	if err != nil {
+		return fpath
+	}
+	return rp
+}
+
+// Open loads the index from its file
+func (ix *TrigramIndex) Open() error {
+	fp, err := os.Open(ix.FName)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	nix := &trigramIndexData{}
+	err = gob.NewDecoder(fp).Decode(nix)
+	if err != nil {
+		return err
+	}
+	if nix.Version != TrigramIndexVersion || nix.Root != ix.Root {
+		return fmt.Errorf("gide.TrigramIndex: index file %v is out of date -- will be rebuilt", ix.FName)
+	}
+	ix.mu.Lock()
+	ix.Files = nix.Files
+	if ix.Files == nil {
+		ix.Files = make(map[string]*TrigramFile)
+	}
+	ix.Changed = false
+	ix.mu.Unlock()
+	return nil
+}
+
+// Save saves the index to its file, if it has changed
+func (ix *TrigramIndex) Save() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if !ix.Changed {
+		return nil
+	}
+	os.MkdirAll(filepath.Dir(ix.FName), 0755)
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(&trigramIndexData{Version: ix.Version, Root: ix.Root, Files: ix.Files})
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(ix.FName, b.Bytes(), 0644)
+	if err == nil {
+		ix.Changed = false
+	}
+	return err
+}
+
+// Entry returns the index entry for given file if it is up-to-date with
+// given file info, else nil
+func (ix *TrigramIndex) Entry(fpath string, info os.FileInfo) *TrigramFile {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	tf, has := ix.Files[ix.RelPath(fpath)]
+	if !has || !tf.IsFresh(info) {
+		return nil
+	}
+	return tf
+}
+
+// UpdateFile reads and indexes given file, returning the new entry,
+// or nil if the file could not be read or is too large
+func (ix *TrigramIndex) UpdateFile(fpath string) *TrigramFile {
+	info, err := os.Stat(fpath)
+	if err != nil || info.IsDir() || info.Size() > TrigramMaxFileSize {
+		ix.RemoveFile(fpath)
+		return nil
+	}
+	txt, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		ix.RemoveFile(fpath)
+		return nil
+	}
+	tf := &TrigramFile{ModTime: info.ModTime(), Size: info.Size(), Tris: Trigrams(txt)}
+	ix.mu.Lock()
+	ix.Files[ix.RelPath(fpath)] = tf
+	ix.Changed = true
+	ix.mu.Unlock()
+	return tf
+}
+
+// RemoveFile removes given file from the index
+func (ix *TrigramIndex) RemoveFile(fpath string) {
+	rp := ix.RelPath(fpath)
+	ix.mu.Lock()
+	if _, has := ix.Files[rp]; has {
+		delete(ix.Files, rp)
+		ix.Changed = true
+	}
+	ix.mu.Unlock()
+}
+
+// Refresh re-indexes all files in the index that have changed on disk, and
+// removes those that no longer exist, then saves the index
+func (ix *TrigramIndex) Refresh() {
+	ix.mu.RLock()
+	var stale []string
+	for rp, tf := range ix.Files {
+		fpath := filepath.Join(ix.Root, rp)
+		info, err := os.Stat(fpath)
+		if err != nil || !tf.IsFresh(info) {
+			stale = append(stale, fpath)
+		}
+	}
+	ix.mu.RUnlock()
+	for _, fpath := range stale {
+		ix.UpdateFile(fpath)
+	}
+	if err := ix.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// TrigramRefreshDelay is how long to wait after the last file change
+// before refreshing the index
+var TrigramRefreshDelay = 2 * time.Second
+
+// FilesChanged should be called whenever files in the project have changed
+// -- it schedules a Refresh after TrigramRefreshDelay, restarting the delay
+// on each call, so a burst of changes results in only one refresh.
+func (ix *TrigramIndex) FilesChanged() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.refresh != nil {
+		ix.refresh.Stop()
+	}
+	ix.refresh = time.AfterFunc(TrigramRefreshDelay, ix.Refresh)
+}
+
+// TrigramIndexes are the open indexes, keyed by project root path
+var TrigramIndexes = map[string]*TrigramIndex{}
+
+// TrigramIndexesMu protects TrigramIndexes
+var TrigramIndexesMu sync.Mutex
+
+// TrigramIndexForRoot returns the open index for given project root path,
+// or nil if none has been opened
+func TrigramIndexForRoot(root string) *TrigramIndex {
+	TrigramIndexesMu.Lock()
+	defer TrigramIndexesMu.Unlock()
+	return TrigramIndexes[filepath.Clean(root)]
+}
+
+// OpenTrigramIndex returns the index for given project root, opening it from
+// given file if not already open -- it is created if the file does not exist
+func OpenTrigramIndex(root, fname string) *TrigramIndex {
+	root = filepath.Clean(root)
+	TrigramIndexesMu.Lock()
+	defer TrigramIndexesMu.Unlock()
+	if ix, has := TrigramIndexes[root]; has {
+		return ix
+	}
+	ix := NewTrigramIndex(root, fname)
+	if err := ix.Open(); err != nil && !os.IsNotExist(err) {
+		log.Println(err)
+	}
+	TrigramIndexes[root] = ix
+	return ix
+}
+
+// TrigramIndexFileName returns the file name for the index of a project with
+// given root path and project (.gide) file name: it is saved next to the
+// project file with a .gidx extension, or in the app prefs dir if there is
+// no project file.
+func TrigramIndexFileName(root, projFile string) string {
+	if projFile != "" {
+		return strings.TrimSuffix(projFile, filepath.Ext(projFile)) + ".gidx"
+	}
+	pdir := oswin.TheApp.AppPrefsDir()
+	return filepath.Join(pdir, "index", fmt.Sprintf("%x.gidx", sha1.Sum([]byte(filepath.Clean(root)))))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gide
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrigramQuery(t *testing.T) {
+	tf := &TrigramFile{Tris: Trigrams([]byte("func FileTreeSearch(start *giv.FileNode) {\n\treturn nil\n}\n"))}
+	tests := []struct {
+		find  string
+		re    bool
+		match bool
+	}{
+		{"FileTreeSearch", false, true},
+		{"filetreesearch", false, true},
+		{"FileTreeFind", false, false},
+		{"nil\n}", false, true},
+		{`File(Tree|Node)Search`, true, true},
+		{`Tree\w+Search`, true, true},
+		{`start \*giv`, true, true},
+		{`(foo)+bar`, true, false},
+		{`x*Search`, true, true},
+		{`foo|bar`, true, true}, // alternates are not narrowed
+		{"ab", false, true},     // too short to narrow
+	}
+	for _, tst := range tests {
+		q := NewTrigramQuery(tst.find, tst.re)
+		match := q.IsEmpty() || tf.HasAll(q.Tris)
+		if match != tst.match {
+			t.Errorf("find: %q match should have been: %v  was: %v\n", tst.find, tst.match, match)
+		}
+	}
+}
+
+func TestTrigramIndex(t *testing.T) {
+	root := genSearchTree(t, 2, 3, 1)
+	defer os.RemoveAll(root)
+	fname := filepath.Join(root, "proj.gidx")
+	ix := NewTrigramIndex(root, fname)
+	fpath := filepath.Join(root, "dir0", "file0.go")
+	tf := ix.UpdateFile(fpath)
+	if tf == nil {
+		t.Fatalf("file: %v was not indexed\n", fpath)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	nix := NewTrigramIndex(root, fname)
+	if err := nix.Open(); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(fpath)
+	if nix.Entry(fpath, info) == nil {
+		t.Errorf("file: %v should have been in opened index\n", fpath)
+	}
+	ioutil.WriteFile(fpath, []byte("package gen // changed\n"), 0644)
+	info, _ = os.Stat(fpath)
+	if nix.Entry(fpath, info) != nil {
+		t.Errorf("changed file: %v should not have a fresh entry\n", fpath)
+	}
+	os.Remove(fpath)
+	nix.Refresh()
+	if len(nix.Files) != 0 {
+		t.Errorf("removed file should have been removed from index, has: %v files\n", len(nix.Files))
+	}
+}
+
+func TestFileSearchIndex(t *testing.T) {
+	root := genSearchTree(t, 2, 3, 1)
+	defer os.RemoveAll(root)
+	ft := openSearchTree(t, root, true)
+	ix := OpenTrigramIndex(string(ft.FPath), filepath.Join(root, "proj.gidx"))
+	defer delete(TrigramIndexes, ix.Root)
+
+	fp := &FindParams{Find: "needle", Index: true}
+	if res := FileTreeSearch(&ft.FileNode, fp, ""); len(res) != 6 {
+		t.Errorf("number of files should have been: %v  was: %v\n", 6, len(res))
+	}
+	fp.Find = "nothere"
+	fs, _ := NewFileSearch(&ft.FileNode, fp, "")
+	for range fs.Run() {
+	}
+	if fs.Stats.Skipped != 6 || fs.Stats.Files != 0 {
+		t.Errorf("all files should have been skipped by index, stats: %v\n", fs.Stats.String())
+	}
+}
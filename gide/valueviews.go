@@ -762,6 +762,85 @@ func RegistersView(pt *Registers) {
 	win.GoStartEventLoop()
 }
 
+//////////////////////////////////////////////////////////////////////////////////////
+//  SnippetsView
+
+// SnippetsView opens a view of the snippets for each language
+func SnippetsView(pt *Snippets) {
+	winm := "gide-snippets"
+	width := 800
+	height := 800
+	win, recyc := gi.RecycleMainWindow(pt, winm, "Gide Snippets", width, height)
+	if recyc {
+		return
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	mfr.Lay = gi.LayoutVert
+
+	title := mfr.AddNewChild(gi.KiT_Label, "title").(*gi.Label)
+	title.SetText("Available Snippets: for each language, by name (e.g., Go, Python, or Any for all) -- in the body, $1, ${1:default} and ${1|choice1,choice2|} are fields to tab through, $0 is the final cursor position, and {ArgVar} variables are replaced with their values")
+	title.SetProp("width", units.NewValue(30, units.Ch)) // need for wrap
+	title.SetStretchMaxWidth()
+	title.SetProp("white-space", gist.WhiteSpaceNormal) // wrap
+
+	tv := mfr.AddNewChild(giv.KiT_MapView, "tv").(*giv.MapView)
+	tv.Viewport = vp
+	tv.SetMap(pt)
+	tv.SetStretchMaxWidth()
+	tv.SetStretchMaxHeight()
+
+	AvailSnippetsChanged = false
+	tv.ViewSig.Connect(mfr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		AvailSnippetsChanged = true
+	})
+
+	mmen := win.MainMenu
+	giv.MainMenuView(pt, win, mmen)
+
+	inClosePrompt := false
+	win.OSWin.SetCloseReqFunc(func(w oswin.Window) {
+		if !AvailSnippetsChanged || pt != &AvailSnippets { // only for main avail map..
+			win.Close()
+			return
+		}
+		if inClosePrompt {
+			return
+		}
+		inClosePrompt = true
+		gi.ChoiceDialog(vp, gi.DlgOpts{Title: "Save Snippets Before Closing?",
+			Prompt: "Do you want to save any changes to custom snippets file before closing, or Cancel the close and do a Save to a different file?"},
+			[]string{"Save and Close", "Discard and Close", "Cancel"},
+			win.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+				switch sig {
+				case 0:
+					pt.SavePrefs()
+					fmt.Printf("Preferences Saved to %v\n", PrefsSnippetsFileName)
+					win.Close()
+				case 1:
+					pt.OpenPrefs() // revert
+					win.Close()
+				case 2:
+					inClosePrompt = false
+					// default is to do nothing, i.e., cancel
+				}
+			})
+	})
+
+	win.MainMenuUpdated()
+
+	if !win.HasGeomPrefs() { // resize to contents
+		vpsz := vp.PrefSize(win.OSWin.Screen().PixSize)
+		win.SetSize(vpsz)
+	}
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////
 //  RegisterValueView
 
@@ -0,0 +1,389 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goki/gi/giv"
+)
+
+// StateDump is a snapshot of the state of a debugged process: its threads,
+// tasks and their stacks, and the local variables of the current frame.
+// It can be saved from a debugging session, or read from a Go panic or
+// SIGQUIT goroutine dump, and browsed later, read-only, in Dump mode.
+type StateDump struct {
+	Time      time.Time        `desc:"time when the dump was made -- zero if unknown"`
+	Exe       string           `desc:"path to the executable that was being debugged, if known"`
+	Reason    string           `desc:"why the dump was made, e.g., the panic message"`
+	CurThread int              `desc:"id of the current system thread"`
+	CurTask   int              `desc:"id of the current task, e.g., the goroutine that panicked"`
+	CurFrame  int              `desc:"frame number within the current thread or task that Vars are from"`
+	Threads   []*Thread        `desc:"all system threads"`
+	Tasks     []*Task          `desc:"all tasks"`
+	Stacks    map[int][]*Frame `desc:"stacks of the tasks (or of the threads, if there are no tasks), by id"`
+	Vars      []*DumpVar       `desc:"local variables and args of the current frame"`
+}
+
+// DumpVar is a variable in a StateDump, with its value as a string
+type DumpVar struct {
+	Name  string `desc:"name of the variable"`
+	Type  string `desc:"type of the variable"`
+	Value string `desc:"value of the variable"`
+	Line  int    `desc:"line where the variable was declared"`
+}
+
+// NewStateDump returns a StateDump of given state, with the stack of the current
+// task or thread only -- others can be added to Stacks
+func NewStateDump(as *AllState) *StateDump {
+	dp := &StateDump{Time: time.Now(), CurThread: as.CurThread, CurTask: as.CurTask, CurFrame: as.CurFrame}
+	dp.Threads = as.Threads
+	dp.Tasks = as.Tasks
+	dp.Stacks = make(map[int][]*Frame)
+	cid := as.CurThread
+	if len(as.Tasks) > 0 {
+		cid = as.CurTask
+	}
+	dp.SetStack(cid, as.Stack)
+	if cf := as.StackFrame(as.CurFrame); cf != nil {
+		dp.Reason = fmt.Sprintf("stopped at %s:%d in %s", cf.File, cf.Line, cf.Func)
+	}
+	for _, vr := range as.Vars {
+		if vr == nil || vr.Nm == "" {
+			continue
+		}
+		val := vr.Value
+		if val == "" {
+			val = vr.ValueString(false, 0, 4, 200, false)
+		}
+		dp.Vars = append(dp.Vars, &DumpVar{Name: vr.Nm, Type: vr.TypeStr, Value: val, Line: vr.Loc.Line})
+	}
+	return dp
+}
+
+// SetStack sets the stack of given task or thread id, without the
+// variables of the frames, which are not saved
+func (dp *StateDump) SetStack(id int, stack []*Frame) {
+	st := make([]*Frame, 0, len(stack))
+	for _, fr := range stack {
+		if fr == nil {
+			continue
+		}
+		nf := *fr
+		nf.Vars = nil
+		nf.Args = nil
+		st = append(st, &nf)
+	}
+	dp.Stacks[id] = st
+}
+
+// Stack returns the stack of given task or thread id
+func (dp *StateDump) Stack(id int) []*Frame {
+	return dp.Stacks[id]
+}
+
+// SetPaths maps the full paths of all locations in the dump to local ones
+// using given path mapping rules, and sets the file names relative to
+// given project root path.  If a full path does not exist locally, but the
+// file name relative to the root does, that is used instead, so dumps can
+// be shared across machines.
+func (dp *StateDump) SetPaths(pm PathMaps, rootPath string) {
+	fix := func(fpath, file *string) {
+		if *fpath == "" {
+			return
+		}
+		fp := pm.ToLocal(*fpath)
+		if _, err := os.Stat(fp); err != nil && *file != "" && !filepath.IsAbs(*file) {
+			rp := filepath.Join(rootPath, *file)
+			if _, err := os.Stat(rp); err == nil {
+				fp = rp
+			}
+		}
+		*fpath = fp
+		*file = giv.RelFilePath(fp, rootPath)
+	}
+	for _, th := range dp.Threads {
+		fix(&th.FPath, &th.File)
+	}
+	for _, tk := range dp.Tasks {
+		fix(&tk.FPath, &tk.File)
+		fix(&tk.StartLoc.FPath, &tk.StartLoc.File)
+		fix(&tk.LaunchLoc.FPath, &tk.LaunchLoc.File)
+	}
+	for _, st := range dp.Stacks {
+		for _, fr := range st {
+			fix(&fr.FPath, &fr.File)
+		}
+	}
+}
+
+// Save saves the dump to given file, as JSON if it has a .json extension,
+// and otherwise as text in the format of a Go goroutine dump
+func (dp *StateDump) Save(fname string) error {
+	var b []byte
+	if strings.ToLower(filepath.Ext(fname)) == ".json" {
+		var err error
+		b, err = json.MarshalIndent(dp, "", "  ")
+		if err != nil {
+			return err
+		}
+	} else {
+		b = dp.Text()
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// Text returns the dump as text, in the format of a Go goroutine dump,
+// which can be read back with ParseGoDump (without the variables, for
+// which JSON must be used), followed by the locals of the current frame
+func (dp *StateDump) Text() []byte {
+	var b bytes.Buffer
+	if dp.Exe != "" {
+		fmt.Fprintf(&b, "dump of: %s\n", dp.Exe)
+	}
+	if !dp.Time.IsZero() {
+		fmt.Fprintf(&b, "time: %s\n", dp.Time.Format(TraceTimeFormat))
+	}
+	if dp.Reason != "" {
+		fmt.Fprintf(&b, "%s\n", dp.Reason)
+	}
+	writeStack := func(st []*Frame) {
+		for _, fr := range st {
+			fmt.Fprintf(&b, "%s(...)\n\t%s:%d", fr.Func, fr.FPath, fr.Line)
+			if fr.PC != 0 {
+				fmt.Fprintf(&b, " pc=%#x", fr.PC)
+			}
+			b.WriteString("\n")
+		}
+	}
+	for _, tk := range dp.Tasks {
+		stat := tk.Blocked
+		switch {
+		case tk.ID == dp.CurTask:
+			stat = "running"
+		case stat == "":
+			stat = "waiting"
+		}
+		fmt.Fprintf(&b, "\ngoroutine %d [%s]:\n", tk.ID, stat)
+		writeStack(dp.Stacks[tk.ID])
+		if tk.LaunchLoc.Func != "" {
+			fmt.Fprintf(&b, "created by %s\n\t%s:%d\n", tk.LaunchLoc.Func, tk.LaunchLoc.FPath, tk.LaunchLoc.Line)
+		}
+	}
+	for _, th := range dp.Threads {
+		fmt.Fprintf(&b, "\nthread %#x [", th.ID)
+		if th.Task > 0 {
+			fmt.Fprintf(&b, "goroutine %d", th.Task)
+		}
+		b.WriteString("]:\n")
+		if len(dp.Tasks) > 0 {
+			writeStack([]*Frame{{Func: th.Func, FPath: th.FPath, Line: th.Line, PC: th.PC}})
+		} else {
+			writeStack(dp.Stacks[th.ID])
+		}
+	}
+	if len(dp.Vars) > 0 {
+		fmt.Fprintf(&b, "\nlocals of frame %d:\n", dp.CurFrame)
+		for _, vr := range dp.Vars {
+			fmt.Fprintf(&b, "\t%s %s = %s\n", vr.Name, vr.Type, vr.Value)
+		}
+	}
+	return b.Bytes()
+}
+
+// OpenDump reads a dump from given file: JSON as saved by Save, or else a
+// Go panic or SIGQUIT goroutine dump, or the text saved by Save
+func OpenDump(fname string) (*StateDump, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	if tb := bytes.TrimSpace(b); len(tb) > 0 && tb[0] == '{' {
+		dp := &StateDump{}
+		if err := json.Unmarshal(b, dp); err != nil {
+			return nil, err
+		}
+		if dp.Stacks == nil {
+			dp.Stacks = make(map[int][]*Frame)
+		}
+		return dp, nil
+	}
+	dp := ParseGoDump(b)
+	if len(dp.Tasks) == 0 && len(dp.Threads) == 0 {
+		return nil, fmt.Errorf("no goroutines found in dump file: %s", fname)
+	}
+	return dp, nil
+}
+
+var (
+	dumpHeadRe = regexp.MustCompile(`^(goroutine|thread) (\w+)[^\[]*\[([^\]]*)\]:$`)
+	dumpFileRe = regexp.MustCompile(`^\t(.+):(\d+)(?:\s|$)`)
+	dumpPCRe   = regexp.MustCompile(`\bpc=(0x[0-9a-fA-F]+)`)
+)
+
+// DumpNotBlocked are the goroutine states in a Go goroutine dump that do
+// not indicate that it is blocked
+var DumpNotBlocked = map[string]bool{
+	"running":  true,
+	"runnable": true,
+	"syscall":  true,
+	"idle":     true,
+	"dead":     true,
+	"waiting":  true,
+}
+
+// ParseGoDump parses a Go panic or SIGQUIT goroutine dump (as also saved by
+// StateDump.Text), returning the goroutines as tasks with their stacks.  The
+// text before the first goroutine, e.g., the panic message, is the Reason,
+// and the first goroutine, e.g., the one that panicked, is the current one.
+func ParseGoDump(b []byte) *StateDump {
+	dp := &StateDump{Stacks: make(map[int][]*Frame)}
+	thrStacks := make(map[int][]*Frame)
+	var reason []string
+	var tk *Task
+	var th *Thread
+	var stack *[]*Frame
+	fun := ""
+	created := false
+	started := false
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		ln := strings.TrimRight(sc.Text(), "\r")
+		if hd := dumpHeadRe.FindStringSubmatch(ln); hd != nil {
+			started = true
+			id, _ := strconv.ParseInt(hd[2], 0, 64)
+			stat := strings.TrimSpace(strings.Split(hd[3], ",")[0])
+			if hd[1] == "goroutine" {
+				tk = &Task{ID: int(id)}
+				if !DumpNotBlocked[stat] {
+					tk.Blocked = stat
+				}
+				dp.Tasks = append(dp.Tasks, tk)
+				st := []*Frame{}
+				dp.Stacks[tk.ID] = st
+				stack = &st
+				th = nil
+			} else {
+				th = &Thread{ID: int(id)}
+				if strings.HasPrefix(stat, "goroutine ") {
+					tid, _ := strconv.Atoi(strings.TrimPrefix(stat, "goroutine "))
+					th.Task = tid
+				}
+				dp.Threads = append(dp.Threads, th)
+				st := []*Frame{}
+				stack = &st
+				tk = nil
+			}
+			fun = ""
+			created = false
+			continue
+		}
+		if strings.TrimSpace(ln) == "" {
+			if tk != nil {
+				dp.Stacks[tk.ID] = *stack
+			} else if th != nil {
+				thrStacks[th.ID] = *stack
+			}
+			tk, th, stack = nil, nil, nil
+			continue
+		}
+		if !started {
+			reason = append(reason, ln)
+			continue
+		}
+		if stack == nil {
+			continue
+		}
+		if !strings.HasPrefix(ln, "\t") {
+			fun = ln
+			created = strings.HasPrefix(ln, "created by ")
+			continue
+		}
+		fm := dumpFileRe.FindStringSubmatch(ln)
+		if fm == nil || fun == "" {
+			continue
+		}
+		line, _ := strconv.Atoi(fm[2])
+		loc := Location{File: fm[1], FPath: fm[1], Line: line}
+		if pm := dumpPCRe.FindStringSubmatch(ln); pm != nil {
+			loc.PC, _ = strconv.ParseUint(pm[1], 0, 64)
+		}
+		if created {
+			loc.Func = strings.TrimPrefix(fun, "created by ")
+			if in := strings.Index(loc.Func, " in goroutine "); in > 0 {
+				loc.Func = loc.Func[:in]
+			}
+			if tk != nil {
+				tk.LaunchLoc = loc
+			}
+		} else {
+			loc.Func = dumpFuncName(fun)
+			fr := &Frame{Depth: len(*stack), PC: loc.PC, File: loc.File, Line: loc.Line, FPath: loc.FPath, Func: loc.Func}
+			*stack = append(*stack, fr)
+			switch {
+			case tk != nil:
+				fr.ThreadID = tk.ID
+				if fr.Depth == 0 {
+					tk.PC, tk.File, tk.Line, tk.FPath, tk.Func = loc.PC, loc.File, loc.Line, loc.FPath, loc.Func
+				}
+				tk.StartLoc = loc
+			case th != nil:
+				fr.ThreadID = th.ID
+				if fr.Depth == 0 {
+					th.PC, th.File, th.Line, th.FPath, th.Func = loc.PC, loc.File, loc.Line, loc.FPath, loc.Func
+				}
+			}
+		}
+		fun = ""
+	}
+	if tk != nil {
+		dp.Stacks[tk.ID] = *stack
+	} else if th != nil {
+		thrStacks[th.ID] = *stack
+	}
+	if len(dp.Tasks) == 0 {
+		dp.Stacks = thrStacks
+		if len(dp.Threads) > 0 {
+			dp.CurThread = dp.Threads[0].ID
+		}
+	} else {
+		dp.CurTask = dp.Tasks[0].ID
+	}
+	dp.Reason = strings.TrimSpace(strings.Join(reason, "\n"))
+	return dp
+}
+
+// dumpFuncName returns the function name from a function line in a Go
+// goroutine dump, without the args, e.g., main.(*T).f from main.(*T).f(0x1, ...)
+func dumpFuncName(ln string) string {
+	ln = strings.TrimSpace(ln)
+	if !strings.HasSuffix(ln, ")") {
+		return ln
+	}
+	depth := 0
+	for i := len(ln) - 1; i >= 0; i-- {
+		switch ln[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return ln[:i]
+			}
+		}
+	}
+	return ln
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import "testing"
+
+var testGoDump = `panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.(*Server).handle(0xc000010000, {0x4b6f00, 0x5})
+	/go/src/app/server.go:42 +0x1d
+main.main()
+	/go/src/app/main.go:10 +0x25
+
+goroutine 18 [chan receive, 2 minutes]:
+main.worker(...)
+	/go/src/app/worker.go:20
+created by main.main in goroutine 1
+	/go/src/app/main.go:8 +0x60
+exit status 2
+`
+
+func TestParseGoDump(t *testing.T) {
+	dp := ParseGoDump([]byte(testGoDump))
+	if dp.Reason != "panic: runtime error: index out of range [5] with length 3" {
+		t.Errorf("Reason was: %v", dp.Reason)
+	}
+	if len(dp.Tasks) != 2 || dp.CurTask != 1 {
+		t.Fatalf("should have 2 tasks with current 1, was: %d, %d", len(dp.Tasks), dp.CurTask)
+	}
+	st := dp.Stack(1)
+	if len(st) != 2 || st[0].Func != "main.(*Server).handle" || st[0].FPath != "/go/src/app/server.go" || st[0].Line != 42 || st[1].Depth != 1 {
+		t.Errorf("stack of goroutine 1 is wrong: %v %v", st[0], st[1])
+	}
+	tk := dp.Tasks[1]
+	if tk.ID != 18 || tk.Blocked != "chan receive" || tk.Func != "main.worker" || tk.Line != 20 {
+		t.Errorf("goroutine 18 is wrong: %v", tk)
+	}
+	if tk.LaunchLoc.Func != "main.main" || tk.LaunchLoc.Line != 8 {
+		t.Errorf("goroutine 18 launch location is wrong: %v", tk.LaunchLoc)
+	}
+	if len(dp.Stack(18)) != 1 {
+		t.Errorf("goroutine 18 should have 1 frame, has: %d", len(dp.Stack(18)))
+	}
+
+	rd := ParseGoDump(dp.Text())
+	if len(rd.Tasks) != 2 || rd.CurTask != 1 || len(rd.Stack(1)) != 2 {
+		t.Fatalf("text dump did not read back: %s", dp.Text())
+	}
+	if rt := rd.Tasks[1]; rt.Blocked != tk.Blocked || rt.LaunchLoc.Func != tk.LaunchLoc.Func || rt.FPath != tk.FPath {
+		t.Errorf("goroutine 18 did not read back: %v", rt)
+	}
+}
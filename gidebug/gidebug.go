@@ -9,12 +9,16 @@ import (
 	"time"
 
 	"github.com/goki/gi/giv"
+	"github.com/goki/ki/kit"
 )
 
 var (
 	NotStartedErr = errors.New("debugger not started")
 
 	IsRunningErr = errors.New("debugger is currently running and cannot return info")
+
+	// NotSupportedErr is returned for actions that are not supported by the debugger
+	NotSupportedErr = errors.New("not supported by this debugger")
 )
 
 // GiDebug is the interface for all supported debuggers.
@@ -62,11 +66,10 @@ type GiDebug interface {
 	GetState() (*State, error)
 
 	// Continue resumes process execution.  The channel will block until the
-	// process stops by any means.  Tracepoints are automatically handled by
-	// the debugger, and do not appear.  Typically there is just one State
-	// in the channel, but perhaps there could be more -- use a range to iterate
-	// over all items in the channel -- it will close after data is sent.
-	// The last state can be used for further updating.
+	// process stops by any means.  Tracepoints are automatically continued by
+	// the debugger, and each hit appears as a State with CurTrace and Trace set.
+	// Use a range to iterate over all items in the channel -- it will close
+	// after data is sent.  The last state can be used for further updating.
 	Continue(all *AllState) <-chan *State
 
 	// StepOver continues to the next source line, not entering function calls.
@@ -81,6 +84,13 @@ type GiDebug interface {
 	// StepSingle step a single cpu instruction.
 	StepSingle() (*State, error)
 
+	// Call resumes process execution while making a function call,
+	// given as an expression (e.g., f(x)), on given thread
+	// (lowest-level supported by language, e.g., Task if supported, else
+	// Thread).  If unsafe, the call is made even if it might not be safe.
+	// Return values are in State.Returns.
+	Call(threadID int, expr string, unsafe bool) (*State, error)
+
 	// SwitchThread switches the current system thread context to given one
 	SwitchThread(threadID int) (*State, error)
 
@@ -97,6 +107,17 @@ type GiDebug interface {
 	// and line number
 	SetBreak(fname string, line int) (*Break, error)
 
+	// SetBreakPC sets a new instruction-level breakpoint at given address
+	SetBreakPC(pc uint64) (*Break, error)
+
+	// SetBreakFunc sets a new breakpoint at the start of given function
+	SetBreakFunc(fname string) (*Break, error)
+
+	// SetWatch sets a new data watchpoint on given variable expression,
+	// evaluated in the current scope, which stops when it is written.
+	// Returns NotSupportedErr if the debugger does not support watchpoints.
+	SetWatch(expr string) (*Break, error)
+
 	// ListBreaks gets all breakpoints.
 	ListBreaks() ([]*Break, error)
 
@@ -104,8 +125,9 @@ type GiDebug interface {
 	ClearBreak(id int) error
 
 	// AmmendBreak updates the Condition and Trace information
-	// for the given breakpoint
-	AmendBreak(id int, fname string, line int, cond string, trace bool) error
+	// for the given breakpoint, including the expressions to evaluate
+	// at each hit of a tracepoint
+	AmendBreak(id int, fname string, line int, cond string, trace bool, traceVars []string) error
 
 	// UpdateBreaks updates current breakpoints based on given list of breakpoints.
 	// first gets the current list, and does actions to ensure that the list is set.
@@ -172,6 +194,15 @@ type GiDebug interface {
 	// e.g., Task if supported, else Thread), and frame number.
 	SetVar(name, value string, threadID int, frame int) error
 
+	// Disassemble returns the instructions of the function containing given
+	// address, for given thread (lowest-level supported by language,
+	// e.g., Task if supported, else Thread), and frame number.
+	Disassemble(threadID int, frame int, pc uint64) ([]*Instr, error)
+
+	// ExamineMemory returns the raw memory of the process, of given length
+	// starting at given address.
+	ExamineMemory(addr uint64, length int) ([]byte, error)
+
 	// ListSources lists all source files in the process matching filter.
 	ListSources(filter string) ([]string, error)
 
@@ -181,6 +212,12 @@ type GiDebug interface {
 	// ListTypes lists all types in the process matching filter.
 	ListTypes(filter string) ([]string, error)
 
+	// Command executes a raw debugger command, in the debugger's own command
+	// language, for given thread (lowest-level supported by language,
+	// e.g., Task if supported, else Thread), and frame number,
+	// returning its output.
+	Command(cmd string, threadID int, frame int) (string, error)
+
 	// WriteToConsole writes given message string to the debugger's output console.
 	// message should end in newline
 	WriteToConsole(msg string)
@@ -198,4 +235,27 @@ const (
 
 	// Attach means attach to an already-running process
 	Attach
+
+	// Core means examine a core dump file of a crashed process, post-mortem:
+	// its state can be inspected, but it cannot be run or stepped
+	Core
+
+	// Remote means connect to an already-running headless debugger server,
+	// e.g., one debugging a service in a container or on another machine
+	Remote
+
+	// Dump means browse a saved dump of the state of a process: a Go panic
+	// or SIGQUIT goroutine dump, or one exported from a debugging session.
+	// Like Core, it cannot be run or stepped.
+	Dump
+
+	// ModesN is the number of debugger modes
+	ModesN
 )
+
+//go:generate stringer -type=Modes
+
+var KiT_Modes = kit.Enums.AddEnum(ModesN, kit.NotBitFlag, nil)
+
+func (ev Modes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *Modes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
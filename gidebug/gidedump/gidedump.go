@@ -0,0 +1,472 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gidedump implements the GiDebug interface for browsing a saved
+// dump of the state of a process, read-only: a Go panic or SIGQUIT
+// goroutine dump, or a dump exported from a debugging session.
+package gidedump
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/ki/ints"
+)
+
+// GiDump is the dump implementation of the GiDebug interface
+type GiDump struct {
+	path     string                    // path to exe
+	rootPath string                    // root path for project
+	obuf     *giv.TextBuf              // output buffer
+	dump     *gidebug.StateDump        // the dump being browsed
+	curID    int                       // current task (or thread, if no tasks)
+	statFunc func(stat gidebug.Status) // status function
+	params   gidebug.Params            // local copy of initial params
+}
+
+// NewGiDump opens the dump file in the params (CoreFile), for given exe
+// path, and project root path
+func NewGiDump(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (*GiDump, error) {
+	gd := &GiDump{}
+	err := gd.Start(path, rootPath, outbuf, pars)
+	return gd, err
+}
+
+func (gd *GiDump) HasTasks() bool {
+	return gd.dump != nil && len(gd.dump.Tasks) > 0
+}
+
+func (gd *GiDump) WriteToConsole(msg string) {
+	if gd.obuf == nil {
+		log.Println(msg)
+		return
+	}
+	gd.obuf.AppendText([]byte(msg), true)
+}
+
+func (gd *GiDump) LogErr(err error) error {
+	if err == nil {
+		return err
+	}
+	gd.WriteToConsole(err.Error() + "\n")
+	return err
+}
+
+func (gd *GiDump) SetParams(params *gidebug.Params) {
+	gd.params = *params
+}
+
+// StartedCheck checks that the dump has been opened
+func (gd *GiDump) StartedCheck() error {
+	if !gd.IsActive() {
+		err := gidebug.NotStartedErr
+		return gd.LogErr(err)
+	}
+	return nil
+}
+
+// Start opens the dump file in the params (CoreFile), for a given exe path
+func (gd *GiDump) Start(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) error {
+	gd.path = path
+	gd.rootPath = rootPath
+	gd.obuf = outbuf
+	gd.params = *pars
+	gd.statFunc = pars.StatFunc
+	dp, err := gidebug.OpenDump(gd.params.CoreFile)
+	if err != nil {
+		if gd.statFunc != nil {
+			gd.statFunc(gidebug.Error)
+		}
+		return gd.LogErr(err)
+	}
+	dp.SetPaths(gd.params.PathMaps, rootPath)
+	gd.dump = dp
+	gd.curID = gd.dumpCurID()
+	gd.WriteToConsole(fmt.Sprintf("Opened dump: %s with %d goroutines and %d threads\n", gd.params.CoreFile, len(dp.Tasks), len(dp.Threads)))
+	if dp.Reason != "" {
+		gd.WriteToConsole(dp.Reason + "\n")
+	}
+	if gd.statFunc != nil {
+		gd.statFunc(gidebug.Ready)
+	}
+	return nil
+}
+
+// dumpCurID returns the id of the current task (or thread) of the dump
+func (gd *GiDump) dumpCurID() int {
+	if gd.HasTasks() {
+		return gd.dump.CurTask
+	}
+	return gd.dump.CurThread
+}
+
+// IsActive returns whether a dump has been opened
+func (gd *GiDump) IsActive() bool {
+	return gd.dump != nil
+}
+
+// Returns the pid of the process -- not known for a dump.
+func (gd *GiDump) ProcessPid() int {
+	return -1
+}
+
+// LastModified returns the time that the dump was made.
+func (gd *GiDump) LastModified() time.Time {
+	if gd.dump == nil {
+		return time.Time{}
+	}
+	return gd.dump.Time
+}
+
+// Detach closes the dump.
+func (gd *GiDump) Detach(killProcess bool) error {
+	gd.dump = nil
+	return nil
+}
+
+// Disconnect closes the dump.
+func (gd *GiDump) Disconnect(cont bool) error {
+	gd.dump = nil
+	return nil
+}
+
+// Restart is not supported for a dump.
+func (gd *GiDump) Restart() error {
+	return gidebug.NotSupportedErr
+}
+
+// GetState returns the state at the current task (or thread).
+func (gd *GiDump) GetState() (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	ds := &gidebug.State{}
+	if gd.HasTasks() {
+		if tk, _ := gidebug.TaskByID(gd.dump.Tasks, gd.curID); tk != nil {
+			ds.Task = *tk
+			if th, _ := gidebug.ThreadByID(gd.dump.Threads, tk.Thread); th != nil {
+				ds.Thread = *th
+			}
+		}
+	} else if th, _ := gidebug.ThreadByID(gd.dump.Threads, gd.curID); th != nil {
+		ds.Thread = *th
+	}
+	return ds, nil
+}
+
+// Continue is not supported for a dump: the channel is closed immediately.
+func (gd *GiDump) Continue(all *gidebug.AllState) <-chan *gidebug.State {
+	sc := make(chan *gidebug.State)
+	close(sc)
+	return sc
+}
+
+// StepOver is not supported for a dump.
+func (gd *GiDump) StepOver() (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// StepInto is not supported for a dump.
+func (gd *GiDump) StepInto() (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// StepOut is not supported for a dump.
+func (gd *GiDump) StepOut() (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// StepSingle is not supported for a dump.
+func (gd *GiDump) StepSingle() (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// Call is not supported for a dump.
+func (gd *GiDump) Call(threadID int, expr string, unsafe bool) (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// SwitchThread switches the current thread.
+func (gd *GiDump) SwitchThread(threadID int) (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	th, _ := gidebug.ThreadByID(gd.dump.Threads, threadID)
+	if th == nil {
+		return nil, fmt.Errorf("thread %d not found in dump", threadID)
+	}
+	if gd.HasTasks() {
+		if th.Task > 0 {
+			gd.curID = th.Task
+		}
+	} else {
+		gd.curID = threadID
+	}
+	return gd.GetState()
+}
+
+// SwitchTask switches the current task (goroutine).
+func (gd *GiDump) SwitchTask(threadID int) (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if tk, _ := gidebug.TaskByID(gd.dump.Tasks, threadID); tk == nil {
+		return nil, fmt.Errorf("goroutine %d not found in dump", threadID)
+	}
+	gd.curID = threadID
+	return gd.GetState()
+}
+
+// Stop does nothing: a dump is always stopped.
+func (gd *GiDump) Stop() (*gidebug.State, error) {
+	return gd.GetState()
+}
+
+// GetBreak is not supported for a dump.
+func (gd *GiDump) GetBreak(id int) (*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// SetBreak is not supported for a dump.
+func (gd *GiDump) SetBreak(fname string, line int) (*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// SetBreakPC is not supported for a dump.
+func (gd *GiDump) SetBreakPC(pc uint64) (*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// SetBreakFunc is not supported for a dump.
+func (gd *GiDump) SetBreakFunc(fname string) (*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// SetWatch is not supported for a dump.
+func (gd *GiDump) SetWatch(expr string) (*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ListBreaks is not supported for a dump, so the project breakpoints
+// are left as they are.
+func (gd *GiDump) ListBreaks() ([]*gidebug.Break, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ClearBreak is not supported for a dump.
+func (gd *GiDump) ClearBreak(id int) error {
+	return gidebug.NotSupportedErr
+}
+
+// AmendBreak is not supported for a dump.
+func (gd *GiDump) AmendBreak(id int, fname string, line int, cond string, trace bool, traceVars []string) error {
+	return gidebug.NotSupportedErr
+}
+
+// UpdateBreaks does nothing for a dump.
+func (gd *GiDump) UpdateBreaks(brk *[]*gidebug.Break) error {
+	return nil
+}
+
+// CancelNext does nothing for a dump.
+func (gd *GiDump) CancelNext() error {
+	return nil
+}
+
+// InitAllState initializes the given AllState with the state of the
+// current task (or thread) in the dump.
+func (gd *GiDump) InitAllState(all *gidebug.AllState) error {
+	bs, err := gd.GetState()
+	if err != nil {
+		return err
+	}
+	all.State = *bs
+	all.CurThread = all.State.Thread.ID
+	all.CurTask = all.State.Task.ID
+	all.CurFrame = 0
+	if gd.curID == gd.dumpCurID() {
+		all.CurFrame = gd.dump.CurFrame
+	}
+	all.Threads = gd.dump.Threads
+	all.Tasks = gd.dump.Tasks
+	all.Stack = gd.dump.Stack(gd.curID)
+	all.Vars, _ = gd.ListVars(gd.curID, all.CurFrame)
+	all.CurBreak = 0
+	return nil
+}
+
+// UpdateAllState updates the state for given threadId and
+// frame number.
+func (gd *GiDump) UpdateAllState(all *gidebug.AllState, threadID int, frame int) error {
+	if gd.HasTasks() {
+		all.CurTask = threadID
+	} else {
+		all.CurThread = threadID
+	}
+	all.Stack = gd.dump.Stack(threadID)
+	all.CurFrame = frame
+	all.Vars, _ = gd.ListVars(threadID, frame)
+	return nil
+}
+
+// FindFrames looks through the Stacks of all Tasks / Threads
+// for the closest Stack Frame to given file and line number.
+// Results are sorted by line number proximity to given line.
+func (gd *GiDump) FindFrames(all *gidebug.AllState, fname string, line int) ([]*gidebug.Frame, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	var fr []*gidebug.Frame
+	for _, sf := range gd.dump.Stacks {
+		for _, f := range sf {
+			if f.FPath != fname {
+				continue
+			}
+			fr = append(fr, f)
+			break
+		}
+	}
+	sort.Slice(fr, func(i, j int) bool {
+		dsti := ints.AbsInt(fr[i].Line - line)
+		dstj := ints.AbsInt(fr[j].Line - line)
+		return dsti < dstj
+	})
+	return fr, nil
+}
+
+// CurThreadID returns the proper current threadID (task or thread)
+// based on debugger, from given state.
+func (gd *GiDump) CurThreadID(all *gidebug.AllState) int {
+	if gd.HasTasks() {
+		return all.CurTask
+	}
+	return all.CurThread
+}
+
+// ListThreads lists all threads in the dump.
+func (gd *GiDump) ListThreads() ([]*gidebug.Thread, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	return gd.dump.Threads, nil
+}
+
+// GetThread gets a thread by its ID.
+func (gd *GiDump) GetThread(id int) (*gidebug.Thread, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	th, _ := gidebug.ThreadByID(gd.dump.Threads, id)
+	if th == nil {
+		return nil, fmt.Errorf("thread %d not found in dump", id)
+	}
+	return th, nil
+}
+
+// ListTasks lists all goroutines in the dump.
+func (gd *GiDump) ListTasks() ([]*gidebug.Task, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	return gd.dump.Tasks, nil
+}
+
+// Stack returns the stack of given task (or thread), up to given depth.
+func (gd *GiDump) Stack(threadID int, depth int) ([]*gidebug.Frame, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	st := gd.dump.Stack(threadID)
+	if depth > 0 && len(st) > depth {
+		st = st[:depth]
+	}
+	return st, nil
+}
+
+// ListGlobalVars is not supported for a dump.
+func (gd *GiDump) ListGlobalVars(filter string) ([]*gidebug.Variable, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ListVars lists the local variables saved in the dump, which are only
+// available for the frame that was current when the dump was exported.
+func (gd *GiDump) ListVars(threadID int, frame int) ([]*gidebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if threadID != gd.dumpCurID() || frame != gd.dump.CurFrame {
+		return nil, nil
+	}
+	vrs := make([]*gidebug.Variable, len(gd.dump.Vars))
+	for i, dv := range gd.dump.Vars {
+		vr := &gidebug.Variable{}
+		vr.InitName(vr, dv.Name)
+		vr.TypeStr = dv.Type
+		vr.FullTypeStr = dv.Type
+		vr.Value = dv.Value
+		vr.ElValue = dv.Value
+		vr.Loc.Line = dv.Line
+		vr.Dbg = gd
+		vrs[i] = vr
+	}
+	return vrs, nil
+}
+
+// GetVar returns a local variable saved in the dump, by name.
+func (gd *GiDump) GetVar(expr string, threadID int, frame int) (*gidebug.Variable, error) {
+	vrs, err := gd.ListVars(threadID, frame)
+	if err != nil {
+		return nil, err
+	}
+	for _, vr := range vrs {
+		if vr.Nm == expr {
+			return vr, nil
+		}
+	}
+	return nil, fmt.Errorf("variable %s not found in dump", expr)
+}
+
+// FollowPtr is not supported for a dump.
+func (gd *GiDump) FollowPtr(vr *gidebug.Variable) error {
+	return gidebug.NotSupportedErr
+}
+
+// SetVar is not supported for a dump.
+func (gd *GiDump) SetVar(name, value string, threadID int, frame int) error {
+	return gidebug.NotSupportedErr
+}
+
+// Disassemble is not supported for a dump.
+func (gd *GiDump) Disassemble(threadID int, frame int, pc uint64) ([]*gidebug.Instr, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ExamineMemory is not supported for a dump.
+func (gd *GiDump) ExamineMemory(addr uint64, length int) ([]byte, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ListSources is not supported for a dump.
+func (gd *GiDump) ListSources(filter string) ([]string, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ListFuncs is not supported for a dump.
+func (gd *GiDump) ListFuncs(filter string) ([]string, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// ListTypes is not supported for a dump.
+func (gd *GiDump) ListTypes(filter string) ([]string, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// Command is not supported for a dump.
+func (gd *GiDump) Command(cmd string, threadID int, frame int) (string, error) {
+	return "", gidebug.NotSupportedErr
+}
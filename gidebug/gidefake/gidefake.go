@@ -0,0 +1,783 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gidefake implements the GiDebug interface in memory, by playing
+// back a recorded debugging session, for testing the DebugView and other
+// users of the interface without a real debugger.  Use a Recorder to record
+// a session from a real debugger.
+package gidefake
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/ki/ints"
+)
+
+// GiFake is the fake implementation of the GiDebug interface
+type GiFake struct {
+	path     string                    // path to session file
+	rootPath string                    // root path for project
+	obuf     *giv.TextBuf              // output buffer
+	sess     *Session                  // the session being played back
+	statFunc func(stat gidebug.Status) // status function
+	params   gidebug.Params            // local copy of initial params
+	mu       sync.Mutex                // protects the following
+	next     int                       // index of the next stop
+	cur      *Stop                     // current stop -- nil if not started
+	breaks   []*gidebug.Break          // current breakpoints
+	lastID   int                       // last breakpoint id
+}
+
+// NewGiFake opens the recorded session file at given path, for given
+// project root path, which the paths recorded under the session root are
+// mapped to.
+func NewGiFake(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (*GiFake, error) {
+	gd := &GiFake{}
+	err := gd.Start(path, rootPath, outbuf, pars)
+	return gd, err
+}
+
+// Session returns the session being played back
+func (gd *GiFake) Session() *Session {
+	return gd.sess
+}
+
+func (gd *GiFake) HasTasks() bool {
+	return gd.sess != nil && gd.sess.HasTasks
+}
+
+func (gd *GiFake) WriteToConsole(msg string) {
+	if gd.obuf == nil {
+		log.Println(msg)
+		return
+	}
+	gd.obuf.AppendText([]byte(msg), true)
+}
+
+func (gd *GiFake) LogErr(err error) error {
+	if err == nil {
+		return err
+	}
+	gd.WriteToConsole(err.Error() + "\n")
+	return err
+}
+
+func (gd *GiFake) SetParams(params *gidebug.Params) {
+	gd.params = *params
+}
+
+// StartedCheck checks that the session has been opened
+func (gd *GiFake) StartedCheck() error {
+	if !gd.IsActive() {
+		err := gidebug.NotStartedErr
+		return gd.LogErr(err)
+	}
+	return nil
+}
+
+// Start opens the recorded session file at given path
+func (gd *GiFake) Start(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) error {
+	gd.path = path
+	gd.rootPath = rootPath
+	gd.obuf = outbuf
+	gd.params = *pars
+	gd.statFunc = pars.StatFunc
+	ss, err := OpenSession(path)
+	if err != nil {
+		if gd.statFunc != nil {
+			gd.statFunc(gidebug.Error)
+		}
+		return gd.LogErr(err)
+	}
+	if ss.Root != "" && rootPath != "" {
+		ss.SetPaths(gidebug.PathMaps{{Remote: ss.Root, Local: rootPath}})
+	}
+	gd.mu.Lock()
+	gd.sess = ss
+	gd.next = 0
+	gd.cur = nil
+	gd.mu.Unlock()
+	if gd.statFunc != nil {
+		gd.statFunc(gidebug.Ready)
+	}
+	return nil
+}
+
+// IsActive returns whether a session has been opened
+func (gd *GiFake) IsActive() bool {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	return gd.sess != nil
+}
+
+// Returns the pid of the process -- there is none.
+func (gd *GiFake) ProcessPid() int {
+	return -1
+}
+
+// LastModified returns the time that the session file was modified.
+func (gd *GiFake) LastModified() time.Time {
+	return time.Time{}
+}
+
+// Detach closes the session.
+func (gd *GiFake) Detach(killProcess bool) error {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	gd.sess = nil
+	gd.cur = nil
+	return nil
+}
+
+// Disconnect closes the session.
+func (gd *GiFake) Disconnect(cont bool) error {
+	return gd.Detach(false)
+}
+
+// Restart goes back to the start of the session.
+func (gd *GiFake) Restart() error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	gd.next = 0
+	gd.cur = nil
+	gd.mu.Unlock()
+	return nil
+}
+
+// advance goes to the next stop of the session, which must have been
+// reached by given run command
+func (gd *GiFake) advance(cmd string) (*Stop, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.next >= len(gd.sess.Stops) {
+		return nil, gd.LogErr(fmt.Errorf("gidefake: %s: session has no more stops", cmd))
+	}
+	st := gd.sess.Stops[gd.next]
+	if st.Cmd != cmd {
+		return nil, gd.LogErr(fmt.Errorf("gidefake: %s: session expected %s at stop %d", cmd, st.Cmd, gd.next))
+	}
+	gd.next++
+	gd.cur = st
+	return st, nil
+}
+
+// step advances by given step command, returning the new state
+func (gd *GiFake) step(cmd string) (*gidebug.State, error) {
+	st, err := gd.advance(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return st.State(gd), nil
+}
+
+// curStop returns the current stop, nil if not started or exited
+func (gd *GiFake) curStop() *Stop {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.cur == nil || gd.cur.Exited {
+		return nil
+	}
+	return gd.cur
+}
+
+// GetState returns the current debugger state.
+func (gd *GiFake) GetState() (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.cur == nil {
+		return &gidebug.State{}, nil
+	}
+	return gd.cur.State(gd), nil
+}
+
+// Continue goes to the next stop of the session, sending the tracepoint
+// hits on the way first.
+func (gd *GiFake) Continue(all *gidebug.AllState) <-chan *gidebug.State {
+	st, err := gd.advance(CmdContinue)
+	if err != nil {
+		return nil
+	}
+	sc := make(chan *gidebug.State)
+	go func() {
+		for _, te := range st.Traces {
+			ds := &gidebug.State{CurTrace: te.Break, Trace: te}
+			ds.Thread.File = te.File
+			ds.Thread.Line = te.Line
+			ds.Thread.FPath = te.FPath
+			ds.Thread.Func = te.Func
+			gd.WriteToConsole(fmt.Sprintf("Trace: %d File: %s:%d %s\n", te.Break, te.File, te.Line, te.Value))
+			sc <- ds
+		}
+		sc <- st.State(gd)
+		close(sc)
+	}()
+	return sc
+}
+
+// StepOver goes to the next stop of the session.
+func (gd *GiFake) StepOver() (*gidebug.State, error) {
+	return gd.step(CmdStepOver)
+}
+
+// StepInto goes to the next stop of the session.
+func (gd *GiFake) StepInto() (*gidebug.State, error) {
+	return gd.step(CmdStepInto)
+}
+
+// StepOut goes to the next stop of the session.
+func (gd *GiFake) StepOut() (*gidebug.State, error) {
+	return gd.step(CmdStepOut)
+}
+
+// StepSingle goes to the next stop of the session.
+func (gd *GiFake) StepSingle() (*gidebug.State, error) {
+	return gd.step(CmdStepInst)
+}
+
+// Call returns the recorded variable of given expression as the return
+// value -- functions are not actually called.
+func (gd *GiFake) Call(threadID int, expr string, unsafe bool) (*gidebug.State, error) {
+	vr, err := gd.GetVar(expr, threadID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := gd.GetState()
+	if err != nil {
+		return nil, err
+	}
+	ds.Returns = []*gidebug.Variable{vr}
+	return ds, nil
+}
+
+// SwitchThread switches the current thread.
+func (gd *GiFake) SwitchThread(threadID int) (*gidebug.State, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	th, _ := gidebug.ThreadByID(st.Threads, threadID)
+	if th == nil {
+		return nil, fmt.Errorf("thread %d not found", threadID)
+	}
+	ds := st.State(gd)
+	ds.Thread = *th
+	return ds, nil
+}
+
+// SwitchTask switches the current task.
+func (gd *GiFake) SwitchTask(threadID int) (*gidebug.State, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	tk, _ := gidebug.TaskByID(st.Tasks, threadID)
+	if tk == nil {
+		return nil, fmt.Errorf("task %d not found", threadID)
+	}
+	ds := st.State(gd)
+	ds.Task = *tk
+	return ds, nil
+}
+
+// Stop returns the current state: the session is always stopped.
+func (gd *GiFake) Stop() (*gidebug.State, error) {
+	return gd.GetState()
+}
+
+// GetBreak gets info about a breakpoint by ID.
+func (gd *GiFake) GetBreak(id int) (*gidebug.Break, error) {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	br, _ := gidebug.BreakByID(gd.breaks, id)
+	if br == nil {
+		return nil, fmt.Errorf("breakpoint %d not found", id)
+	}
+	cb := *br
+	return &cb, nil
+}
+
+// addBreak adds given breakpoint with a new id, returning a copy
+func (gd *GiFake) addBreak(br *gidebug.Break) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	gd.lastID++
+	br.ID = gd.lastID
+	br.On = true
+	gd.breaks = append(gd.breaks, br)
+	cb := *br
+	return &cb, nil
+}
+
+// SetBreak sets a new breakpoint at given file and line number, using the
+// address and function of that line from the session, if it was recorded.
+func (gd *GiFake) SetBreak(fname string, line int) (*gidebug.Break, error) {
+	br := &gidebug.Break{Kind: gidebug.BreakLine, FPath: fname, Line: line}
+	br.File = giv.RelFilePath(fname, gd.rootPath)
+	if lc := gd.findLoc(fname, line); lc != nil {
+		br.PC = lc.PC
+		br.Func = lc.Func
+	}
+	return gd.addBreak(br)
+}
+
+// findLoc returns the location of given file and line, from the frames
+// recorded in the session, nil if none
+func (gd *GiFake) findLoc(fname string, line int) *gidebug.Frame {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.sess == nil {
+		return nil
+	}
+	for _, st := range gd.sess.Stops {
+		for _, sf := range st.Stacks {
+			for _, fr := range sf {
+				if fr.FPath == fname && fr.Line == line {
+					return fr
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetBreakPC sets a new instruction-level breakpoint at given address
+func (gd *GiFake) SetBreakPC(pc uint64) (*gidebug.Break, error) {
+	return gd.addBreak(&gidebug.Break{Kind: gidebug.BreakAddr, PC: pc})
+}
+
+// SetBreakFunc sets a new breakpoint at the start of given function
+func (gd *GiFake) SetBreakFunc(fname string) (*gidebug.Break, error) {
+	return gd.addBreak(&gidebug.Break{Kind: gidebug.BreakFunc, Func: fname, Expr: fname})
+}
+
+// SetWatch sets a new data watchpoint on given variable expression
+func (gd *GiFake) SetWatch(expr string) (*gidebug.Break, error) {
+	return gd.addBreak(&gidebug.Break{Kind: gidebug.BreakWatch, Expr: expr})
+}
+
+// ListBreaks gets all breakpoints.
+func (gd *GiFake) ListBreaks() ([]*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	bks := make([]*gidebug.Break, len(gd.breaks))
+	for i, br := range gd.breaks {
+		cb := *br
+		bks[i] = &cb
+	}
+	return bks, nil
+}
+
+// ClearBreak deletes a breakpoint by ID.
+func (gd *GiFake) ClearBreak(id int) error {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	_, i := gidebug.BreakByID(gd.breaks, id)
+	if i < 0 {
+		return fmt.Errorf("breakpoint %d not found", id)
+	}
+	gd.breaks = append(gd.breaks[:i], gd.breaks[i+1:]...)
+	return nil
+}
+
+// AmendBreak updates the Condition and Trace information
+// for the given breakpoint
+func (gd *GiFake) AmendBreak(id int, fname string, line int, cond string, trace bool, traceVars []string) error {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	br, _ := gidebug.BreakByID(gd.breaks, id)
+	if br == nil {
+		return fmt.Errorf("breakpoint %d not found", id)
+	}
+	br.Cond = cond
+	br.Trace = trace
+	br.TraceVars = strings.Join(traceVars, ", ")
+	return nil
+}
+
+// UpdateBreaks updates current breakpoints based on given list of breakpoints.
+// first gets the current list, and does actions to ensure that the list is set.
+func (gd *GiFake) UpdateBreaks(brk *[]*gidebug.Break) error {
+	cb, err := gd.ListBreaks()
+	if err != nil {
+		return err
+	}
+	for _, b := range *brk {
+		var c *gidebug.Break
+		ci := -1
+		switch b.Kind {
+		case gidebug.BreakAddr:
+			c, ci = gidebug.BreakByPC(cb, b.PC)
+		case gidebug.BreakLine:
+			c, ci = gidebug.BreakByFile(cb, b.FPath, b.Line)
+		default:
+			if b.ID > 0 {
+				c, ci = gidebug.BreakByID(cb, b.ID)
+			}
+		}
+		if c != nil {
+			cb = append(cb[:ci], cb[ci+1:]...)
+			if !b.On {
+				gd.ClearBreak(c.ID)
+				continue
+			}
+		} else {
+			if !b.On {
+				continue
+			}
+			switch b.Kind {
+			case gidebug.BreakAddr:
+				c, err = gd.SetBreakPC(b.PC)
+			case gidebug.BreakFunc:
+				c, err = gd.SetBreakFunc(b.Expr)
+			case gidebug.BreakWatch:
+				c, err = gd.SetWatch(b.Expr)
+			default:
+				c, err = gd.SetBreak(b.FPath, b.Line)
+			}
+			if err != nil { // stays on, retried next update
+				b.ID = 0
+				continue
+			}
+		}
+		if b.Cond != c.Cond || b.Trace != c.Trace || b.TraceVars != c.TraceVars {
+			gd.AmendBreak(c.ID, c.FPath, c.Line, b.Cond, b.Trace, gidebug.TraceExprs(b.TraceVars))
+		}
+		b.ID = c.ID
+		if b.Kind == gidebug.BreakLine {
+			b.PC = c.PC
+			b.Func = c.Func
+		}
+	}
+	for _, c := range cb {
+		*brk = append(*brk, c)
+	}
+	gidebug.SortBreaks(*brk)
+	return nil
+}
+
+// CancelNext does nothing.
+func (gd *GiFake) CancelNext() error {
+	return nil
+}
+
+// curID returns the current task (or thread, if no tasks) of given stop
+func (gd *GiFake) curID(st *Stop) int {
+	if gd.HasTasks() {
+		return st.Task.ID
+	}
+	return st.Thread.ID
+}
+
+// InitAllState initializes the given AllState with relevant info for
+// current state of things.  Does Not get AllVars
+func (gd *GiFake) InitAllState(all *gidebug.AllState) error {
+	bs, err := gd.GetState()
+	if err != nil {
+		return err
+	}
+	all.State = *bs
+	all.CurThread = bs.Thread.ID
+	all.CurTask = bs.Task.ID
+	all.CurFrame = 0
+	all.CurBreak = 0
+	st := gd.curStop()
+	if st == nil {
+		all.Threads = nil
+		all.Tasks = nil
+		all.Stack = nil
+		all.Vars = nil
+		return nil
+	}
+	all.Threads, _ = gd.ListThreads()
+	all.Tasks, _ = gd.ListTasks()
+	id := gd.curID(st)
+	all.Stack, _ = gd.Stack(id, 100)
+	all.Vars, _ = gd.ListVars(id, 0)
+	if cf := all.StackFrame(0); cf != nil {
+		bk, _ := gidebug.BreakByFile(all.Breaks, cf.FPath, cf.Line)
+		if bk != nil {
+			all.CurBreak = bk.ID
+		}
+	}
+	return nil
+}
+
+// UpdateAllState updates the state for given threadId and
+// frame number (only info different from current results is updated).
+func (gd *GiFake) UpdateAllState(all *gidebug.AllState, threadID int, frame int) error {
+	updt := false
+	if threadID != gd.CurThreadID(all) {
+		updt = true
+		if gd.HasTasks() {
+			all.CurTask = threadID
+		} else {
+			all.CurThread = threadID
+		}
+		all.Stack, _ = gd.Stack(threadID, 100)
+	}
+	if updt || all.CurFrame != frame {
+		all.CurFrame = frame
+		all.Vars, _ = gd.ListVars(threadID, frame)
+	}
+	return nil
+}
+
+// FindFrames looks through the Stacks of all Tasks / Threads
+// for the closest Stack Frame to given file and line number.
+// Results are sorted by line number proximity to given line.
+func (gd *GiFake) FindFrames(all *gidebug.AllState, fname string, line int) ([]*gidebug.Frame, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	var fr []*gidebug.Frame
+	for _, sf := range st.Stacks {
+		for _, f := range sf {
+			if f.FPath != fname {
+				continue
+			}
+			fr = append(fr, f)
+			break
+		}
+	}
+	sort.Slice(fr, func(i, j int) bool {
+		dsti := ints.AbsInt(fr[i].Line - line)
+		dstj := ints.AbsInt(fr[j].Line - line)
+		return dsti < dstj
+	})
+	return fr, nil
+}
+
+// CurThreadID returns the proper current threadID (task or thread)
+// based on the session, from given state.
+func (gd *GiFake) CurThreadID(all *gidebug.AllState) int {
+	if gd.HasTasks() {
+		return all.CurTask
+	}
+	return all.CurThread
+}
+
+// ListThreads lists all threads.
+func (gd *GiFake) ListThreads() ([]*gidebug.Thread, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, nil
+	}
+	return st.Threads, nil
+}
+
+// GetThread gets a thread by its ID.
+func (gd *GiFake) GetThread(id int) (*gidebug.Thread, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	th, _ := gidebug.ThreadByID(st.Threads, id)
+	if th == nil {
+		return nil, fmt.Errorf("thread %d not found", id)
+	}
+	return th, nil
+}
+
+// ListTasks lists all tasks.
+func (gd *GiFake) ListTasks() ([]*gidebug.Task, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, nil
+	}
+	return st.Tasks, nil
+}
+
+// Stack returns the recorded stack of given task (or thread, if no tasks)
+func (gd *GiFake) Stack(threadID int, depth int) ([]*gidebug.Frame, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	sf := st.Stacks[threadID]
+	if len(sf) > depth {
+		sf = sf[:depth]
+	}
+	return sf, nil
+}
+
+// ListGlobalVars lists the recorded global variables matching filter regexp.
+func (gd *GiFake) ListGlobalVars(filter string) ([]*gidebug.Variable, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+	var vrs []*gidebug.Variable
+	for _, sv := range st.Globals {
+		if re.MatchString(sv.Name) {
+			vrs = append(vrs, sv.Variable(gd))
+		}
+	}
+	gidebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// ListVars lists the recorded local variables of given task (or thread)
+// and frame number.
+func (gd *GiFake) ListVars(threadID int, frame int) ([]*gidebug.Variable, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	svs := st.Vars(threadID, frame)
+	vrs := make([]*gidebug.Variable, len(svs))
+	for i, sv := range svs {
+		vrs[i] = sv.Variable(gd)
+	}
+	gidebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// GetVar returns the recorded variable of given expression, from the locals
+// of given task (or thread) and frame number, or the globals.
+func (gd *GiFake) GetVar(expr string, threadID int, frame int) (*gidebug.Variable, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	sv := FindVar(st.Vars(threadID, frame), expr)
+	if sv == nil {
+		sv = FindVar(st.Globals, expr)
+	}
+	if sv == nil {
+		return nil, fmt.Errorf("could not find variable: %s", expr)
+	}
+	return sv.Variable(gd), nil
+}
+
+// FollowPtr fills in the Child of given pointer Variable
+// with the value it points to, if it was recorded.
+func (gd *GiFake) FollowPtr(vr *gidebug.Variable) error {
+	if vr.HasChildren() {
+		return nil
+	}
+	ch, err := gd.GetVar("*"+vr.Nm, 0, 0)
+	if err != nil {
+		return err
+	}
+	vr.AddChild(ch)
+	return nil
+}
+
+// SetVar sets the value of a recorded variable, at the current stop.
+func (gd *GiFake) SetVar(name, value string, threadID int, frame int) error {
+	st := gd.curStop()
+	if st == nil {
+		return gidebug.NotStartedErr
+	}
+	sv := FindVar(st.Vars(threadID, frame), name)
+	if sv == nil {
+		sv = FindVar(st.Globals, name)
+	}
+	if sv == nil {
+		return fmt.Errorf("could not find variable: %s", name)
+	}
+	sv.Value = value
+	return nil
+}
+
+// Disassemble returns the recorded disassembly, if it contains given address.
+func (gd *GiFake) Disassemble(threadID int, frame int, pc uint64) ([]*gidebug.Instr, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	for _, in := range st.Disasm {
+		if in.PC == pc {
+			return st.Disasm, nil
+		}
+	}
+	return nil, gidebug.NotSupportedErr
+}
+
+// ExamineMemory returns the recorded memory of given length starting at
+// given address, if it was recorded.
+func (gd *GiFake) ExamineMemory(addr uint64, length int) ([]byte, error) {
+	st := gd.curStop()
+	if st == nil {
+		return nil, gidebug.NotStartedErr
+	}
+	for _, m := range st.Mem {
+		if addr >= m.Addr && addr+uint64(length) <= m.Addr+uint64(len(m.Bytes)) {
+			off := addr - m.Addr
+			return m.Bytes[off : off+uint64(length)], nil
+		}
+	}
+	return nil, gidebug.NotSupportedErr
+}
+
+// filterList returns the items of the list matching filter regexp
+func filterList(list []string, filter string) ([]string, error) {
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+	var fl []string
+	for _, s := range list {
+		if re.MatchString(s) {
+			fl = append(fl, s)
+		}
+	}
+	return fl, nil
+}
+
+// ListSources lists the recorded source files matching filter regexp.
+func (gd *GiFake) ListSources(filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	return filterList(gd.sess.Sources, filter)
+}
+
+// ListFuncs lists the recorded functions matching filter regexp.
+func (gd *GiFake) ListFuncs(filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	return filterList(gd.sess.Funcs, filter)
+}
+
+// ListTypes lists the recorded types matching filter regexp.
+func (gd *GiFake) ListTypes(filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	return filterList(gd.sess.Types, filter)
+}
+
+// Command is not supported: there is no debugger to run it.
+func (gd *GiFake) Command(cmd string, threadID int, frame int) (string, error) {
+	return "", gidebug.NotSupportedErr
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidefake
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/gide/gidebug/gidetest"
+)
+
+var fixtureSession = filepath.Join("testdata", "fixture.json")
+
+func TestConformance(t *testing.T) {
+	gidetest.Conformance(t, func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error) {
+		return NewGiFake(fixtureSession, dir, nil, pars)
+	})
+}
+
+// TestRecord records a session by running the conformance suite on the
+// fake debugger, and checks that the recorded session passes it too
+func TestRecord(t *testing.T) {
+	var rc *Recorder
+	gidetest.Conformance(t, func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error) {
+		gd, err := NewGiFake(fixtureSession, dir, nil, pars)
+		if err != nil {
+			return nil, err
+		}
+		rc = NewRecorder(gd, dir, `^main\.`)
+		return rc, nil
+	})
+	if t.Failed() {
+		return
+	}
+	if len(rc.Sess.Stops) != 4 || len(rc.Sess.Funcs) != 2 {
+		t.Fatalf("should have recorded 4 stops and 2 funcs, was: %d, %d", len(rc.Sess.Stops), len(rc.Sess.Funcs))
+	}
+	dir, err := ioutil.TempDir("", "gidefake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fname := filepath.Join(dir, "session.json")
+	if err := rc.Save(fname); err != nil {
+		t.Fatal(err)
+	}
+	gidetest.Conformance(t, func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error) {
+		return NewGiFake(fname, dir, nil, pars)
+	})
+}
+
+func TestSessionOrder(t *testing.T) {
+	pars := gidebug.DefaultParams
+	gd, err := NewGiFake(fixtureSession, gidetest.FixtureDir(), nil, &pars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gd.StepOver(); err == nil {
+		t.Errorf("step over should fail when the session continues first")
+	}
+	if gd.Continue(nil) == nil {
+		t.Errorf("continue should go to the first stop")
+	}
+	if err := gd.SetVar("sum", "42", 1, 0); err != nil {
+		t.Error(err)
+	}
+	if vr, err := gd.GetVar("sum", 1, 0); err != nil || vr.Value != "42" {
+		t.Errorf("sum should have been set to 42, was: %v %v", vr, err)
+	}
+}
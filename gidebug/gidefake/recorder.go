@@ -0,0 +1,153 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidefake
+
+import (
+	"github.com/goki/gide/gidebug"
+)
+
+// Recorder records a debugging session from a real debugger, which it wraps:
+// it is used in place of the debugger, and at each stop of a run command
+// (continue, step etc) it records the state of the process, which can then
+// be saved and played back with the fake debugger.
+type Recorder struct {
+	gidebug.GiDebug
+	Sess   *Session `desc:"the session being recorded"`
+	Filter string   `desc:"regexp filter of the global variables, functions and types to record -- none are recorded if empty, as there can be very many"`
+	Depth  int      `desc:"number of frames of each stack to record -- local variables are recorded for each of the frames of the current task (or thread)"`
+}
+
+// NewRecorder returns a new recorder for given debugger, which must be
+// active, with given project root path
+func NewRecorder(dbg gidebug.GiDebug, rootPath, filter string) *Recorder {
+	rc := &Recorder{GiDebug: dbg, Filter: filter, Depth: 20}
+	rc.Sess = &Session{Root: rootPath, HasTasks: dbg.HasTasks()}
+	rc.Sess.Sources, _ = dbg.ListSources("")
+	if filter != "" {
+		rc.Sess.Funcs, _ = dbg.ListFuncs(filter)
+		rc.Sess.Types, _ = dbg.ListTypes(filter)
+	}
+	return rc
+}
+
+// Save saves the recorded session to given file, as JSON
+func (rc *Recorder) Save(fname string) error {
+	return rc.Sess.Save(fname)
+}
+
+// Continue resumes process execution, recording the stop it ends at,
+// with any tracepoint hits on the way.
+func (rc *Recorder) Continue(all *gidebug.AllState) <-chan *gidebug.State {
+	dsc := rc.GiDebug.Continue(all)
+	if dsc == nil {
+		return nil
+	}
+	sc := make(chan *gidebug.State)
+	go func() {
+		var trs []*gidebug.TraceEvent
+		var last *gidebug.State
+		for ds := range dsc {
+			if ds.CurTrace > 0 && ds.Trace != nil {
+				trs = append(trs, ds.Trace)
+			} else {
+				last = ds
+			}
+			sc <- ds
+		}
+		if last != nil {
+			st := rc.Record(CmdContinue, last)
+			st.Traces = trs
+		}
+		close(sc)
+	}()
+	return sc
+}
+
+// StepOver continues to the next source line, recording the stop.
+func (rc *Recorder) StepOver() (*gidebug.State, error) {
+	return rc.step(CmdStepOver, rc.GiDebug.StepOver)
+}
+
+// StepInto continues to the next source line, recording the stop.
+func (rc *Recorder) StepInto() (*gidebug.State, error) {
+	return rc.step(CmdStepInto, rc.GiDebug.StepInto)
+}
+
+// StepOut continues to the return of the current function, recording the stop.
+func (rc *Recorder) StepOut() (*gidebug.State, error) {
+	return rc.step(CmdStepOut, rc.GiDebug.StepOut)
+}
+
+// StepSingle steps a single cpu instruction, recording the stop.
+func (rc *Recorder) StepSingle() (*gidebug.State, error) {
+	return rc.step(CmdStepInst, rc.GiDebug.StepSingle)
+}
+
+func (rc *Recorder) step(cmd string, fun func() (*gidebug.State, error)) (*gidebug.State, error) {
+	ds, err := fun()
+	if err == nil && ds != nil {
+		rc.Record(cmd, ds)
+	}
+	return ds, err
+}
+
+// Record records the stop at given state, reached by given run command,
+// adding it to the session.
+func (rc *Recorder) Record(cmd string, ds *gidebug.State) *Stop {
+	st := &Stop{Cmd: cmd, Thread: ds.Thread, Task: ds.Task, Exited: ds.Exited, ExitStatus: ds.ExitStatus}
+	st.Returns = NewVars(ds.Returns)
+	rc.Sess.Stops = append(rc.Sess.Stops, st)
+	if ds.Exited {
+		return st
+	}
+	dbg := rc.GiDebug
+	st.Threads, _ = dbg.ListThreads()
+	st.Tasks, _ = dbg.ListTasks()
+	st.Stacks = make(map[int][]*gidebug.Frame)
+	curID := ds.Thread.ID
+	if rc.Sess.HasTasks {
+		curID = ds.Task.ID
+		for _, tk := range st.Tasks {
+			rc.recordStack(st, tk.ID)
+		}
+	} else {
+		for _, th := range st.Threads {
+			rc.recordStack(st, th.ID)
+		}
+	}
+	for _, fr := range st.Stacks[curID] {
+		vrs, err := dbg.ListVars(curID, fr.Depth)
+		if err != nil {
+			continue
+		}
+		st.Locals = append(st.Locals, &Locals{ID: curID, Frame: fr.Depth, Vars: NewVars(vrs)})
+	}
+	if rc.Filter != "" {
+		if gvs, err := dbg.ListGlobalVars(rc.Filter); err == nil {
+			st.Globals = NewVars(gvs)
+		}
+	}
+	if ds.Thread.PC != 0 {
+		st.Disasm, _ = dbg.Disassemble(curID, 0, ds.Thread.PC)
+	}
+	return st
+}
+
+// recordStack records the stack of given task (or thread), without the
+// Vars and Args of the frames, which are recorded as Locals
+func (rc *Recorder) recordStack(st *Stop, id int) {
+	sf, err := rc.GiDebug.Stack(id, rc.Depth)
+	if err != nil {
+		return
+	}
+	ss := make([]*gidebug.Frame, len(sf))
+	for i, fr := range sf {
+		cf := *fr
+		cf.Vars = nil
+		cf.Args = nil
+		ss[i] = &cf
+	}
+	st.Stacks[id] = ss
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidefake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/pi/syms"
+)
+
+// Session is a recorded debugging session, which is played back by the fake
+// debugger: each run command (continue, step etc) goes to the next Stop.
+// Sessions are recorded from a real debugger with a Recorder, and can also
+// be written or edited by hand.
+type Session struct {
+	Root     string   `desc:"project root path when the session was recorded -- paths under it are mapped to the root path the session is played back with"`
+	HasTasks bool     `desc:"the recorded debugger has tasks (e.g., goroutines) -- stacks and variables are then by task, else by thread"`
+	Sources  []string `desc:"source files of the program"`
+	Funcs    []string `desc:"functions of the program"`
+	Types    []string `desc:"types of the program"`
+	Stops    []*Stop  `desc:"the stops of the session, in order"`
+}
+
+// Commands that run the process, which lead to a Stop
+const (
+	CmdContinue = "continue"
+	CmdStepOver = "next"
+	CmdStepInto = "step"
+	CmdStepOut  = "stepout"
+	CmdStepInst = "stepinst"
+)
+
+// Stop is the state of the process at a stop in a recorded session
+type Stop struct {
+	Cmd        string                   `desc:"the run command that led to this stop: continue, next, step, stepout, or stepinst"`
+	Thread     gidebug.Thread           `desc:"current system thread"`
+	Task       gidebug.Task             `desc:"current task"`
+	Exited     bool                     `desc:"the program has exited"`
+	ExitStatus int                      `desc:"the exit status, if Exited"`
+	Traces     []*gidebug.TraceEvent    `desc:"tracepoint hits on the way to this stop"`
+	Returns    []*Var                   `desc:"return values of the function just stepped out of"`
+	Threads    []*gidebug.Thread        `desc:"all system threads"`
+	Tasks      []*gidebug.Task          `desc:"all tasks"`
+	Stacks     map[int][]*gidebug.Frame `desc:"stacks of the tasks (or threads, if no tasks), by id -- frames do not have their Vars or Args"`
+	Locals     []*Locals                `desc:"local variables, of the frames they were recorded for"`
+	Globals    []*Var                   `desc:"global variables"`
+	Disasm     []*gidebug.Instr         `desc:"disassembly of the function of the top frame of the current task (or thread)"`
+	Mem        []*Mem                   `desc:"memory that was recorded"`
+}
+
+// Locals are the local variables of one frame of a task (or thread)
+type Locals struct {
+	ID    int    `desc:"id of the task (or thread, if no tasks)"`
+	Frame int    `desc:"frame depth"`
+	Vars  []*Var `desc:"the variables, including args"`
+}
+
+// Mem is a recorded block of memory
+type Mem struct {
+	Addr  uint64 `desc:"start address"`
+	Bytes []byte `desc:"contents"`
+}
+
+// Var is a recorded variable, with its children
+type Var struct {
+	Name  string     `desc:"name of the variable"`
+	Type  string     `desc:"type of the variable"`
+	Kind  syms.Kinds `desc:"kind of the variable"`
+	Value string     `desc:"value of the variable"`
+	Addr  uintptr    `desc:"address of the variable"`
+	Len   int64      `desc:"length, for lists, maps and strings"`
+	Kids  []*Var     `desc:"children of the variable, e.g., fields and elements"`
+}
+
+// NewVar returns the recording of given variable, with its children
+func NewVar(vr *gidebug.Variable) *Var {
+	sv := &Var{Name: vr.Nm, Type: vr.FullTypeStr, Kind: vr.Kind, Value: vr.Value, Addr: vr.Addr, Len: vr.Len}
+	for _, k := range vr.Kids {
+		if kv, ok := k.(*gidebug.Variable); ok {
+			sv.Kids = append(sv.Kids, NewVar(kv))
+		}
+	}
+	return sv
+}
+
+// NewVars returns the recordings of given variables
+func NewVars(vrs []*gidebug.Variable) []*Var {
+	svs := make([]*Var, len(vrs))
+	for i, vr := range vrs {
+		svs[i] = NewVar(vr)
+	}
+	return svs
+}
+
+// Variable returns the variable for this recording, with its children,
+// for given debugger
+func (sv *Var) Variable(dbg gidebug.GiDebug) *gidebug.Variable {
+	vr := &gidebug.Variable{}
+	vr.InitName(vr, sv.Name)
+	vr.FullTypeStr = sv.Type
+	vr.TypeStr = sv.Type
+	vr.Kind = sv.Kind
+	vr.Value = sv.Value
+	vr.ElValue = sv.Value
+	vr.Addr = sv.Addr
+	vr.Len = sv.Len
+	vr.Dbg = dbg
+	for _, k := range sv.Kids {
+		vr.AddChild(k.Variable(dbg))
+	}
+	return vr
+}
+
+// Find returns the variable of given expression: a name, optionally
+// followed by .field or [index] of its children
+func (sv *Var) Find(expr string) *Var {
+	if expr == sv.Name {
+		return sv
+	}
+	if !strings.HasPrefix(expr, sv.Name) {
+		return nil
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(expr, sv.Name), ".")
+	for _, k := range sv.Kids {
+		if fv := k.Find(rest); fv != nil {
+			return fv
+		}
+	}
+	return nil
+}
+
+// FindVar returns the variable of given expression from given list, nil if
+// not found
+func FindVar(svs []*Var, expr string) *Var {
+	for _, sv := range svs {
+		if fv := sv.Find(expr); fv != nil {
+			return fv
+		}
+	}
+	return nil
+}
+
+// Vars returns the local variables of given task (or thread) id and frame
+// depth, nil if not recorded
+func (st *Stop) Vars(id, frame int) []*Var {
+	for _, lc := range st.Locals {
+		if lc.ID == id && lc.Frame == frame {
+			return lc.Vars
+		}
+	}
+	return nil
+}
+
+// State returns the State at this stop
+func (st *Stop) State(dbg gidebug.GiDebug) *gidebug.State {
+	ds := &gidebug.State{Thread: st.Thread, Task: st.Task, Exited: st.Exited, ExitStatus: st.ExitStatus}
+	for _, sv := range st.Returns {
+		ds.Returns = append(ds.Returns, sv.Variable(dbg))
+	}
+	return ds
+}
+
+// SetPaths maps all of the file paths in the session with given mapping
+func (ss *Session) SetPaths(pm gidebug.PathMaps) {
+	for i, fp := range ss.Sources {
+		ss.Sources[i] = pm.ToLocal(fp)
+	}
+	for _, st := range ss.Stops {
+		st.Thread.FPath = pm.ToLocal(st.Thread.FPath)
+		st.Task.FPath = pm.ToLocal(st.Task.FPath)
+		for _, th := range st.Threads {
+			th.FPath = pm.ToLocal(th.FPath)
+		}
+		for _, tk := range st.Tasks {
+			tk.FPath = pm.ToLocal(tk.FPath)
+		}
+		for _, sf := range st.Stacks {
+			for _, fr := range sf {
+				fr.FPath = pm.ToLocal(fr.FPath)
+			}
+		}
+		for _, te := range st.Traces {
+			te.FPath = pm.ToLocal(te.FPath)
+		}
+		for _, in := range st.Disasm {
+			in.FPath = pm.ToLocal(in.FPath)
+		}
+	}
+}
+
+// Save saves the session to given file, as JSON
+func (ss *Session) Save(fname string) error {
+	b, err := json.MarshalIndent(ss, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// OpenSession opens a session from given JSON file
+func OpenSession(fname string) (*Session, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	ss := &Session{}
+	err = json.Unmarshal(b, ss)
+	if err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
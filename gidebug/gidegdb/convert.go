@@ -0,0 +1,240 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidegdb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/pi/syms"
+)
+
+// localPath returns given file path from gdb mapped to the local one,
+// for Remote mode
+func (gd *GiGdb) localPath(fpath string) string {
+	return gd.params.PathMaps.ToLocal(fpath)
+}
+
+// remotePath returns given local file path mapped to the one used by
+// gdb, for Remote mode
+func (gd *GiGdb) remotePath(fpath string) string {
+	return gd.params.PathMaps.ToRemote(fpath)
+}
+
+// cvtStopped converts a *stopped record into the State
+func (gd *GiGdb) cvtStopped(mr *MIRecord) *gidebug.State {
+	st := &gidebug.State{}
+	switch mr.Vals.Str("reason") {
+	case "exited-normally":
+		st.Exited = true
+		return st
+	case "exited":
+		st.Exited = true
+		ec, _ := strconv.ParseInt(mr.Vals.Str("exit-code"), 8, 64)
+		st.ExitStatus = int(ec)
+		return st
+	case "exited-signalled":
+		st.Exited = true
+		st.ExitStatus = -1
+		gd.WriteToConsole("Program terminated with signal: " + mr.Vals.Str("signal-name") + "\n")
+		return st
+	case "signal-received":
+		gd.WriteToConsole("Program received signal: " + mr.Vals.Str("signal-name") + ", " + mr.Vals.Str("signal-meaning") + "\n")
+	case "breakpoint-hit":
+		if id := mr.Vals.Int("bkptno"); gd.isTrace(id) {
+			st.CurTrace = id
+		}
+	case "function-finished":
+		if rv := mr.Vals.Str("return-value"); rv != "" {
+			vr := &gidebug.Variable{}
+			vr.InitName(vr, mr.Vals.Str("gdb-result-var"))
+			vr.Value = rv
+			vr.ElValue = rv
+			vr.Dbg = gd
+			st.Returns = []*gidebug.Variable{vr}
+		}
+	}
+	ft := mr.Vals.Tuple("frame")
+	th := &st.Thread
+	th.ID = mr.Vals.Int("thread-id")
+	th.PC = ft.Addr("addr")
+	th.FPath = gd.localPath(ft.Str("fullname"))
+	th.File = giv.RelFilePath(th.FPath, gd.rootPath)
+	th.Line = ft.Int("line")
+	th.Func = ft.Str("func")
+	return st
+}
+
+// isTrace returns true if given breakpoint id is a tracepoint
+func (gd *GiGdb) isTrace(id int) bool {
+	_, has := gd.traceExprs(id)
+	return has
+}
+
+// traceExprs returns the trace expressions of given breakpoint id,
+// and false if it is not a tracepoint
+func (gd *GiGdb) traceExprs(id int) ([]string, bool) {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	tv, has := gd.traces[id]
+	return tv, has
+}
+
+func (gd *GiGdb) cvtThread(tt MITuple) *gidebug.Thread {
+	th := &gidebug.Thread{}
+	th.ID = tt.Int("id")
+	ft := tt.Tuple("frame")
+	th.PC = ft.Addr("addr")
+	th.FPath = gd.localPath(ft.Str("fullname"))
+	th.File = giv.RelFilePath(th.FPath, gd.rootPath)
+	th.Line = ft.Int("line")
+	th.Func = ft.Str("func")
+	return th
+}
+
+func (gd *GiGdb) cvtFrame(ft MITuple, threadID int) *gidebug.Frame {
+	fr := &gidebug.Frame{}
+	fr.Depth = ft.Int("level")
+	fr.ThreadID = threadID
+	fr.PC = ft.Addr("addr")
+	fr.FPath = gd.localPath(ft.Str("fullname"))
+	fr.File = giv.RelFilePath(fr.FPath, gd.rootPath)
+	fr.Line = ft.Int("line")
+	fr.Func = ft.Str("func")
+	return fr
+}
+
+func (gd *GiGdb) cvtBreak(bt MITuple) *gidebug.Break {
+	bp := &gidebug.Break{}
+	bp.On = bt.Str("enabled") != "n"
+	bp.ID = bt.Int("number")
+	bp.PC = bt.Addr("addr")
+	bp.FPath = gd.localPath(bt.Str("fullname"))
+	bp.File = giv.RelFilePath(bp.FPath, gd.rootPath)
+	bp.Line = bt.Int("line")
+	bp.Func = bt.Str("func")
+	bp.Cond = bt.Str("cond")
+	switch {
+	case strings.Contains(bt.Str("type"), "watchpoint"):
+		bp.Kind = gidebug.BreakWatch
+		bp.Expr = bt.Str("what")
+	case strings.HasPrefix(bt.Str("original-location"), "*"):
+		bp.Kind = gidebug.BreakAddr
+	case bp.Line > 0 && strings.Contains(bt.Str("original-location"), ":"):
+		bp.Kind = gidebug.BreakLine
+	case bt.Str("original-location") != "":
+		bp.Kind = gidebug.BreakFunc
+		bp.Expr = strings.TrimPrefix(bt.Str("original-location"), "-function ")
+	}
+	if tv, has := gd.traceExprs(bp.ID); has {
+		bp.Trace = true
+		bp.TraceVars = strings.Join(tv, ", ")
+	}
+	return bp
+}
+
+// cvtVarObj converts a gdb variable object, with given expression as its name
+func (gd *GiGdb) cvtVarObj(vt MITuple, expr string) *gidebug.Variable {
+	vr := &gidebug.Variable{}
+	vr.InitName(vr, expr)
+	gd.setVarType(vr, vt.Str("type"))
+	vr.Value = vt.Str("value")
+	vr.ElValue = vr.Value
+	if vr.Kind.SubCat() == syms.List || vr.Kind.SubCat() == syms.Struct {
+		vr.Len = int64(vt.Int("numchild"))
+	}
+	if vr.Kind.IsPtr() {
+		vr.Addr = uintptr(parseAddr(vr.Value))
+	}
+	vr.Dbg = gd
+	return vr
+}
+
+// setVarType sets the type and kind of given variable from C / C++ type
+func (gd *GiGdb) setVarType(vr *gidebug.Variable, typ string) {
+	vr.FullTypeStr = typ
+	vr.TypeStr = typ
+	vr.Kind = CTypeKind(typ)
+}
+
+// CTypeKind returns the kind of given C or C++ type
+func CTypeKind(typ string) syms.Kinds {
+	typ = strings.TrimSpace(typ)
+	switch {
+	case typ == "":
+		return syms.Unknown
+	case strings.HasSuffix(typ, "*"), strings.Contains(typ, "(*)"):
+		return syms.Ptr
+	case strings.HasSuffix(typ, "&"):
+		return syms.Ref
+	case strings.HasSuffix(typ, "]"):
+		return syms.Array
+	case strings.Contains(typ, "("):
+		return syms.Func
+	}
+	flds := strings.Fields(typ)
+	unsigned := false
+	for _, f := range flds {
+		switch f {
+		case "struct", "class", "union":
+			return syms.Struct
+		case "unsigned":
+			unsigned = true
+		}
+	}
+	switch flds[len(flds)-1] {
+	case "bool", "_Bool":
+		return syms.Bool
+	case "char", "int8_t":
+		if unsigned {
+			return syms.Uint8
+		}
+		return syms.Int8
+	case "uint8_t":
+		return syms.Uint8
+	case "short", "int16_t":
+		if unsigned {
+			return syms.Uint16
+		}
+		return syms.Int16
+	case "uint16_t":
+		return syms.Uint16
+	case "int32_t":
+		return syms.Int32
+	case "uint32_t":
+		return syms.Uint32
+	case "int64_t":
+		return syms.Int64
+	case "uint64_t", "size_t":
+		return syms.Uint64
+	case "int", "long", "signed":
+		if unsigned {
+			return syms.Uint
+		}
+		return syms.Int
+	case "unsigned":
+		return syms.Uint
+	case "float":
+		return syms.Float32
+	case "double":
+		return syms.Float64
+	}
+	return syms.Struct
+}
+
+// parseAddr parses the address at the start of given value, e.g.,
+// 0x7ffc1234 "hello" or (int *) 0x7ffc1234
+func parseAddr(val string) uint64 {
+	if ci := strings.Index(val, ")"); strings.HasPrefix(val, "(") && ci > 0 {
+		val = strings.TrimSpace(val[ci+1:])
+	}
+	if si := strings.IndexAny(val, " <"); si > 0 {
+		val = val[:si]
+	}
+	a, _ := strconv.ParseUint(val, 0, 64)
+	return a
+}
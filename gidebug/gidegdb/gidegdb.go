@@ -0,0 +1,1254 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gidegdb implements the GiDebug interface using the GDB/MI machine
+// interface of gdb, for debugging C and C++ programs.
+package gidegdb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/ki/ints"
+)
+
+// GiGdb is the gdb implementation of the GiDebug interface
+type GiGdb struct {
+	path     string                    // path to exe
+	rootPath string                    // root path for project
+	cmd      *exec.Cmd                 // command running gdb
+	stdin    io.WriteCloser            // gdb input, for MI commands
+	obuf     *giv.TextBuf              // output buffer
+	statFunc func(stat gidebug.Status) // status function
+	params   gidebug.Params            // local copy of initial params
+	mu       sync.Mutex                // protects the following
+	ready    bool                      // gdb is set up and ready for commands
+	started  bool                      // the process has been started (run), for Exec mode
+	running  bool                      // the process is running
+	token    int                       // token of the last command sent
+	waits    map[int]chan *MIRecord    // result channels of commands waiting for results, by token
+	capture  *strings.Builder          // if non-nil, console stream output is captured here, for Command
+	traces   map[int][]string          // trace expressions of the tracepoints, by breakpoint id
+	stopc    chan *MIRecord            // *stopped records
+}
+
+// MITimeout is how long to wait for the result of a GDB/MI command
+var MITimeout = 30 * time.Second
+
+// NewGiGdb creates a new gdb debugger
+// for given path, and project root path
+func NewGiGdb(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) (*GiGdb, error) {
+	gd := &GiGdb{}
+	err := gd.Start(path, rootPath, outbuf, pars)
+	return gd, err
+}
+
+func (gd *GiGdb) HasTasks() bool {
+	return false
+}
+
+func (gd *GiGdb) WriteToConsole(msg string) {
+	if gd.obuf == nil {
+		log.Println(msg)
+		return
+	}
+	gd.obuf.AppendText([]byte(msg), true)
+}
+
+func (gd *GiGdb) LogErr(err error) error {
+	if err == nil {
+		return err
+	}
+	gd.WriteToConsole(err.Error() + "\n")
+	return err
+}
+
+func (gd *GiGdb) SetParams(params *gidebug.Params) {
+	gd.params = *params
+}
+
+// StartedCheck checks that gdb is running properly
+func (gd *GiGdb) StartedCheck() error {
+	if !gd.IsActive() {
+		err := gidebug.NotStartedErr
+		return gd.LogErr(err)
+	}
+	return nil
+}
+
+// Start starts gdb for a given exe path -- it is set up in the
+// background, and the status function is called with Ready when done.
+func (gd *GiGdb) Start(path, rootPath string, outbuf *giv.TextBuf, pars *gidebug.Params) error {
+	gd.path = path
+	gd.rootPath = rootPath
+	gd.obuf = outbuf
+	gd.params = *pars
+	gd.statFunc = pars.StatFunc
+	gd.waits = make(map[int]chan *MIRecord)
+	gd.traces = make(map[int][]string)
+	gd.stopc = make(chan *MIRecord, 100)
+	if gd.params.Mode == gidebug.Test {
+		gd.setStatus(gidebug.Error)
+		return gd.LogErr(fmt.Errorf("gdb: Test mode is %w", gidebug.NotSupportedErr))
+	}
+	gd.cmd = exec.Command("gdb", gd.gdbArgs(path)...)
+	if len(gd.params.Env) > 0 {
+		gd.cmd.Env = append(os.Environ(), gd.params.Env...)
+	}
+	gd.cmd.Dir = filepath.Dir(path)
+	if gd.params.WorkDir != "" {
+		gd.cmd.Dir = gd.params.WorkDir
+	}
+	var err error
+	gd.stdin, err = gd.cmd.StdinPipe()
+	if err == nil {
+		var stdout io.ReadCloser
+		stdout, err = gd.cmd.StdoutPipe()
+		if err == nil {
+			gd.cmd.Stderr = gd.cmd.Stdout
+			err = gd.cmd.Start()
+			if err == nil {
+				go gd.monitorOutput(stdout)
+				go gd.setup()
+			}
+		}
+	}
+	if err != nil {
+		gd.setStatus(gidebug.Error)
+		return gd.LogErr(err)
+	}
+	return nil
+}
+
+// gdbArgs returns the args for running gdb on given exe path, according
+// to the params
+func (gd *GiGdb) gdbArgs(path string) []string {
+	targs := []string{"--interpreter=mi2", "--quiet", "--nx"}
+	for _, a := range gd.params.Args {
+		if a == "--" {
+			break
+		}
+		targs = append(targs, a)
+	}
+	if gd.params.Mode != gidebug.Remote || path != "" {
+		targs = append(targs, path)
+	}
+	return targs
+}
+
+// progArgs returns the args to pass to the program: any after -- in the
+// extra args, followed by the launch configuration program args
+func (gd *GiGdb) progArgs() []string {
+	var pargs []string
+	for i, a := range gd.params.Args {
+		if a == "--" {
+			pargs = append(pargs, gd.params.Args[i+1:]...)
+			break
+		}
+	}
+	return append(pargs, gd.params.ProgArgs...)
+}
+
+// setup sets up gdb according to the mode, and then sets status to Ready
+func (gd *GiGdb) setup() {
+	cmds := []string{"-gdb-set confirm off", "-gdb-set pagination off", "-gdb-set print pretty off", "-enable-pretty-printing"}
+	switch gd.params.Mode {
+	case gidebug.Exec:
+		if pargs := gd.progArgs(); len(pargs) > 0 {
+			cmds = append(cmds, "-exec-arguments "+strings.Join(pargs, " "))
+		}
+		if gd.params.WorkDir != "" {
+			cmds = append(cmds, "-environment-cd "+MIQuote(gd.params.WorkDir))
+		}
+	case gidebug.Attach:
+		cmds = append(cmds, fmt.Sprintf("-target-attach %d", gd.params.PID))
+	case gidebug.Core:
+		cmds = append(cmds, "-target-select core "+MIQuote(gd.params.CoreFile))
+	case gidebug.Remote:
+		addr := gd.params.Addr
+		if addr == "" {
+			addr = "127.0.0.1:2345"
+		}
+		cmds = append(cmds, "-target-select remote "+addr)
+	}
+	for _, c := range cmds {
+		if _, err := gd.mi(c); err != nil {
+			gd.LogErr(err)
+			if !strings.HasPrefix(c, "-gdb-set") && !strings.HasPrefix(c, "-enable") {
+				gd.setStatus(gidebug.Error)
+				return
+			}
+		}
+	}
+	gd.mu.Lock()
+	gd.ready = true
+	gd.started = gd.params.Mode != gidebug.Exec
+	gd.mu.Unlock()
+	// stops from attaching etc are not needed
+	gd.drainStops()
+	gd.setStatus(gidebug.Ready)
+}
+
+func (gd *GiGdb) setStatus(stat gidebug.Status) {
+	if gd.statFunc != nil {
+		gd.statFunc(stat)
+	}
+}
+
+// monitorOutput reads the output of gdb, dispatching the MI records, and
+// writing everything else (i.e., output of the program) to the console
+func (gd *GiGdb) monitorOutput(out io.Reader) {
+	sc := bufio.NewScanner(out)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		ln := strings.TrimRight(sc.Text(), "\r")
+		if strings.HasPrefix(ln, "(gdb)") {
+			continue
+		}
+		mr, err := ParseMIRecord(ln)
+		if err != nil {
+			gd.WriteToConsole(ln + "\n")
+			continue
+		}
+		gd.dispatch(mr)
+	}
+	gd.mu.Lock()
+	gd.ready = false
+	for tok, wc := range gd.waits {
+		close(wc)
+		delete(gd.waits, tok)
+	}
+	gd.mu.Unlock()
+	close(gd.stopc)
+}
+
+// dispatch handles given MI record
+func (gd *GiGdb) dispatch(mr *MIRecord) {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	switch mr.Kind {
+	case '^':
+		if mr.Class == "running" {
+			gd.running = true
+		}
+		if wc, has := gd.waits[mr.Token]; has {
+			wc <- mr
+			delete(gd.waits, mr.Token)
+		}
+	case '*':
+		switch mr.Class {
+		case "running":
+			gd.running = true
+		case "stopped":
+			gd.running = false
+			gd.stopc <- mr
+		}
+	case '~', '@':
+		if gd.capture != nil && mr.Kind == '~' {
+			gd.capture.WriteString(mr.Text)
+		} else {
+			gd.WriteToConsole(mr.Text)
+		}
+	case '&':
+		if gd.capture == nil {
+			gd.WriteToConsole(mr.Text)
+		}
+	}
+}
+
+// mi sends given MI command to gdb, and waits for its result record,
+// returning an error if it is an error result
+func (gd *GiGdb) mi(cmd string) (*MIRecord, error) {
+	gd.mu.Lock()
+	if gd.stdin == nil {
+		gd.mu.Unlock()
+		return nil, gidebug.NotStartedErr
+	}
+	gd.token++
+	tok := gd.token
+	wc := make(chan *MIRecord, 1)
+	gd.waits[tok] = wc
+	_, err := fmt.Fprintf(gd.stdin, "%d%s\n", tok, cmd)
+	gd.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case mr, ok := <-wc:
+		if !ok {
+			return nil, fmt.Errorf("gdb exited")
+		}
+		if mr.Class == "error" {
+			return mr, fmt.Errorf("%s", mr.Vals.Str("msg"))
+		}
+		return mr, nil
+	case <-time.After(MITimeout):
+		gd.mu.Lock()
+		delete(gd.waits, tok)
+		gd.mu.Unlock()
+		return nil, fmt.Errorf("gdb: timeout waiting for result of: %s", cmd)
+	}
+}
+
+// drainStops discards any pending stop records
+func (gd *GiGdb) drainStops() {
+	for {
+		select {
+		case <-gd.stopc:
+		default:
+			return
+		}
+	}
+}
+
+// exec sends given command that runs the process, and waits for it to stop,
+// returning the resulting state
+func (gd *GiGdb) exec(cmd string) (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.drainStops()
+	if _, err := gd.mi(cmd); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	mr, ok := <-gd.stopc
+	if !ok {
+		return &gidebug.State{Exited: true}, nil
+	}
+	return gd.cvtStopped(mr), nil
+}
+
+// IsActive returns whether debugger is active and ready for commands
+func (gd *GiGdb) IsActive() bool {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	return gd.cmd != nil && gd.ready
+}
+
+// Returns the pid of the process we are debugging.
+func (gd *GiGdb) ProcessPid() int {
+	if err := gd.StartedCheck(); err != nil {
+		return -1
+	}
+	mr, err := gd.mi("-list-thread-groups")
+	if err != nil {
+		return -1
+	}
+	for _, tg := range mr.Vals.List("groups").Tuples() {
+		if pid := tg.Int("pid"); pid > 0 {
+			return pid
+		}
+	}
+	return -1
+}
+
+// LastModified returns the time that the process' executable was modified.
+func (gd *GiGdb) LastModified() time.Time {
+	fi, err := os.Stat(gd.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// Detach detaches the debugger, optionally killing the process, and exits gdb.
+func (gd *GiGdb) Detach(killProcess bool) error {
+	var err error
+	if gd.IsActive() {
+		if gd.isRunning() {
+			gd.mi("-exec-interrupt")
+		}
+		if killProcess {
+			gd.mi("-interpreter-exec console kill")
+		} else if gd.params.Mode == gidebug.Remote {
+			gd.mi("-target-disconnect")
+		} else {
+			gd.mi("-target-detach")
+		}
+		gd.mi("-gdb-exit")
+	}
+	gd.mu.Lock()
+	gd.ready = false
+	gd.mu.Unlock()
+	if gd.cmd != nil && gd.cmd.Process != nil { // make sure it dies!
+		err = gd.cmd.Process.Kill()
+	}
+	return err
+}
+
+// Disconnect disconnects from the target without detaching, for Remote mode.
+func (gd *GiGdb) Disconnect(cont bool) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	if cont {
+		gd.mi("-exec-continue")
+	}
+	_, err := gd.mi("-target-disconnect")
+	return gd.LogErr(err)
+}
+
+// Restart restarts the program, which is stopped at the start of main.
+func (gd *GiGdb) Restart() error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	if gd.params.Mode != gidebug.Exec {
+		return gidebug.NotSupportedErr
+	}
+	if gd.isRunning() {
+		gd.mi("-exec-interrupt")
+	}
+	_, err := gd.exec("-exec-run --start")
+	if err == nil {
+		gd.mu.Lock()
+		gd.started = true
+		gd.mu.Unlock()
+	}
+	return gd.LogErr(err)
+}
+
+func (gd *GiGdb) isRunning() bool {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	return gd.running
+}
+
+func (gd *GiGdb) isStarted() bool {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	return gd.started
+}
+
+// GetState returns the current debugger state.
+// This will return immediately -- if the target is running then
+// the Running flag will be set.
+func (gd *GiGdb) GetState() (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if gd.isRunning() {
+		return &gidebug.State{Running: true}, nil
+	}
+	ds := &gidebug.State{}
+	if !gd.isStarted() {
+		return ds, nil
+	}
+	mr, err := gd.mi("-thread-info")
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	cid := mr.Vals.Int("current-thread-id")
+	for _, tt := range mr.Vals.List("threads").Tuples() {
+		if tt.Int("id") == cid {
+			ds.Thread = *gd.cvtThread(tt)
+		}
+	}
+	return ds, nil
+}
+
+// Continue resumes process execution -- for Exec mode, the first one
+// runs the program.  Tracepoints are continued automatically, with each
+// hit sent as a State.
+func (gd *GiGdb) Continue(all *gidebug.AllState) <-chan *gidebug.State {
+	if err := gd.StartedCheck(); err != nil {
+		return nil
+	}
+	sc := make(chan *gidebug.State)
+	go func() {
+		defer close(sc)
+		cmd := "-exec-continue"
+		if !gd.isStarted() {
+			cmd = "-exec-run"
+			gd.mu.Lock()
+			gd.started = true
+			gd.mu.Unlock()
+		}
+		for {
+			ds, err := gd.exec(cmd)
+			if err != nil {
+				return
+			}
+			if ds.Exited {
+				gd.mu.Lock()
+				gd.started = gd.params.Mode != gidebug.Exec
+				gd.mu.Unlock()
+			}
+			tvars, isTrace := gd.traceExprs(ds.CurTrace)
+			if ds.Exited || !isTrace {
+				ds.CurTrace = 0
+				sc <- ds
+				return
+			}
+			ds.Trace = gd.traceEvent(ds, tvars)
+			gd.WriteToConsole(fmt.Sprintf("Trace: %d File: %s:%d %s\n", ds.CurTrace, ds.Trace.File, ds.Trace.Line, ds.Trace.Value))
+			sc <- ds
+			cmd = "-exec-continue"
+		}
+	}()
+	return sc
+}
+
+// traceEvent returns the record of a tracepoint hit at given state,
+// evaluating given trace expressions
+func (gd *GiGdb) traceEvent(ds *gidebug.State, tvars []string) *gidebug.TraceEvent {
+	te := &gidebug.TraceEvent{Time: time.Now(), Break: ds.CurTrace, Task: ds.Thread.ID}
+	te.File = ds.Thread.File
+	te.Line = ds.Thread.Line
+	te.FPath = ds.Thread.FPath
+	te.Func = ds.Thread.Func
+	for _, ex := range tvars {
+		val := ""
+		if vr, err := gd.GetVar(ex, ds.Thread.ID, 0); err == nil {
+			val = vr.Value
+		}
+		te.Vals = append(te.Vals, gidebug.TraceVal{Expr: ex, Value: val})
+	}
+	te.SetValue()
+	return te
+}
+
+// StepOver continues to the next source line, not entering function calls.
+func (gd *GiGdb) StepOver() (*gidebug.State, error) {
+	return gd.exec("-exec-next")
+}
+
+// StepInto continues to the next source line, entering function calls.
+func (gd *GiGdb) StepInto() (*gidebug.State, error) {
+	return gd.exec("-exec-step")
+}
+
+// StepOut continues to the return address of the current function
+func (gd *GiGdb) StepOut() (*gidebug.State, error) {
+	return gd.exec("-exec-finish")
+}
+
+// StepSingle steps a single cpu instruction.
+func (gd *GiGdb) StepSingle() (*gidebug.State, error) {
+	return gd.exec("-exec-next-instruction")
+}
+
+// Call calls a function, given as an expression (e.g., f(x)), on given
+// thread, with the return value in State.Returns.
+func (gd *GiGdb) Call(threadID int, expr string, unsafe bool) (*gidebug.State, error) {
+	vr, err := gd.GetVar(expr, threadID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := gd.GetState()
+	if err != nil {
+		return nil, err
+	}
+	ds.Returns = []*gidebug.Variable{vr}
+	return ds, nil
+}
+
+// SwitchThread switches the current thread context.
+func (gd *GiGdb) SwitchThread(threadID int) (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if _, err := gd.mi(fmt.Sprintf("-thread-select %d", threadID)); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return gd.GetState()
+}
+
+// SwitchTask is not supported: gdb does not have tasks.
+func (gd *GiGdb) SwitchTask(threadID int) (*gidebug.State, error) {
+	return nil, gidebug.NotSupportedErr
+}
+
+// Stop suspends the process -- the Continue channel gets the stopped state.
+func (gd *GiGdb) Stop() (*gidebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if !gd.isRunning() {
+		return gd.GetState()
+	}
+	_, err := gd.mi("-exec-interrupt")
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return &gidebug.State{Running: true}, nil
+}
+
+// GetBreak gets info about a breakpoint by ID.
+func (gd *GiGdb) GetBreak(id int) (*gidebug.Break, error) {
+	bks, err := gd.ListBreaks()
+	if err != nil {
+		return nil, err
+	}
+	br, _ := gidebug.BreakByID(bks, id)
+	if br == nil {
+		return nil, fmt.Errorf("breakpoint %d not found", id)
+	}
+	return br, nil
+}
+
+// insertBreak inserts a breakpoint at given gdb location
+func (gd *GiGdb) insertBreak(loc string, kind gidebug.BreakKinds) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi("-break-insert -f " + MIQuote(loc))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	br := gd.cvtBreak(mr.Vals.Tuple("bkpt"))
+	br.Kind = kind
+	return br, nil
+}
+
+// SetBreak sets a new breakpoint at given file and line number
+func (gd *GiGdb) SetBreak(fname string, line int) (*gidebug.Break, error) {
+	return gd.insertBreak(fmt.Sprintf("%s:%d", gd.remotePath(fname), line), gidebug.BreakLine)
+}
+
+// SetBreakPC sets a new instruction-level breakpoint at given address
+func (gd *GiGdb) SetBreakPC(pc uint64) (*gidebug.Break, error) {
+	return gd.insertBreak(fmt.Sprintf("*%#x", pc), gidebug.BreakAddr)
+}
+
+// SetBreakFunc sets a new breakpoint at the start of given function
+func (gd *GiGdb) SetBreakFunc(fname string) (*gidebug.Break, error) {
+	br, err := gd.insertBreak(fname, gidebug.BreakFunc)
+	if br != nil {
+		br.Expr = fname
+	}
+	return br, err
+}
+
+// SetWatch sets a new data watchpoint on given variable expression,
+// which stops when it is written.
+func (gd *GiGdb) SetWatch(expr string) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi("-break-watch " + MIQuote(expr))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	wt := mr.Vals.Tuple("wpt")
+	br := &gidebug.Break{ID: wt.Int("number"), On: true, Kind: gidebug.BreakWatch, Expr: expr}
+	return br, nil
+}
+
+// ListBreaks gets all breakpoints.
+func (gd *GiGdb) ListBreaks() ([]*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi("-break-list")
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	bts := mr.Vals.Tuple("BreakpointTable").List("body").Tuples()
+	bks := make([]*gidebug.Break, 0, len(bts))
+	for _, bt := range bts {
+		bks = append(bks, gd.cvtBreak(bt))
+	}
+	return bks, nil
+}
+
+// ClearBreak deletes a breakpoint by ID.
+func (gd *GiGdb) ClearBreak(id int) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	delete(gd.traces, id)
+	gd.mu.Unlock()
+	_, err := gd.mi(fmt.Sprintf("-break-delete %d", id))
+	return gd.LogErr(err)
+}
+
+// AmendBreak updates the Condition and Trace information
+// for the given breakpoint.  Tracepoints are breakpoints that are
+// continued automatically, after evaluating the trace expressions.
+func (gd *GiGdb) AmendBreak(id int, fname string, line int, cond string, trace bool, traceVars []string) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	if trace {
+		gd.traces[id] = traceVars
+	} else {
+		delete(gd.traces, id)
+	}
+	gd.mu.Unlock()
+	_, err := gd.mi(fmt.Sprintf("-break-condition %d %s", id, cond))
+	return gd.LogErr(err)
+}
+
+// UpdateBreaks updates current breakpoints based on given list of breakpoints.
+// first gets the current list, and does actions to ensure that the list is set.
+func (gd *GiGdb) UpdateBreaks(brk *[]*gidebug.Break) error {
+	cb, err := gd.ListBreaks()
+	if err != nil {
+		return err
+	}
+	for _, b := range *brk {
+		var c *gidebug.Break
+		ci := -1
+		switch b.Kind {
+		case gidebug.BreakAddr:
+			c, ci = gidebug.BreakByPC(cb, b.PC)
+		case gidebug.BreakLine:
+			c, ci = gidebug.BreakByFile(cb, b.FPath, b.Line)
+		default:
+			if b.ID > 0 {
+				c, ci = gidebug.BreakByID(cb, b.ID)
+			}
+		}
+		if c != nil {
+			cb = append(cb[:ci], cb[ci+1:]...)
+			if !b.On {
+				gd.ClearBreak(c.ID)
+				continue
+			}
+		} else {
+			if !b.On {
+				continue
+			}
+			switch b.Kind {
+			case gidebug.BreakAddr:
+				c, err = gd.SetBreakPC(b.PC)
+			case gidebug.BreakFunc:
+				c, err = gd.SetBreakFunc(b.Expr)
+			case gidebug.BreakWatch:
+				c, err = gd.SetWatch(b.Expr)
+			default:
+				c, err = gd.SetBreak(b.FPath, b.Line)
+			}
+			if err != nil || c == nil { // e.g., watch not in scope: stays on, retried next update
+				b.ID = 0
+				continue
+			}
+		}
+		if b.Cond != c.Cond || b.Trace != c.Trace || b.TraceVars != c.TraceVars {
+			gd.AmendBreak(c.ID, c.FPath, c.Line, b.Cond, b.Trace, gidebug.TraceExprs(b.TraceVars))
+		}
+		b.ID = c.ID
+		if b.Kind != gidebug.BreakWatch {
+			b.PC = c.PC
+			b.Func = c.Func
+		}
+		if b.Kind != gidebug.BreakLine && c.FPath != "" {
+			b.FPath = c.FPath
+			b.File = c.File
+			b.Line = c.Line
+		}
+	}
+	for _, c := range cb { // any we didn't set, e.g., from raw commands
+		if c.ID <= 0 {
+			continue
+		}
+		*brk = append(*brk, c)
+	}
+	gidebug.SortBreaks(*brk)
+	return nil
+}
+
+// CancelNext does nothing: gdb does not have pending next operations.
+func (gd *GiGdb) CancelNext() error {
+	return nil
+}
+
+// InitAllState initializes the given AllState with relevant info for
+// current state of things.  Does Not get AllVars
+func (gd *GiGdb) InitAllState(all *gidebug.AllState) error {
+	bs, err := gd.GetState()
+	if err != nil {
+		return err
+	}
+	if bs.Running {
+		all.State.Running = true
+		err = gidebug.IsRunningErr
+		return gd.LogErr(err)
+	}
+	all.State = *bs
+	all.CurThread = all.State.Thread.ID
+	all.CurTask = 0
+	all.CurFrame = 0
+	all.Tasks = nil
+	all.Threads = nil
+	all.Stack = nil
+	all.Vars = nil
+	all.CurBreak = 0
+	if !gd.isStarted() || bs.Exited {
+		return nil
+	}
+	th, err := gd.ListThreads()
+	if err != nil {
+		return err
+	}
+	all.Threads = th
+	sf, err := gd.Stack(all.CurThread, 100)
+	if err != nil {
+		return err
+	}
+	all.Stack = sf
+	vr, err := gd.ListVars(all.CurThread, 0)
+	if err != nil {
+		return err
+	}
+	all.Vars = vr
+	if cf := all.StackFrame(0); cf != nil {
+		bk, _ := gidebug.BreakByFile(all.Breaks, cf.FPath, cf.Line)
+		if bk != nil {
+			all.CurBreak = bk.ID
+		}
+	}
+	return nil
+}
+
+// UpdateAllState updates the state for given threadId and
+// frame number (only info different from current results is updated).
+func (gd *GiGdb) UpdateAllState(all *gidebug.AllState, threadID int, frame int) error {
+	updt := false
+	if threadID != all.CurThread {
+		updt = true
+		all.CurThread = threadID
+		sf, err := gd.Stack(all.CurThread, 100)
+		if err != nil {
+			return err
+		}
+		all.Stack = sf
+	}
+	if updt || all.CurFrame != frame {
+		all.CurFrame = frame
+		vr, err := gd.ListVars(all.CurThread, all.CurFrame)
+		if err != nil {
+			return err
+		}
+		all.Vars = vr
+	}
+	return nil
+}
+
+// FindFrames looks through the Stacks of all Threads
+// for the closest Stack Frame to given file and line number.
+// Results are sorted by line number proximity to given line.
+func (gd *GiGdb) FindFrames(all *gidebug.AllState, fname string, line int) ([]*gidebug.Frame, error) {
+	var err error
+	var fr []*gidebug.Frame
+	for _, th := range all.Threads {
+		sf, err := gd.Stack(th.ID, 100)
+		if err != nil {
+			break
+		}
+		for _, f := range sf {
+			if f.FPath != fname {
+				continue
+			}
+			fr = append(fr, f)
+			break
+		}
+	}
+	sort.Slice(fr, func(i, j int) bool {
+		dsti := ints.AbsInt(fr[i].Line - line)
+		dstj := ints.AbsInt(fr[j].Line - line)
+		return dsti < dstj
+	})
+	return fr, err
+}
+
+// CurThreadID returns the current thread, as gdb does not have tasks.
+func (gd *GiGdb) CurThreadID(all *gidebug.AllState) int {
+	return all.CurThread
+}
+
+// ListThreads lists all threads.
+func (gd *GiGdb) ListThreads() ([]*gidebug.Thread, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi("-thread-info")
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	tts := mr.Vals.List("threads").Tuples()
+	ths := make([]*gidebug.Thread, len(tts))
+	for i, tt := range tts {
+		ths[i] = gd.cvtThread(tt)
+	}
+	return ths, nil
+}
+
+// GetThread gets a thread by its ID.
+func (gd *GiGdb) GetThread(id int) (*gidebug.Thread, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-thread-info %d", id))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	tts := mr.Vals.List("threads").Tuples()
+	if len(tts) == 0 {
+		return nil, fmt.Errorf("thread %d not found", id)
+	}
+	return gd.cvtThread(tts[0]), nil
+}
+
+// ListTasks returns nothing: gdb does not have tasks.
+func (gd *GiGdb) ListTasks() ([]*gidebug.Task, error) {
+	return nil, nil
+}
+
+// Stack returns the stack of given thread, up to given depth.
+func (gd *GiGdb) Stack(threadID int, depth int) ([]*gidebug.Frame, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-stack-list-frames --thread %d 0 %d", threadID, depth-1))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	fts := mr.Vals.List("stack").Tuples()
+	frs := make([]*gidebug.Frame, len(fts))
+	for i, ft := range fts {
+		frs[i] = gd.cvtFrame(ft, threadID)
+	}
+	return frs, nil
+}
+
+// ListGlobalVars lists global variables (subject to filter regexp) in the
+// context of the current thread.  Requires gdb 10 or later.
+func (gd *GiGdb) ListGlobalVars(filter string) ([]*gidebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	cmd := "-symbol-info-variables"
+	if filter != "" {
+		cmd += " --name " + MIQuote(filter)
+	}
+	mr, err := gd.mi(cmd)
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	var vrs []*gidebug.Variable
+	for _, ft := range mr.Vals.Tuple("symbols").List("debug").Tuples() {
+		for _, st := range ft.List("symbols").Tuples() {
+			vr := &gidebug.Variable{}
+			vr.InitName(vr, st.Str("name"))
+			gd.setVarType(vr, st.Str("type"))
+			vr.Loc.Line = st.Int("line")
+			vr.Loc.FPath = gd.localPath(ft.Str("fullname"))
+			vr.Dbg = gd
+			if vmr, err := gd.mi("-data-evaluate-expression " + MIQuote(vr.Nm)); err == nil {
+				vr.Value = vmr.Vals.Str("value")
+				vr.ElValue = vr.Value
+			}
+			vrs = append(vrs, vr)
+		}
+	}
+	gidebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// ListVars lists all stack-frame local variables (including args)
+// for given thread and frame number.
+func (gd *GiGdb) ListVars(threadID int, frame int) ([]*gidebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-stack-list-variables --thread %d --frame %d --all-values", threadID, frame))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	vts := mr.Vals.List("variables").Tuples()
+	vrs := make([]*gidebug.Variable, len(vts))
+	for i, vt := range vts {
+		vr := &gidebug.Variable{}
+		vr.InitName(vr, vt.Str("name"))
+		vr.Value = vt.Str("value")
+		vr.ElValue = vr.Value
+		vr.Dbg = gd
+		if tmr, err := gd.mi(fmt.Sprintf("-var-create --thread %d --frame %d - * %s", threadID, frame, MIQuote(vr.Nm))); err == nil {
+			gd.setVarType(vr, tmr.Vals.Str("type"))
+			gd.mi("-var-delete " + tmr.Vals.Str("name"))
+		}
+		vrs[i] = vr
+	}
+	gidebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// GetVar returns a variable for given thread and frame number, from given
+// expression, with its children filled in using gdb variable objects.
+func (gd *GiGdb) GetVar(expr string, threadID int, frame int) (*gidebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-var-create --thread %d --frame %d - * %s", threadID, frame, MIQuote(expr)))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	vo := mr.Vals.Str("name")
+	defer gd.mi("-var-delete " + vo)
+	vr := gd.cvtVarObj(mr.Vals, expr)
+	if amr, err := gd.mi(fmt.Sprintf("-data-evaluate-expression --thread %d --frame %d %s", threadID, frame, MIQuote("&("+expr+")"))); err == nil {
+		vr.Addr = uintptr(parseAddr(amr.Vals.Str("value")))
+	}
+	gd.varChildren(vr, vo, 1)
+	return vr, nil
+}
+
+// varChildren adds the children of given variable, from gdb variable
+// object vo, up to the max recursion depth of the params
+func (gd *GiGdb) varChildren(vr *gidebug.Variable, vo string, depth int) {
+	if depth > gd.params.GetVar.MaxRecurse {
+		return
+	}
+	mr, err := gd.mi("-var-list-children --all-values " + vo)
+	if err != nil {
+		return
+	}
+	for _, ct := range mr.Vals.List("children").Tuples() {
+		exp := ct.Str("exp")
+		if ct.Str("type") == "" && (exp == "public" || exp == "private" || exp == "protected") {
+			gd.varChildren(vr, ct.Str("name"), depth) // C++ access specifiers
+			continue
+		}
+		cv := gd.cvtVarObj(ct, exp)
+		vr.AddChild(cv)
+		if ct.Int("numchild") > 0 {
+			gd.varChildren(cv, ct.Str("name"), depth+1)
+		}
+	}
+}
+
+// FollowPtr fills in the Child of given pointer Variable
+// with the value it points to.
+func (gd *GiGdb) FollowPtr(vr *gidebug.Variable) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	typ := vr.FullTypeStr
+	if !strings.HasSuffix(typ, "*") {
+		typ += " *"
+	}
+	ch, err := gd.GetVar(fmt.Sprintf("*(%s)%#x", typ, vr.Addr), 0, 0)
+	if err != nil {
+		return gd.LogErr(err)
+	}
+	ch.SetName("*" + vr.Nm)
+	vr.AddChild(ch)
+	return nil
+}
+
+// SetVar sets the value of a variable, for given thread and frame number.
+func (gd *GiGdb) SetVar(name, value string, threadID int, frame int) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	_, err := gd.mi(fmt.Sprintf("-data-evaluate-expression --thread %d --frame %d %s", threadID, frame, MIQuote(name+" = "+value)))
+	return gd.LogErr(err)
+}
+
+// Disassemble returns the instructions of the function containing given
+// address, for given thread and frame number.
+func (gd *GiGdb) Disassemble(threadID int, frame int, pc uint64) ([]*gidebug.Instr, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-data-disassemble -a %#x -- 1", pc))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	bks, _ := gd.ListBreaks()
+	var ins []*gidebug.Instr
+	for _, st := range mr.Vals.List("asm_insns").Tuples() {
+		fpath := gd.localPath(st.Str("fullname"))
+		line := st.Int("line")
+		for _, it := range st.List("line_asm_insn").Tuples() {
+			in := &gidebug.Instr{}
+			in.PC = it.Addr("address")
+			in.Text = it.Str("inst")
+			in.FPath = fpath
+			in.File = giv.RelFilePath(fpath, gd.rootPath)
+			in.Line = line
+			in.Func = it.Str("func-name")
+			in.Dest = callDest(in.Text)
+			in.AtPC = in.PC == pc
+			if br, _ := gidebug.BreakByPC(bks, in.PC); br != nil {
+				in.Break = true
+			}
+			ins = append(ins, in)
+		}
+	}
+	return ins, nil
+}
+
+// callDestRe matches the destination function of a call instruction
+var callDestRe = regexp.MustCompile(`^call\w*\s+\S+\s+<([^>+]+)`)
+
+// callDest returns the destination function of given call instruction
+func callDest(inst string) string {
+	m := callDestRe.FindStringSubmatch(inst)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// ExamineMemory returns the raw memory of the process, of given length
+// starting at given address.
+func (gd *GiGdb) ExamineMemory(addr uint64, length int) ([]byte, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi(fmt.Sprintf("-data-read-memory-bytes %#x %d", addr, length))
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	var mem []byte
+	for _, mt := range mr.Vals.List("memory").Tuples() {
+		hx := mt.Str("contents")
+		for i := 0; i+1 < len(hx); i += 2 {
+			var b byte
+			fmt.Sscanf(hx[i:i+2], "%02x", &b)
+			mem = append(mem, b)
+		}
+	}
+	return mem, nil
+}
+
+// ListSources lists all source files in the process matching filter regexp.
+func (gd *GiGdb) ListSources(filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+	mr, err := gd.mi("-file-list-exec-source-files")
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	var fs []string
+	for _, ft := range mr.Vals.List("files").Tuples() {
+		fp := gd.localPath(ft.Str("fullname"))
+		if fp != "" && re.MatchString(fp) {
+			fs = append(fs, fp)
+		}
+	}
+	return fs, nil
+}
+
+// listSymbols lists the names of the symbols from given gdb symbol-info
+// command, matching filter regexp.  Requires gdb 10 or later.
+func (gd *GiGdb) listSymbols(cmd, filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if filter != "" {
+		cmd += " --name " + MIQuote(filter)
+	}
+	mr, err := gd.mi(cmd)
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	var nms []string
+	for _, ft := range mr.Vals.Tuple("symbols").List("debug").Tuples() {
+		for _, st := range ft.List("symbols").Tuples() {
+			nm := st.Str("name")
+			if nm == "" {
+				nm = st.Str("type")
+			}
+			nms = append(nms, nm)
+		}
+	}
+	sort.Strings(nms)
+	return nms, nil
+}
+
+// ListFuncs lists all functions in the process matching filter regexp.
+func (gd *GiGdb) ListFuncs(filter string) ([]string, error) {
+	return gd.listSymbols("-symbol-info-functions", filter)
+}
+
+// ListTypes lists all types in the process matching filter regexp.
+func (gd *GiGdb) ListTypes(filter string) ([]string, error) {
+	return gd.listSymbols("-symbol-info-types", filter)
+}
+
+// Commands are the raw gdb console commands supported by Command, with
+// their help.  Commands that run the process (run, continue, next, step etc)
+// or change the selected frame are not included, as those must go through
+// the debugger toolbar to keep the view in sync.  The breakpoint commands
+// are not supported for a core dump.
+var Commands = [][2]string{
+	{"break <loc>", "set a breakpoint at a location: func, file:line, or *address"},
+	{"tbreak <loc>", "set a temporary breakpoint, deleted when hit"},
+	{"watch <expr>", "set a watchpoint, stopping when expr is written"},
+	{"delete [id]", "delete breakpoint with given id, or all breakpoints"},
+	{"clear <loc>", "delete the breakpoints at a location"},
+	{"info <what>", "show info about the program, e.g., info breakpoints, info threads, info registers"},
+	{"backtrace [n]", "print the stack of the current thread"},
+	{"print[/fmt] <expr>", "evaluate an expression, in given format, e.g., print/x"},
+	{"x[/fmt] <addr>", "examine memory at an address, in given format, e.g., x/8xb"},
+	{"ptype <expr>", "print the type of an expression"},
+	{"whatis <expr>", "print the type name of an expression"},
+	{"list [loc]", "list source lines around a location"},
+	{"disassemble [loc]", "disassemble the function at a location"},
+	{"show <setting>", "show a gdb setting"},
+	{"help", "list the available commands"},
+}
+
+// Command executes a raw gdb console command, for given thread and frame
+// number, returning its output.  Only the Commands are supported.
+func (gd *GiGdb) Command(cmd string, threadID int, frame int) (string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return "", err
+	}
+	flds := strings.Fields(cmd)
+	if len(flds) == 0 {
+		return "", nil
+	}
+	name := flds[0]
+	if i := strings.Index(name, "/"); i > 0 { // format, as in print/x or x/8xb
+		name = name[:i]
+	}
+	switch name {
+	case "break", "b", "br", "tbreak", "watch", "delete", "d", "clear":
+		if gd.params.Mode == gidebug.Core {
+			return "", fmt.Errorf("%s: cannot change the breakpoints of a core dump", name)
+		}
+	case "info", "i", "backtrace", "bt", "where", "print", "p", "x", "ptype", "whatis", "list", "l", "disassemble", "show":
+	case "help", "h":
+		var sb strings.Builder
+		for _, c := range Commands {
+			fmt.Fprintf(&sb, "%-20s %s\n", c[0], c[1])
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("unknown command: %s -- type help for the available commands", flds[0])
+	}
+	var sb strings.Builder
+	gd.mu.Lock()
+	gd.capture = &sb
+	gd.mu.Unlock()
+	_, err := gd.mi(fmt.Sprintf("-interpreter-exec --thread %d --frame %d console %s", threadID, frame, MIQuote(cmd)))
+	gd.mu.Lock()
+	gd.capture = nil
+	gd.mu.Unlock()
+	return sb.String(), err
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidegdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goki/gide/gidebug"
+)
+
+func TestGdbArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		params gidebug.Params
+		path   string
+		args   string
+		prog   string
+	}{
+		{"exec", gidebug.Params{Mode: gidebug.Exec}, "prog",
+			"--interpreter=mi2 --quiet --nx prog", ""},
+		{"extra args", gidebug.Params{Mode: gidebug.Exec, Args: []string{"-ex", "set foo", "--", "-v", "x"}}, "prog",
+			"--interpreter=mi2 --quiet --nx -ex set foo prog", "-v x"},
+		{"prog args after extra", gidebug.Params{Mode: gidebug.Exec, Args: []string{"--", "-v"}, ProgArgs: []string{"in.txt"}}, "prog",
+			"--interpreter=mi2 --quiet --nx prog", "-v in.txt"},
+		{"prog args only", gidebug.Params{Mode: gidebug.Exec, ProgArgs: []string{"in.txt"}}, "prog",
+			"--interpreter=mi2 --quiet --nx prog", "in.txt"},
+		{"remote without exe", gidebug.Params{Mode: gidebug.Remote}, "",
+			"--interpreter=mi2 --quiet --nx", ""},
+		{"remote with exe", gidebug.Params{Mode: gidebug.Remote}, "prog",
+			"--interpreter=mi2 --quiet --nx prog", ""},
+	}
+	for _, tt := range tests {
+		gd := &GiGdb{params: tt.params}
+		if got := strings.Join(gd.gdbArgs(tt.path), " "); got != tt.args {
+			t.Errorf("%s: gdb args were:\n%s\nwant:\n%s", tt.name, got, tt.args)
+		}
+		if got := strings.Join(gd.progArgs(), " "); got != tt.prog {
+			t.Errorf("%s: program args were: %q, want: %q", tt.name, got, tt.prog)
+		}
+	}
+}
+
+func TestCvtStopped(t *testing.T) {
+	gd := &GiGdb{rootPath: "/src", traces: map[int][]string{2: {"x"}}}
+	gd.params.PathMaps = gidebug.PathMaps{{Remote: "/build", Local: "/src"}}
+	frame := `frame={addr="0x1139",func="add",args=[],file="main.c",fullname="/build/main.c",line="7"},thread-id="3",stopped-threads="all"`
+	tests := []struct {
+		name   string
+		rec    string
+		exited bool
+		status int
+		trace  int
+		ret    string
+	}{
+		{"breakpoint", `*stopped,reason="breakpoint-hit",disp="keep",bkptno="1",` + frame, false, 0, 0, ""},
+		{"tracepoint", `*stopped,reason="breakpoint-hit",disp="keep",bkptno="2",` + frame, false, 0, 2, ""},
+		{"step", `*stopped,reason="end-stepping-range",` + frame, false, 0, 0, ""},
+		{"signal", `*stopped,reason="signal-received",signal-name="SIGSEGV",signal-meaning="Segmentation fault",` + frame, false, 0, 0, ""},
+		{"finish", `*stopped,reason="function-finished",gdb-result-var="$1",return-value="42",` + frame, false, 0, 0, "$1 = 42"},
+		{"exited normally", `*stopped,reason="exited-normally"`, true, 0, 0, ""},
+		{"exited", `*stopped,reason="exited",exit-code="010"`, true, 8, 0, ""},
+		{"exited signalled", `*stopped,reason="exited-signalled",signal-name="SIGABRT",signal-meaning="Aborted"`, true, -1, 0, ""},
+	}
+	for _, tt := range tests {
+		mr, err := ParseMIRecord(tt.rec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		st := gd.cvtStopped(mr)
+		if st.Exited != tt.exited || st.ExitStatus != tt.status || st.CurTrace != tt.trace {
+			t.Errorf("%s: state should be exited: %v status: %d trace: %d, was: %v %d %d", tt.name, tt.exited, tt.status, tt.trace, st.Exited, st.ExitStatus, st.CurTrace)
+		}
+		ret := ""
+		if len(st.Returns) > 0 {
+			ret = st.Returns[0].Nm + " = " + st.Returns[0].Value
+		}
+		if ret != tt.ret {
+			t.Errorf("%s: return value should be: %q, was: %q", tt.name, tt.ret, ret)
+		}
+		if tt.exited {
+			continue
+		}
+		th := st.Thread
+		if th.ID != 3 || th.PC != 0x1139 || th.FPath != "/src/main.c" || th.File != "main.c" || th.Line != 7 || th.Func != "add" {
+			t.Errorf("%s: thread should be mapped to the local path, was: %+v", tt.name, th)
+		}
+	}
+}
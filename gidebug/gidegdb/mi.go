@@ -0,0 +1,289 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidegdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file contains the parsing of GDB/MI output records, see:
+// https://sourceware.org/gdb/current/onlinedocs/gdb/GDB_002fMI-Output-Syntax.html
+
+// MIRecord is one output record from GDB/MI
+type MIRecord struct {
+	Token int     // token of the command this is the result of, -1 if none
+	Kind  byte    // ^ result, * exec async, + status async, = notify async, ~ console stream, @ target stream, & log stream
+	Class string  // result or async class, e.g., done, error, running, stopped
+	Vals  MITuple // the results
+	Text  string  // the text of stream records
+}
+
+// IsStream returns true if this is a stream record
+func (mr *MIRecord) IsStream() bool {
+	return mr.Kind == '~' || mr.Kind == '@' || mr.Kind == '&'
+}
+
+// MITuple is a tuple of named values: each value is a string, MITuple,
+// or MIList
+type MITuple map[string]interface{}
+
+// MIList is a list of values: for lists of results, the names are dropped
+type MIList []interface{}
+
+// Str returns the string value of given name, "" if none
+func (mt MITuple) Str(name string) string {
+	s, _ := mt[name].(string)
+	return s
+}
+
+// Int returns the int value of given name, 0 if none
+func (mt MITuple) Int(name string) int {
+	i, _ := strconv.Atoi(mt.Str(name))
+	return i
+}
+
+// Addr returns the address value of given name, in hex with 0x prefix
+// (or decimal), 0 if none
+func (mt MITuple) Addr(name string) uint64 {
+	a, _ := strconv.ParseUint(mt.Str(name), 0, 64)
+	return a
+}
+
+// Tuple returns the tuple value of given name, nil if none
+func (mt MITuple) Tuple(name string) MITuple {
+	t, _ := mt[name].(MITuple)
+	return t
+}
+
+// List returns the list value of given name, nil if none
+func (mt MITuple) List(name string) MIList {
+	l, _ := mt[name].(MIList)
+	return l
+}
+
+// Tuples returns the tuple values of the list, skipping any others
+func (ml MIList) Tuples() []MITuple {
+	ts := make([]MITuple, 0, len(ml))
+	for _, v := range ml {
+		if t, ok := v.(MITuple); ok {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
+
+// Strs returns the string values of the list, skipping any others
+func (ml MIList) Strs() []string {
+	ss := make([]string, 0, len(ml))
+	for _, v := range ml {
+		if s, ok := v.(string); ok {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// ParseMIRecord parses one line of GDB/MI output.  It returns an error if
+// the line is not an MI record, e.g., output of the program being debugged.
+func ParseMIRecord(ln string) (*MIRecord, error) {
+	mr := &MIRecord{Token: -1}
+	i := 0
+	for i < len(ln) && ln[i] >= '0' && ln[i] <= '9' {
+		i++
+	}
+	if i > 0 {
+		mr.Token, _ = strconv.Atoi(ln[:i])
+	}
+	if i >= len(ln) || !strings.ContainsRune("^*+=~@&", rune(ln[i])) {
+		return nil, fmt.Errorf("not an MI record: %s", ln)
+	}
+	mr.Kind = ln[i]
+	p := &miParser{s: ln, pos: i + 1}
+	if mr.IsStream() {
+		s, err := p.cstring()
+		if err != nil {
+			return nil, err
+		}
+		mr.Text = s
+		return mr, nil
+	}
+	st := p.pos
+	for p.pos < len(ln) && ln[p.pos] != ',' {
+		p.pos++
+	}
+	mr.Class = ln[st:p.pos]
+	mr.Vals = MITuple{}
+	for p.pos < len(ln) && ln[p.pos] == ',' {
+		p.pos++
+		nm, val, err := p.result()
+		if err != nil {
+			return nil, err
+		}
+		mr.Vals[nm] = val
+	}
+	return mr, nil
+}
+
+// miParser parses MI values from a string
+type miParser struct {
+	s   string
+	pos int
+}
+
+func (p *miParser) errorf(msg string) error {
+	return fmt.Errorf("MI parse error at %d: %s in: %s", p.pos, msg, p.s)
+}
+
+// result parses name=value
+func (p *miParser) result() (string, interface{}, error) {
+	st := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != '=' {
+		p.pos++
+	}
+	if p.pos >= len(p.s) {
+		return "", nil, p.errorf("expected =")
+	}
+	nm := p.s[st:p.pos]
+	p.pos++
+	val, err := p.value()
+	return nm, val, err
+}
+
+// value parses a c-string, tuple or list
+func (p *miParser) value() (interface{}, error) {
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("expected value")
+	}
+	switch p.s[p.pos] {
+	case '"':
+		return p.cstring()
+	case '{':
+		p.pos++
+		mt := MITuple{}
+		if p.pos < len(p.s) && p.s[p.pos] == '}' {
+			p.pos++
+			return mt, nil
+		}
+		for {
+			nm, val, err := p.result()
+			if err != nil {
+				return nil, err
+			}
+			mt[nm] = val
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("expected }")
+			}
+			c := p.s[p.pos]
+			p.pos++
+			if c == '}' {
+				return mt, nil
+			}
+			if c != ',' {
+				return nil, p.errorf("expected , or }")
+			}
+		}
+	case '[':
+		p.pos++
+		ml := MIList{}
+		if p.pos < len(p.s) && p.s[p.pos] == ']' {
+			p.pos++
+			return ml, nil
+		}
+		for {
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("expected ]")
+			}
+			var val interface{}
+			var err error
+			if c := p.s[p.pos]; c == '"' || c == '{' || c == '[' {
+				val, err = p.value()
+			} else {
+				_, val, err = p.result()
+			}
+			if err != nil {
+				return nil, err
+			}
+			ml = append(ml, val)
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("expected ]")
+			}
+			c := p.s[p.pos]
+			p.pos++
+			if c == ']' {
+				return ml, nil
+			}
+			if c != ',' {
+				return nil, p.errorf("expected , or ]")
+			}
+		}
+	}
+	return nil, p.errorf("expected \", { or [")
+}
+
+// cstring parses a C string, with escapes
+func (p *miParser) cstring() (string, error) {
+	if p.pos >= len(p.s) || p.s[p.pos] != '"' {
+		return "", p.errorf("expected \"")
+	}
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", p.errorf("unterminated escape")
+			}
+			e := p.s[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'e':
+				sb.WriteByte(0x1b)
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				st := p.pos - 1
+				for p.pos < len(p.s) && p.pos-st < 3 && p.s[p.pos] >= '0' && p.s[p.pos] <= '7' {
+					p.pos++
+				}
+				o, _ := strconv.ParseUint(p.s[st:p.pos], 8, 8)
+				sb.WriteByte(byte(o))
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// MIQuote returns given string quoted as a C string for a GDB/MI command
+func MIQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString(`\n`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidegdb
+
+import (
+	"testing"
+
+	"github.com/goki/pi/syms"
+)
+
+func TestParseMIRecord(t *testing.T) {
+	mr, err := ParseMIRecord(`12^done,stack=[frame={level="0",addr="0x0000555555555131",func="add",file="main.c",fullname="/src/main.c",line="4"},frame={level="1",addr="0x000055555555514f",func="main",file="main.c",fullname="/src/main.c",line="9"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Token != 12 || mr.Kind != '^' || mr.Class != "done" {
+		t.Errorf("record header is wrong: %d %c %s", mr.Token, mr.Kind, mr.Class)
+	}
+	frs := mr.Vals.List("stack").Tuples()
+	if len(frs) != 2 || frs[1].Str("func") != "main" || frs[1].Int("line") != 9 || frs[0].Addr("addr") != 0x555555555131 {
+		t.Errorf("stack is wrong: %v", frs)
+	}
+
+	mr, err = ParseMIRecord(`*stopped,reason="breakpoint-hit",disp="keep",bkptno="1",frame={addr="0x1139",func="main",args=[],file="main.c",fullname="/src/main.c",line="7"},thread-id="1",stopped-threads="all"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Token != -1 || mr.Class != "stopped" || mr.Vals.Int("bkptno") != 1 || mr.Vals.Tuple("frame").Str("fullname") != "/src/main.c" {
+		t.Errorf("stopped record is wrong: %v", mr.Vals)
+	}
+	if len(mr.Vals.Tuple("frame").List("args")) != 0 {
+		t.Errorf("args should be empty")
+	}
+
+	mr, err = ParseMIRecord(`~"$1 = \"a\\tb\"\n"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mr.IsStream() || mr.Text != "$1 = \"a\\tb\"\n" {
+		t.Errorf("stream text is wrong: %q", mr.Text)
+	}
+
+	mr, err = ParseMIRecord(`^done,groups=["1","2"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs := mr.Vals.List("groups").Strs(); len(gs) != 2 || gs[1] != "2" {
+		t.Errorf("list of strings is wrong: %v", gs)
+	}
+
+	if _, err = ParseMIRecord("hello from the program"); err == nil {
+		t.Errorf("program output should not parse as a record")
+	}
+	if _, err = ParseMIRecord(`^done,value="unterminated`); err == nil {
+		t.Errorf("unterminated string should be an error")
+	}
+}
+
+func TestMIQuote(t *testing.T) {
+	q := MIQuote(`say "hi" \ there`)
+	if q != `"say \"hi\" \\ there"` {
+		t.Errorf("quote is wrong: %s", q)
+	}
+	mr, err := ParseMIRecord(`~` + q)
+	if err != nil || mr.Text != `say "hi" \ there` {
+		t.Errorf("quote did not read back: %q %v", mr.Text, err)
+	}
+}
+
+func TestCTypeKind(t *testing.T) {
+	tests := []struct {
+		typ  string
+		kind syms.Kinds
+	}{
+		{"int", syms.Int},
+		{"unsigned int", syms.Uint},
+		{"unsigned char", syms.Uint8},
+		{"const char *", syms.Ptr},
+		{"std::string &", syms.Ref},
+		{"double [4]", syms.Array},
+		{"struct point", syms.Struct},
+		{"std::vector<int>", syms.Struct},
+		{"bool", syms.Bool},
+		{"int (*)(int, int)", syms.Ptr},
+		{"float", syms.Float32},
+	}
+	for _, ts := range tests {
+		if k := CTypeKind(ts.typ); k != ts.kind {
+			t.Errorf("kind of %s should be %v, was: %v", ts.typ, ts.kind, k)
+		}
+	}
+	if a := parseAddr(`0x4005e4 "hello"`); a != 0x4005e4 {
+		t.Errorf("address is wrong: %#x", a)
+	}
+	if a := parseAddr(`(int *) 0x7ffc10`); a != 0x7ffc10 {
+		t.Errorf("address is wrong: %#x", a)
+	}
+	if d := callDest("call   0x1139 <add>"); d != "add" {
+		t.Errorf("call destination is wrong: %s", d)
+	}
+}
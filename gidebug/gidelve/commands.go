@@ -0,0 +1,149 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidelve
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gide/gidebug"
+)
+
+// Commands are the raw delve commands supported by Command, with their help.
+// Commands that run the process (continue, next, step etc) are not included,
+// as those must go through the debugger toolbar to keep the view in sync.
+// The breakpoint commands are not supported for a core dump.
+var Commands = [][2]string{
+	{"break <loc>", "set a breakpoint at a location: func, file:line, or *address"},
+	{"breakpoints", "list all breakpoints"},
+	{"clear <id>", "delete breakpoint with given id"},
+	{"goroutines", "list all goroutines"},
+	{"threads", "list all threads"},
+	{"stack [depth]", "print the stack of the current goroutine"},
+	{"print <expr>", "evaluate an expression"},
+	{"locals", "print the local variables and args of the current frame"},
+	{"funcs [filter]", "list functions matching filter regexp"},
+	{"types [filter]", "list types matching filter regexp"},
+	{"sources [filter]", "list source files matching filter regexp"},
+	{"help", "list the available commands"},
+}
+
+// Command executes a raw debugger command, in the debugger's own
+// command language, for given thread and frame, returning its output.
+func (gd *GiDelve) Command(cmd string, threadID int, frame int) (string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return "", err
+	}
+	flds := strings.Fields(cmd)
+	if len(flds) == 0 {
+		return "", nil
+	}
+	arg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd), flds[0]))
+	var sb strings.Builder
+	switch flds[0] {
+	case "break", "b", "clear":
+		if gd.params.Mode == gidebug.Core {
+			return "", fmt.Errorf("%s: cannot change the breakpoints of a core dump", flds[0])
+		}
+	}
+	switch flds[0] {
+	case "break", "b":
+		ec := gd.toEvalScope(threadID, frame)
+		locs, err := gd.dlv.FindLocation(*ec, arg, false, nil)
+		if err != nil {
+			return "", err
+		}
+		for _, lc := range locs {
+			br, err := gd.SetBreakPC(lc.PC)
+			if err != nil {
+				return sb.String(), err
+			}
+			fmt.Fprintf(&sb, "Breakpoint %d set at %#x for %s %s:%d\n", br.ID, br.PC, br.Func, br.File, br.Line)
+		}
+	case "breakpoints", "bp":
+		bks, err := gd.ListBreaks()
+		if err != nil {
+			return "", err
+		}
+		for _, br := range bks {
+			fmt.Fprintf(&sb, "Breakpoint %d at %#x %s %s:%d\n", br.ID, br.PC, br.Func, br.File, br.Line)
+		}
+	case "clear":
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", err
+		}
+		if err := gd.ClearBreak(id); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "Breakpoint %d cleared\n", id)
+	case "goroutines", "grs":
+		tks, err := gd.ListTasks()
+		if err != nil {
+			return "", err
+		}
+		for _, tk := range tks {
+			fmt.Fprintf(&sb, "Goroutine %d - %s:%d %s\n", tk.ID, tk.File, tk.Line, tk.Func)
+		}
+		fmt.Fprintf(&sb, "[%d goroutines]\n", len(tks))
+	case "threads":
+		ths, err := gd.ListThreads()
+		if err != nil {
+			return "", err
+		}
+		for _, th := range ths {
+			fmt.Fprintf(&sb, "Thread %d at %#x %s:%d %s\n", th.ID, th.PC, th.File, th.Line, th.Func)
+		}
+	case "stack", "bt":
+		depth := 50
+		if arg != "" {
+			depth, _ = strconv.Atoi(arg)
+		}
+		sf, err := gd.Stack(threadID, depth)
+		if err != nil {
+			return "", err
+		}
+		for _, fr := range sf {
+			fmt.Fprintf(&sb, "%d  %#x in %s\n    at %s:%d\n", fr.Depth, fr.PC, fr.Func, fr.File, fr.Line)
+		}
+	case "print", "p":
+		vr, err := gd.GetVar(arg, threadID, frame)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(vr.ValueString(true, 0, 4, 200, false) + "\n")
+	case "locals", "args":
+		vrs, err := gd.ListVars(threadID, frame)
+		if err != nil {
+			return "", err
+		}
+		for _, vr := range vrs {
+			sb.WriteString(vr.Label() + "\n")
+		}
+	case "funcs", "types", "sources":
+		var ls []string
+		var err error
+		switch flds[0] {
+		case "funcs":
+			ls, err = gd.ListFuncs(arg)
+		case "types":
+			ls, err = gd.ListTypes(arg)
+		default:
+			ls, err = gd.ListSources(arg)
+		}
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(strings.Join(ls, "\n") + "\n")
+	case "help", "h":
+		for _, c := range Commands {
+			fmt.Fprintf(&sb, "%-20s %s\n", c[0], c[1])
+		}
+	default:
+		return "", fmt.Errorf("unknown command: %s -- type help for the available commands", flds[0])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidelve
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/goki/gi/giv"
+	"github.com/goki/gide/gidebug"
+	"github.com/goki/gide/gidebug/gidetest"
+)
+
+func TestConformance(t *testing.T) {
+	if _, err := exec.LookPath("dlv"); err != nil {
+		t.Skip("dlv is not installed")
+	}
+	gidetest.Conformance(t, func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error) {
+		return NewGiDelve(dir, dir, giv.NewTextBuf(), pars)
+	})
+}
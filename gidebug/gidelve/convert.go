@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/go-delve/delve/service/api"
@@ -35,6 +36,9 @@ func (gd *GiDelve) cvtState(ds *api.DebuggerState) *gidebug.State {
 	st.Exited = ds.Exited
 	st.ExitStatus = ds.ExitStatus
 	st.Err = ds.Err
+	if ds.CurrentThread != nil && len(ds.CurrentThread.ReturnValues) > 0 {
+		st.Returns = gd.cvtVars(ds.CurrentThread.ReturnValues)
+	}
 	return st
 }
 
@@ -45,9 +49,9 @@ func (gd *GiDelve) cvtThread(ds *api.Thread) *gidebug.Thread {
 	th := &gidebug.Thread{}
 	th.ID = ds.ID
 	th.PC = ds.PC
-	th.File = giv.RelFilePath(ds.File, gd.rootPath)
+	th.FPath = gd.localPath(ds.File)
+	th.File = giv.RelFilePath(th.FPath, gd.rootPath)
 	th.Line = ds.Line
-	th.FPath = ds.File
 	if ds.Function != nil {
 		th.Func = ds.Function.Name_
 	}
@@ -74,9 +78,9 @@ func (gd *GiDelve) cvtTask(ds *api.Goroutine) *gidebug.Task {
 	gr := &gidebug.Task{}
 	gr.ID = ds.ID
 	gr.PC = ds.UserCurrentLoc.PC
-	gr.File = giv.RelFilePath(ds.UserCurrentLoc.File, gd.rootPath)
+	gr.FPath = gd.localPath(ds.UserCurrentLoc.File)
+	gr.File = giv.RelFilePath(gr.FPath, gd.rootPath)
 	gr.Line = ds.UserCurrentLoc.Line
-	gr.FPath = ds.UserCurrentLoc.File
 	if ds.UserCurrentLoc.Function != nil {
 		gr.Func = ds.UserCurrentLoc.Function.Name_
 	}
@@ -104,9 +108,9 @@ func (gd *GiDelve) cvtLocation(ds *api.Location) *gidebug.Location {
 	}
 	lc := &gidebug.Location{}
 	lc.PC = ds.PC
-	lc.File = giv.RelFilePath(ds.File, gd.rootPath)
+	lc.FPath = gd.localPath(ds.File)
+	lc.File = giv.RelFilePath(lc.FPath, gd.rootPath)
 	lc.Line = ds.Line
-	lc.FPath = ds.File
 	if ds.Function != nil {
 		lc.Func = ds.Function.Name_
 	}
@@ -121,15 +125,47 @@ func (gd *GiDelve) cvtBreak(ds *api.Breakpoint) *gidebug.Break {
 	bp.On = true // if we're converting, it is on..
 	bp.ID = ds.ID
 	bp.PC = ds.Addr
-	bp.File = giv.RelFilePath(ds.File, gd.rootPath)
-	bp.FPath = ds.File
+	bp.FPath = gd.localPath(ds.File)
+	bp.File = giv.RelFilePath(bp.FPath, gd.rootPath)
 	bp.Line = ds.Line
 	bp.Func = ds.FunctionName
 	bp.Cond = ds.Cond
 	bp.Trace = ds.Tracepoint
+	bp.TraceVars = strings.Join(ds.Variables, ", ")
+	if ds.WatchExpr != "" { // Addr is that of the variable, with no file
+		bp.Kind = gidebug.BreakWatch
+		bp.Expr = ds.WatchExpr
+		bp.FPath = ""
+		bp.File = ""
+	}
 	return bp
 }
 
+func (gd *GiDelve) cvtTrace(ds *api.Thread) *gidebug.TraceEvent {
+	te := &gidebug.TraceEvent{Time: time.Now()}
+	if ds == nil {
+		return te
+	}
+	if ds.Breakpoint != nil {
+		te.Break = ds.Breakpoint.ID
+	}
+	te.Task = ds.GoroutineID
+	te.FPath = gd.localPath(ds.File)
+	te.File = giv.RelFilePath(te.FPath, gd.rootPath)
+	te.Line = ds.Line
+	if ds.Function != nil {
+		te.Func = ds.Function.Name_
+	}
+	if ds.BreakpointInfo != nil {
+		for i := range ds.BreakpointInfo.Variables {
+			vr := gd.cvtVar(&ds.BreakpointInfo.Variables[i])
+			te.Vals = append(te.Vals, gidebug.TraceVal{Expr: vr.Nm, Value: vr.ValueString(false, 0, 4, 200, false)})
+		}
+	}
+	te.SetValue()
+	return te
+}
+
 func (gd *GiDelve) cvtBreaks(ds []*api.Breakpoint) []*gidebug.Break {
 	if ds == nil || len(ds) == 0 {
 		return nil
@@ -142,6 +178,39 @@ func (gd *GiDelve) cvtBreaks(ds []*api.Breakpoint) []*gidebug.Break {
 	return vr
 }
 
+func (gd *GiDelve) cvtInstr(ds *api.AsmInstruction) *gidebug.Instr {
+	if ds == nil {
+		return nil
+	}
+	in := &gidebug.Instr{}
+	in.PC = ds.Loc.PC
+	in.Text = ds.Text
+	in.FPath = gd.localPath(ds.Loc.File)
+	in.File = giv.RelFilePath(in.FPath, gd.rootPath)
+	in.Line = ds.Loc.Line
+	if ds.Loc.Function != nil {
+		in.Func = ds.Loc.Function.Name_
+	}
+	if ds.DestLoc != nil && ds.DestLoc.Function != nil {
+		in.Dest = ds.DestLoc.Function.Name_
+	}
+	in.Break = ds.Breakpoint
+	in.AtPC = ds.AtPC
+	return in
+}
+
+func (gd *GiDelve) cvtInstrs(ds api.AsmInstructions) []*gidebug.Instr {
+	if ds == nil || len(ds) == 0 {
+		return nil
+	}
+	nd := len(ds)
+	vr := make([]*gidebug.Instr, nd)
+	for i := range ds {
+		vr[i] = gd.cvtInstr(&ds[i])
+	}
+	return vr
+}
+
 func (gd *GiDelve) cvtFrame(ds *api.Stackframe, taskID int) *gidebug.Frame {
 	if ds == nil {
 		return nil
@@ -149,9 +218,9 @@ func (gd *GiDelve) cvtFrame(ds *api.Stackframe, taskID int) *gidebug.Frame {
 	fr := &gidebug.Frame{}
 	fr.ThreadID = taskID
 	fr.PC = ds.Location.PC
-	fr.File = giv.RelFilePath(ds.Location.File, gd.rootPath)
+	fr.FPath = gd.localPath(ds.Location.File)
+	fr.File = giv.RelFilePath(fr.FPath, gd.rootPath)
 	fr.Line = ds.Location.Line
-	fr.FPath = ds.Location.File
 	if ds.Location.Function != nil {
 		fr.Func = ds.Location.Function.Name_
 	}
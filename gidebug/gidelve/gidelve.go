@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"net"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
@@ -78,7 +80,7 @@ func (gd *GiDelve) SetParams(params *gidebug.Params) {
 
 // StartedCheck checks that delve client is running properly
 func (gd *GiDelve) StartedCheck() error {
-	if gd.cmd == nil || gd.dlv == nil {
+	if !gd.IsActive() {
 		err := gidebug.NotStartedErr
 		return gd.LogErr(err)
 	}
@@ -91,22 +93,20 @@ func (gd *GiDelve) Start(path, rootPath string, outbuf *giv.TextBuf, pars *gideb
 	gd.rootPath = rootPath
 	gd.params = *pars
 	gd.statFunc = pars.StatFunc
-	switch pars.Mode {
-	case gidebug.Exec:
-		targs := []string{"debug", "--headless", "--api-version=2"}
-		targs = append(targs, gd.params.Args...)
-		gd.cmd = exec.Command("dlv", targs...)
-	case gidebug.Test:
-		targs := []string{"test", "--headless", "--api-version=2"}
-		targs = append(targs, gd.params.Args...)
-		gd.cmd = exec.Command("dlv", targs...)
-	case gidebug.Attach:
-		// note: --log here creates huge amounts of messages and doesn't work..
-		targs := []string{"attach", fmt.Sprintf("%d", gd.params.PID), "--headless", "--api-version=2"}
-		targs = append(targs, gd.params.Args...)
-		gd.cmd = exec.Command("dlv", targs...)
+	if gd.params.Mode == gidebug.Remote {
+		if outbuf != nil {
+			gd.obuf = &giv.OutBuf{Buf: outbuf}
+		}
+		return gd.Connect(gd.params.Addr)
+	}
+	gd.cmd = exec.Command("dlv", gd.dlvArgs(path)...)
+	if len(gd.params.Env) > 0 {
+		gd.cmd.Env = append(os.Environ(), gd.params.Env...)
+	}
+	gd.cmd.Dir = path
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		gd.cmd.Dir = filepath.Dir(path)
 	}
-	gd.cmd.Dir = filepath.Dir(path)
 	stdout, err := gd.cmd.StdoutPipe()
 	if err == nil {
 		gd.cmd.Stderr = gd.cmd.Stdout
@@ -124,6 +124,88 @@ func (gd *GiDelve) Start(path, rootPath string, outbuf *giv.TextBuf, pars *gideb
 	return nil
 }
 
+// ConnectTimeout is how long to wait to connect to a remote debugger server
+var ConnectTimeout = 10 * time.Second
+
+// Connect connects to an already-running headless delve server at given
+// host:port address, as started with: dlv exec --headless --listen addr
+func (gd *GiDelve) Connect(addr string) error {
+	if addr == "" {
+		addr = "127.0.0.1:2345"
+	}
+	conn, err := net.DialTimeout("tcp", addr, ConnectTimeout)
+	if err != nil {
+		if gd.statFunc != nil {
+			gd.statFunc(gidebug.Error)
+		}
+		return gd.LogErr(fmt.Errorf("could not connect to the delve server at: %s: %v", addr, err))
+	}
+	gd.conn = addr
+	gd.dlv = rpc2.NewClientFromConn(conn)
+	gd.SetParams(&gd.params)
+	gd.WriteToConsole(fmt.Sprintf("Connected to the delve server at: %s\n", addr))
+	if gd.statFunc != nil {
+		gd.statFunc(gidebug.Ready)
+	}
+	return nil
+}
+
+// localPath returns given file path from delve mapped to the local one,
+// for Remote mode
+func (gd *GiDelve) localPath(fpath string) string {
+	return gd.params.PathMaps.ToLocal(fpath)
+}
+
+// remotePath returns given local file path mapped to the one used by
+// delve, for Remote mode
+func (gd *GiDelve) remotePath(fpath string) string {
+	return gd.params.PathMaps.ToRemote(fpath)
+}
+
+// dlvArgs returns the args for running dlv on given exe path, according
+// to the params
+func (gd *GiDelve) dlvArgs(path string) []string {
+	var targs []string
+	switch gd.params.Mode {
+	case gidebug.Exec:
+		targs = []string{"debug"}
+	case gidebug.Test:
+		targs = []string{"test"}
+	case gidebug.Attach:
+		// note: --log here creates huge amounts of messages and doesn't work..
+		targs = []string{"attach", fmt.Sprintf("%d", gd.params.PID)}
+	case gidebug.Core:
+		targs = []string{"core", path, gd.params.CoreFile}
+	}
+	targs = append(targs, "--headless", "--api-version=2")
+	build := gd.params.Mode == gidebug.Exec || gd.params.Mode == gidebug.Test
+	if build && gd.params.WorkDir != "" {
+		targs = append(targs, "--wd", gd.params.WorkDir)
+	}
+	if build && len(gd.params.BuildTags) > 0 {
+		targs = append(targs, "--build-flags=-tags="+strings.Join(gd.params.BuildTags, ","))
+	}
+	// extra args can include -- followed by program args, to which the
+	// launch configuration program args are added
+	var pargs []string
+	for i, a := range gd.params.Args {
+		if a == "--" {
+			pargs = append(pargs, gd.params.Args[i+1:]...)
+			break
+		}
+		targs = append(targs, a)
+	}
+	pargs = append(pargs, gd.params.ProgArgs...)
+	if gd.params.Mode == gidebug.Test && gd.params.TestRun != "" {
+		pargs = append(pargs, "-test.run", gd.params.TestRun)
+	}
+	if len(pargs) > 0 {
+		targs = append(targs, "--")
+		targs = append(targs, pargs...)
+	}
+	return targs
+}
+
 func (gd *GiDelve) monitorOutput(out []byte) []byte {
 	if gd.conn != "" {
 		return out
@@ -157,6 +239,9 @@ func (gd *GiDelve) monitorOutput(out []byte) []byte {
 
 // IsActive returns whether debugger is active and ready for commands
 func (gd *GiDelve) IsActive() bool {
+	if gd.params.Mode == gidebug.Remote {
+		return gd.dlv != nil
+	}
 	return gd.cmd != nil && gd.dlv != nil
 }
 
@@ -179,8 +264,14 @@ func (gd *GiDelve) LastModified() time.Time {
 }
 
 // Detach detaches the debugger, optionally killing the process.
+// In Remote mode, if not killing the process, it just disconnects from the
+// server, letting the process continue.
 func (gd *GiDelve) Detach(killProcess bool) error {
 	var err error
+	if gd.dlv != nil && gd.params.Mode == gidebug.Remote && !killProcess {
+		err = gd.dlv.Disconnect(true)
+		gd.dlv = nil
+	}
 	if gd.dlv != nil {
 		err = gd.dlv.Detach(killProcess)
 		gd.dlv = nil
@@ -247,13 +338,11 @@ func (gd *GiDelve) Continue(all *gidebug.AllState) <-chan *gidebug.State {
 				gd.LogErr(nv.Err)
 			}
 			ds := gd.cvtState(nv)
-			if !ds.Exited {
-				bk, _ := gidebug.BreakByFile(all.Breaks, ds.Task.FPath, ds.Task.Line)
-				if bk != nil && bk.Trace {
-					ds.CurTrace = bk.ID
-					gd.WriteToConsole(fmt.Sprintf("Trace: %d File: %s:%d\n", bk.ID, ds.Task.File, ds.Task.Line))
-					continue
-				}
+			if th := nv.CurrentThread; !ds.Exited && th != nil && th.Breakpoint != nil && th.Breakpoint.Tracepoint {
+				// delve continues after tracepoints on its own
+				ds.CurTrace = th.Breakpoint.ID
+				ds.Trace = gd.cvtTrace(th)
+				gd.WriteToConsole(fmt.Sprintf("Trace: %d File: %s:%d %s\n", ds.CurTrace, ds.Trace.File, ds.Trace.Line, ds.Trace.Value))
 			}
 			sc <- ds
 		}
@@ -377,19 +466,82 @@ func (gd *GiDelve) SetBreak(fname string, line int) (*gidebug.Break, error) {
 		return nil, err
 	}
 	bp := &api.Breakpoint{}
-	bp.File = fname
+	bp.File = gd.remotePath(fname)
 	bp.Line = line
 	ds, err := gd.dlv.CreateBreakpoint(bp)
 	gd.LogErr(err)
 	return gd.cvtBreak(ds), err
 }
 
+// SetBreakPC sets a new instruction-level breakpoint at given address
+func (gd *GiDelve) SetBreakPC(pc uint64) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	bp := &api.Breakpoint{}
+	bp.Addr = pc
+	ds, err := gd.dlv.CreateBreakpoint(bp)
+	gd.LogErr(err)
+	br := gd.cvtBreak(ds)
+	if br != nil {
+		br.Kind = gidebug.BreakAddr
+	}
+	return br, err
+}
+
+// SetBreakFunc sets a new breakpoint at the start of given function
+func (gd *GiDelve) SetBreakFunc(fname string) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	bp := &api.Breakpoint{}
+	bp.FunctionName = fname
+	ds, err := gd.dlv.CreateBreakpoint(bp)
+	gd.LogErr(err)
+	br := gd.cvtBreak(ds)
+	if br != nil {
+		br.Kind = gidebug.BreakFunc
+		br.Expr = fname
+	}
+	return br, err
+}
+
+// SetWatch sets a new data watchpoint on given variable expression,
+// evaluated in the scope of the current goroutine, which stops when it is
+// written.  Watchpoints on local variables are cleared by delve when the
+// variable goes out of scope.
+func (gd *GiDelve) SetWatch(expr string) (*gidebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	ds, err := gd.dlv.CreateWatchpoint(api.EvalScope{GoroutineID: -1}, expr, api.WatchWrite)
+	gd.LogErr(err)
+	br := gd.cvtBreak(ds)
+	if br != nil {
+		br.Kind = gidebug.BreakWatch
+		br.Expr = expr
+	}
+	return br, err
+}
+
+// breakAtPC returns the breakpoint at given address from list, which
+// does not have the Kind set as it comes from delve.
+// returns nil, -1 if not found.
+func breakAtPC(bks []*gidebug.Break, pc uint64) (*gidebug.Break, int) {
+	for i, br := range bks {
+		if br.PC == pc {
+			return br, i
+		}
+	}
+	return nil, -1
+}
+
 // ListBreaks gets all breakpoints.
 func (gd *GiDelve) ListBreaks() ([]*gidebug.Break, error) {
 	if err := gd.StartedCheck(); err != nil {
 		return nil, err
 	}
-	ds, err := gd.dlv.ListBreakpoints()
+	ds, err := gd.dlv.ListBreakpoints(false)
 	gd.LogErr(err)
 	return gd.cvtBreaks(ds), err
 }
@@ -416,16 +568,17 @@ func (gd *GiDelve) ClearBreakByName(name string) (*gidebug.Break, error) {
 // AmmendBreak allows user to update an existing breakpoint for example
 // to change the information retrieved when the breakpoint is hit or to change,
 // add or remove the break condition
-func (gd *GiDelve) AmendBreak(id int, fname string, line int, cond string, trace bool) error {
+func (gd *GiDelve) AmendBreak(id int, fname string, line int, cond string, trace bool, traceVars []string) error {
 	if err := gd.StartedCheck(); err != nil {
 		return err
 	}
 	bp := &api.Breakpoint{}
 	bp.ID = id
-	bp.File = fname
+	bp.File = gd.remotePath(fname)
 	bp.Line = line
 	bp.Cond = cond
 	bp.Tracepoint = trace
+	bp.Variables = traceVars
 	err := gd.dlv.AmendBreakpoint(bp)
 	return gd.LogErr(err)
 }
@@ -442,27 +595,65 @@ func (gd *GiDelve) UpdateBreaks(brk *[]*gidebug.Break) error {
 	}
 	for itr := 0; itr < 2; itr++ {
 		updt := false
-		for _, b := range *brk {
-			c, ci := gidebug.BreakByFile(cb, b.FPath, b.Line)
-			if c != nil && c.ID > 0 {
-				if !b.On {
-					cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
-					gd.ClearBreak(c.ID)                // remove from list
+		// other kinds first, so they are not taken for line breaks
+		for _, kind := range []gidebug.BreakKinds{gidebug.BreakAddr, gidebug.BreakFunc, gidebug.BreakWatch, gidebug.BreakLine} {
+			for _, b := range *brk {
+				if b.Kind != kind {
 					continue
 				}
-				bc := b.Cond
-				bt := b.Trace
-				if bc != c.Cond || bt != c.Trace {
-					gd.AmendBreak(c.ID, c.File, c.Line, b.Cond, b.Trace)
+				var c *gidebug.Break
+				ci := -1
+				switch kind {
+				case gidebug.BreakAddr:
+					c, ci = breakAtPC(cb, b.PC)
+				case gidebug.BreakLine:
+					c, ci = gidebug.BreakByFile(cb, b.FPath, b.Line)
+				default:
+					if b.ID > 0 {
+						c, ci = gidebug.BreakByID(cb, b.ID)
+					}
 				}
-				*b = *c
-				b.Cond = bc
-				b.Trace = bt
-				cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
-			} else { // set but not found
-				if b.On {
-					updt = true // need another iter
-					gd.SetBreak(b.FPath, b.Line)
+				if c != nil && c.ID > 0 {
+					if !b.On {
+						cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
+						gd.ClearBreak(c.ID)                // remove from list
+						continue
+					}
+					bc := b.Cond
+					bt := b.Trace
+					btv := b.TraceVars
+					be := b.Expr
+					if bc != c.Cond || bt != c.Trace || btv != c.TraceVars {
+						gd.AmendBreak(c.ID, c.FPath, c.Line, b.Cond, b.Trace, gidebug.TraceExprs(b.TraceVars))
+					}
+					*b = *c
+					b.Cond = bc
+					b.Trace = bt
+					b.TraceVars = btv
+					b.Kind = kind
+					b.Expr = be
+					cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
+				} else { // set but not found
+					if b.On {
+						switch kind {
+						case gidebug.BreakAddr:
+							gd.SetBreakPC(b.PC)
+						case gidebug.BreakFunc:
+							if nb, err := gd.SetBreakFunc(b.Expr); err == nil && nb != nil {
+								b.ID = nb.ID
+							}
+						case gidebug.BreakWatch:
+							nb, err := gd.SetWatch(b.Expr)
+							if err != nil || nb == nil { // e.g., not in scope: stays on, retried next update
+								b.ID = 0
+								continue
+							}
+							b.ID = nb.ID
+						default:
+							gd.SetBreak(b.FPath, b.Line)
+						}
+						updt = true // need another iter
+					}
 				}
 			}
 		}
@@ -732,6 +923,45 @@ func (gd *GiDelve) SetVar(name, value string, threadID int, frame int) error {
 	return gd.LogErr(err)
 }
 
+// Disassemble returns the instructions of the function containing given
+// address, for given thread (lowest-level supported by language,
+// e.g., Task if supported, else Thread), and frame number.
+func (gd *GiDelve) Disassemble(threadID int, frame int, pc uint64) ([]*gidebug.Instr, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	ec := gd.toEvalScope(threadID, frame)
+	ds, err := gd.dlv.DisassemblePC(*ec, pc, api.GoFlavour)
+	gd.LogErr(err)
+	return gd.cvtInstrs(ds), err
+}
+
+// ExamineMemory returns the raw memory of the process, of given length
+// starting at given address.
+func (gd *GiDelve) ExamineMemory(addr uint64, length int) ([]byte, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	var mem []byte
+	for len(mem) < length { // delve reads at most 1000 bytes at a time
+		n := length - len(mem)
+		if n > 1000 {
+			n = 1000
+		}
+		ds, _, err := gd.dlv.ExamineMemory(addr+uint64(len(mem)), n)
+		if err != nil {
+			gd.LogErr(err)
+			return mem, err
+		}
+		if len(ds) == 0 { // e.g., at the end of a mapped region
+			err = fmt.Errorf("ExamineMemory: could only read %d of %d bytes at: %#x", len(mem), length, addr)
+			return mem, gd.LogErr(err)
+		}
+		mem = append(mem, ds...)
+	}
+	return mem, nil
+}
+
 // ListSources lists all source files in the process matching filter.
 func (gd *GiDelve) ListSources(filter string) ([]string, error) {
 	if err := gd.StartedCheck(); err != nil {
@@ -739,6 +969,9 @@ func (gd *GiDelve) ListSources(filter string) ([]string, error) {
 	}
 	ds, err := gd.dlv.ListSources(filter)
 	gd.LogErr(err)
+	for i, fp := range ds {
+		ds[i] = gd.localPath(fp)
+	}
 	return ds, err
 }
 
@@ -0,0 +1,45 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidelve
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goki/gide/gidebug"
+)
+
+func TestDlvArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		params gidebug.Params
+		want   string
+	}{
+		{"exec", gidebug.Params{Mode: gidebug.Exec},
+			"debug --headless --api-version=2"},
+		{"workdir and tags", gidebug.Params{Mode: gidebug.Exec, WorkDir: "/tmp/wd", BuildTags: []string{"a", "b"}},
+			"debug --headless --api-version=2 --wd /tmp/wd --build-flags=-tags=a,b"},
+		{"extra args", gidebug.Params{Mode: gidebug.Exec, Args: []string{"--log", "--", "-v", "x"}},
+			"debug --headless --api-version=2 --log -- -v x"},
+		{"prog args after extra", gidebug.Params{Mode: gidebug.Exec, Args: []string{"--", "-v"}, ProgArgs: []string{"in.txt"}},
+			"debug --headless --api-version=2 -- -v in.txt"},
+		{"prog args only", gidebug.Params{Mode: gidebug.Exec, Args: []string{"--log"}, ProgArgs: []string{"in.txt"}},
+			"debug --headless --api-version=2 --log -- in.txt"},
+		{"test run", gidebug.Params{Mode: gidebug.Test, BuildTags: []string{"x"}, TestRun: "TestFoo"},
+			"test --headless --api-version=2 --build-flags=-tags=x -- -test.run TestFoo"},
+		{"test run with args", gidebug.Params{Mode: gidebug.Test, ProgArgs: []string{"-test.v"}, TestRun: "TestFoo"},
+			"test --headless --api-version=2 -- -test.v -test.run TestFoo"},
+		{"attach ignores build", gidebug.Params{Mode: gidebug.Attach, PID: 42, WorkDir: "/tmp/wd", BuildTags: []string{"a"}},
+			"attach 42 --headless --api-version=2"},
+		{"core ignores test run", gidebug.Params{Mode: gidebug.Core, CoreFile: "core.1", TestRun: "TestFoo"},
+			"core prog core.1 --headless --api-version=2"},
+	}
+	for _, tt := range tests {
+		gd := &GiDelve{params: tt.params}
+		if got := strings.Join(gd.dlvArgs("prog"), " "); got != tt.want {
+			t.Errorf("%s: args were:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidelve
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goki/gide/gidebug"
+)
+
+var remoteTestProg = `package main
+
+import "time"
+
+func main() {
+	for {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+`
+
+// TestRemote connects to a local headless delve server, checking that
+// breakpoints and locations go through the source path mapping
+func TestRemote(t *testing.T) {
+	dlv, err := exec.LookPath("dlv")
+	if err != nil {
+		t.Skip("dlv is not installed")
+	}
+	dir, err := ioutil.TempDir("", "gidelve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "main.go")
+	exe := filepath.Join(dir, "prog")
+	if err := ioutil.WriteFile(src, []byte(remoteTestProg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bld := exec.Command("go", "build", "-gcflags=all=-N -l", "-o", exe, "main.go")
+	bld.Dir = dir
+	if out, err := bld.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v: %s", err, out)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	srv := exec.Command(dlv, "exec", exe, "--headless", "--api-version=2", "--accept-multiclient", "--listen", addr)
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Process.Kill()
+	for i := 0; i < 100; i++ { // wait for server to listen
+		if c, err := net.Dial("tcp", addr); err == nil {
+			c.Close()
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	local := "/local/src/prog"
+	pars := gidebug.DefaultParams
+	pars.Mode = gidebug.Remote
+	pars.Addr = addr
+	pars.PathMaps = gidebug.PathMaps{{Remote: dir, Local: local}}
+	gd, err := NewGiDelve(exe, local, nil, &pars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gd.Detach(true)
+	if !gd.IsActive() {
+		t.Fatal("should be active after connecting")
+	}
+	lfile := filepath.Join(local, "main.go")
+	br, err := gd.SetBreak(lfile, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.FPath != lfile {
+		t.Errorf("break path should have been: %v  was: %v\n", lfile, br.FPath)
+	}
+	if br.File != "main.go" {
+		t.Errorf("break file should have been: %v  was: %v\n", "main.go", br.File)
+	}
+	var ds *gidebug.State
+	for ds = range gd.Continue(nil) {
+	}
+	if ds == nil || ds.Thread.FPath != lfile || ds.Thread.Line != 7 {
+		t.Errorf("should have stopped at: %v:7  was: %+v\n", lfile, ds)
+	}
+}
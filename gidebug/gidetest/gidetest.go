@@ -0,0 +1,265 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gidetest provides a conformance test suite for implementations of
+// the GiDebug interface: each backend runs it in its own tests against the
+// small Go fixture program in testdata/fixture, e.g.:
+//
+//	func TestConformance(t *testing.T) {
+//		gidetest.Conformance(t, func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error) {
+//			return NewGiDelve(dir, dir, nil, pars)
+//		})
+//	}
+package gidetest
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goki/gide/gidebug"
+)
+
+// NewFunc returns a new debugger of the backend being tested, started in
+// Exec mode on the fixture program in given directory, which is also the
+// project root path.
+type NewFunc func(dir string, pars *gidebug.Params) (gidebug.GiDebug, error)
+
+// ReadyTimeout is how long to wait for the debugger to be ready
+var ReadyTimeout = 2 * time.Minute
+
+// FixtureDir returns the directory of the fixture program
+func FixtureDir() string {
+	_, fn, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(fn), "testdata", "fixture")
+}
+
+// FixtureFile returns the full path to the source file of the fixture program
+func FixtureFile() string {
+	return filepath.Join(FixtureDir(), "main.go")
+}
+
+// FixtureLine returns the line number in the fixture program of the line with
+// given break marker comment, e.g., "add" for the line with // break: add
+func FixtureLine(marker string) int {
+	f, err := os.Open(FixtureFile())
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	ln := 0
+	for sc.Scan() {
+		ln++
+		if strings.HasSuffix(sc.Text(), "// break: "+marker) {
+			return ln
+		}
+	}
+	return 0
+}
+
+// Conformance runs the conformance test suite on the debugger returned by
+// given function: it breaks in a function of the fixture program, checks the
+// stack and variables there, steps over a line and out of the function,
+// and continues to the end of the program.
+func Conformance(t *testing.T, newDbg NewFunc) {
+	fpath := FixtureFile()
+	addLn := FixtureLine("add")
+	mainLn := FixtureLine("main")
+	if addLn == 0 || mainLn == 0 {
+		t.Fatalf("fixture program is missing its break markers: %s", fpath)
+	}
+
+	stat := make(chan gidebug.Status, 10)
+	pars := gidebug.DefaultParams
+	pars.Mode = gidebug.Exec
+	pars.StatFunc = func(st gidebug.Status) {
+		stat <- st
+	}
+	dbg, err := newDbg(FixtureDir(), &pars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbg.Detach(true)
+	if err := waitReady(stat); err != nil {
+		t.Fatal(err)
+	}
+	if !dbg.IsActive() {
+		t.Fatal("should be active when ready")
+	}
+
+	br, err := dbg.SetBreak(fpath, addLn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.ID <= 0 || br.FPath != fpath || br.Line != addLn {
+		t.Errorf("break should be at: %s:%d  was: %d %s:%d", fpath, addLn, br.ID, br.FPath, br.Line)
+	}
+	bks, err := dbg.ListBreaks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bk, _ := gidebug.BreakByFile(bks, fpath, addLn); bk == nil || bk.ID != br.ID {
+		t.Errorf("break at: %s:%d should be listed, list was: %v", fpath, addLn, bks)
+	}
+
+	all := &gidebug.AllState{Mode: gidebug.Exec, Breaks: bks}
+	ds := lastState(dbg.Continue(all))
+	if ds == nil || ds.Exited {
+		t.Fatalf("should have stopped at the break, state was: %+v", ds)
+	}
+	checkAt(t, "break", &ds.Thread, fpath, addLn)
+
+	if err := dbg.InitAllState(all); err != nil {
+		t.Fatal(err)
+	}
+	if all.CurBreak != br.ID {
+		t.Errorf("current break should be: %d  was: %d", br.ID, all.CurBreak)
+	}
+	if len(all.Stack) < 2 {
+		t.Fatalf("stack should have at least 2 frames, has: %d", len(all.Stack))
+	}
+	if fr := all.Stack[0]; !strings.HasSuffix(fr.Func, "add") || fr.Line != addLn {
+		t.Errorf("frame 0 should be in add at line: %d  was: %s at: %d", addLn, fr.Func, fr.Line)
+	}
+	if fr := all.Stack[1]; !strings.HasSuffix(fr.Func, "main") || fr.FPath != fpath || fr.Line != mainLn {
+		t.Errorf("frame 1 should be in main at: %s:%d  was: %s at: %s:%d", fpath, mainLn, fr.Func, fr.FPath, fr.Line)
+	}
+
+	tid := dbg.CurThreadID(all)
+	vrs, err := dbg.ListVars(tid, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVar(t, vrs, "a", "1")
+	checkVar(t, vrs, "b", "2")
+	if vr, err := dbg.GetVar("b", tid, 0); err != nil || vr.Value != "2" {
+		t.Errorf("b should be 2, was: %v %v", vr, err)
+	}
+	if vrs, err := dbg.ListVars(tid, 1); err != nil {
+		t.Error(err)
+	} else {
+		checkVar(t, vrs, "x", "1")
+	}
+
+	ds, err = dbg.StepOver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAt(t, "step over", &ds.Thread, fpath, addLn+1)
+	if err := dbg.InitAllState(all); err != nil {
+		t.Fatal(err)
+	}
+	tid = dbg.CurThreadID(all)
+	if vr, err := dbg.GetVar("sum", tid, 0); err != nil || vr.Value != "3" {
+		t.Errorf("sum should be 3, was: %v %v", vr, err)
+	}
+
+	ds, err = dbg.StepOut()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAt(t, "step out", &ds.Thread, fpath, mainLn)
+	if !strings.HasSuffix(ds.Thread.Func, "main") {
+		t.Errorf("step out should be in main, was in: %s", ds.Thread.Func)
+	}
+
+	gvs, err := dbg.ListGlobalVars("counter$")
+	if err != nil {
+		t.Error(err)
+	}
+	for _, gv := range gvs {
+		if gv.Nm == "main.counter" { // debuggers may or may not qualify globals
+			gv.Nm = "counter"
+		}
+	}
+	checkVar(t, gvs, "counter", "3")
+
+	wb, err := dbg.SetWatch("counter")
+	switch {
+	case err == gidebug.NotSupportedErr:
+	case err != nil:
+		t.Error(err)
+	default:
+		if wb.ID <= 0 || wb.Kind != gidebug.BreakWatch || wb.Expr != "counter" {
+			t.Errorf("watch should be on counter, was: %+v", wb)
+		}
+		if bks, err = dbg.ListBreaks(); err != nil {
+			t.Fatal(err)
+		}
+		if bk, _ := gidebug.BreakByID(bks, wb.ID); bk == nil || bk.Kind != gidebug.BreakWatch {
+			t.Errorf("watch on counter should be listed, list was: %v", bks)
+		}
+		if err := dbg.ClearBreak(wb.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dbg.ClearBreak(br.ID); err != nil {
+		t.Fatal(err)
+	}
+	if bks, err = dbg.ListBreaks(); err != nil {
+		t.Fatal(err)
+	}
+	if bk, _ := gidebug.BreakByFile(bks, fpath, addLn); bk != nil {
+		t.Errorf("break at: %s:%d should have been cleared", fpath, addLn)
+	}
+
+	ds = lastState(dbg.Continue(all))
+	if ds == nil || !ds.Exited || ds.ExitStatus != 0 {
+		t.Errorf("should have exited with status 0, state was: %+v", ds)
+	}
+}
+
+// waitReady waits for the Ready status
+func waitReady(stat chan gidebug.Status) error {
+	for {
+		select {
+		case st := <-stat:
+			switch st {
+			case gidebug.Ready:
+				return nil
+			case gidebug.Error:
+				return gidebug.NotStartedErr
+			}
+		case <-time.After(ReadyTimeout):
+			return gidebug.NotStartedErr
+		}
+	}
+}
+
+// lastState returns the last state from a Continue channel
+func lastState(sc <-chan *gidebug.State) *gidebug.State {
+	var ds *gidebug.State
+	for st := range sc {
+		ds = st
+	}
+	return ds
+}
+
+// checkAt checks that the thread is at given file and line
+func checkAt(t *testing.T, what string, th *gidebug.Thread, fpath string, line int) {
+	t.Helper()
+	if th.FPath != fpath || th.Line != line {
+		t.Errorf("%s should be at: %s:%d  was at: %s:%d", what, fpath, line, th.FPath, th.Line)
+	}
+}
+
+// checkVar checks that the list has a variable of given name and value
+func checkVar(t *testing.T, vrs []*gidebug.Variable, name, val string) {
+	t.Helper()
+	for _, vr := range vrs {
+		if vr.Nm == name {
+			if vr.Value != val {
+				t.Errorf("%s should be: %s  was: %s", name, val, vr.Value)
+			}
+			return
+		}
+	}
+	t.Errorf("variable %s not found in: %v", name, vrs)
+}
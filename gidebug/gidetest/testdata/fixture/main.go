@@ -0,0 +1,23 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// fixture is the program that the gidetest conformance suite debugs --
+// the suite finds the lines to break at by their marker comments.
+package main
+
+import "fmt"
+
+var counter = 3
+
+func add(a, b int) int {
+	sum := a + b // break: add
+	return sum
+}
+
+func main() {
+	x := 1
+	y := add(x, 2) // break: main
+	counter += y
+	fmt.Println("sum:", y)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"path/filepath"
+
+	"github.com/goki/gi/gi"
+)
+
+// LaunchConfig is a named configuration for launching the debugger: what
+// program to debug, in what mode, and with what args and environment.
+// Relative paths are relative to the project root.
+type LaunchConfig struct {
+	Name      string      `desc:"name of the configuration, as shown in the debug toolbar"`
+	Mode      Modes       `desc:"mode for running the debugger: Exec builds and debugs the package, Test builds and debugs its tests, Attach attaches to a running process, Core examines a core dump, Remote connects to a headless debugger server, and Dump browses a saved goroutine dump"`
+	Program   gi.FileName `desc:"for Exec and Test, the package directory (or a file in it) to build and debug -- for Attach and Core, the executable"`
+	Args      []string    `desc:"args to pass to the program"`
+	Env       []string    `desc:"environment variables (NAME=value) to add for the program"`
+	WorkDir   gi.FileName `desc:"working directory for the program -- if empty, the package directory"`
+	BuildTags []string    `desc:"build tags to use in building the program, for Exec and Test modes"`
+	TestRun   string      `desc:"regular expression selecting the tests to run, for Test mode (as in go test -run) -- if empty, all tests are run"`
+	PID       uint64      `desc:"process id number to attach to, for Attach mode"`
+	CoreFile  gi.FileName `desc:"core dump file to examine, for Core mode, or goroutine dump (e.g., from a panic) or exported debug dump to browse, for Dump mode"`
+	Addr      string      `desc:"host:port address of the headless debugger server to connect to, for Remote mode, e.g., as started with: dlv exec --headless --listen host:port"`
+	PathMaps  PathMaps    `desc:"rules mapping source paths on the remote machine (or in the container) to local ones, for Remote mode"`
+}
+
+// AbsPath returns given path made absolute relative to the root path
+func (lc *LaunchConfig) AbsPath(fpath gi.FileName, rootPath string) string {
+	fp := string(fpath)
+	if fp == "" || filepath.IsAbs(fp) {
+		return fp
+	}
+	return filepath.Join(rootPath, fp)
+}
+
+// ProgramPath returns the full path to the program, for given project root path
+func (lc *LaunchConfig) ProgramPath(rootPath string) string {
+	fp := lc.AbsPath(lc.Program, rootPath)
+	if fp == "" {
+		return rootPath
+	}
+	return fp
+}
+
+// SetParams sets the debugger params from the launch configuration, for
+// given project root path
+func (lc *LaunchConfig) SetParams(pars *Params, rootPath string) {
+	pars.Mode = lc.Mode
+	pars.PID = lc.PID
+	pars.CoreFile = lc.AbsPath(lc.CoreFile, rootPath)
+	pars.ProgArgs = lc.Args
+	pars.Env = lc.Env
+	pars.WorkDir = lc.AbsPath(lc.WorkDir, rootPath)
+	pars.BuildTags = lc.BuildTags
+	pars.TestRun = lc.TestRun
+	pars.Addr = lc.Addr
+	pars.PathMaps = make(PathMaps, len(lc.PathMaps))
+	for i, pm := range lc.PathMaps {
+		pars.PathMaps[i] = PathMap{Remote: pm.Remote, Local: lc.AbsPath(gi.FileName(pm.Local), rootPath)}
+	}
+}
+
+// LaunchConfigs is a list of launch configurations
+type LaunchConfigs []*LaunchConfig
+
+// ByName returns the launch configuration with given name, and its index,
+// or nil, -1 if not found
+func (lc *LaunchConfigs) ByName(name string) (*LaunchConfig, int) {
+	if name == "" {
+		return nil, -1
+	}
+	for i, c := range *lc {
+		if c.Name == name {
+			return c, i
+		}
+	}
+	return nil, -1
+}
+
+// Names returns the names of the launch configurations
+func (lc *LaunchConfigs) Names() []string {
+	nms := make([]string, len(*lc))
+	for i, c := range *lc {
+		nms[i] = c.Name
+	}
+	return nms
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"strings"
+)
+
+// PathMap maps source file paths on a remote machine (or in a container)
+// to the corresponding local ones, by replacing a path prefix
+type PathMap struct {
+	Remote string `desc:"path prefix on the remote machine, e.g., /go/src/app"`
+	Local  string `desc:"corresponding local path prefix -- relative paths are relative to the project root"`
+}
+
+// PathMaps is a list of path mapping rules -- the rule with the longest
+// matching prefix applies
+type PathMaps []PathMap
+
+// ToLocal returns given remote path mapped to the local one -- it is
+// returned unchanged if no rule applies
+func (pm PathMaps) ToLocal(fpath string) string {
+	bi, bn := -1, -1
+	for i, m := range pm {
+		if n := pathPrefixLen(fpath, m.Remote); n > bn {
+			bi, bn = i, n
+		}
+	}
+	if bi < 0 {
+		return fpath
+	}
+	return strings.TrimSuffix(pm[bi].Local, "/") + fpath[bn:]
+}
+
+// ToRemote returns given local path mapped to the remote one -- it is
+// returned unchanged if no rule applies
+func (pm PathMaps) ToRemote(fpath string) string {
+	bi, bn := -1, -1
+	for i, m := range pm {
+		if n := pathPrefixLen(fpath, m.Local); n > bn {
+			bi, bn = i, n
+		}
+	}
+	if bi < 0 {
+		return fpath
+	}
+	return strings.TrimSuffix(pm[bi].Remote, "/") + fpath[bn:]
+}
+
+// pathPrefixLen returns the length of given path prefix if it is a prefix
+// of fpath at a path element boundary, and -1 otherwise
+func pathPrefixLen(fpath, prefix string) int {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || !strings.HasPrefix(fpath, prefix) {
+		return -1
+	}
+	if len(fpath) > len(prefix) && fpath[len(prefix)] != '/' {
+		return -1
+	}
+	return len(prefix)
+}
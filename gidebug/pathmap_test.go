@@ -0,0 +1,27 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import "testing"
+
+func TestPathMaps(t *testing.T) {
+	pm := PathMaps{{Remote: "/go/src/app", Local: "/home/me/app"}, {Remote: "/go/src/app/vendor/", Local: "/home/me/vendor"}}
+	tests := [][3]string{
+		{"/go/src/app/main.go", "/home/me/app/main.go", "/go/src/app/main.go"},
+		{"/go/src/app/vendor/x/x.go", "/home/me/vendor/x/x.go", "/go/src/app/vendor/x/x.go"},
+		{"/go/src/apple/main.go", "/go/src/apple/main.go", "/go/src/apple/main.go"},
+		{"/usr/local/go/src/fmt/print.go", "/usr/local/go/src/fmt/print.go", "/usr/local/go/src/fmt/print.go"},
+	}
+	for _, ts := range tests {
+		lp := pm.ToLocal(ts[0])
+		if lp != ts[1] {
+			t.Errorf("ToLocal(%v) should have been: %v  was: %v\n", ts[0], ts[1], lp)
+		}
+		rp := pm.ToRemote(lp)
+		if rp != ts[2] {
+			t.Errorf("ToRemote(%v) should have been: %v  was: %v\n", lp, ts[2], rp)
+		}
+	}
+}
@@ -55,6 +55,7 @@ type Task struct {
 	Thread    int      `format:"%#X" desc:"id of the current Thread this task is running on"`
 	StartLoc  Location `tableview:"-" desc:"where did this task first start running?"`
 	LaunchLoc Location `tableview:"-" desc:"at what point was this task launched from another task?"`
+	Blocked   string   `desc:"what this task is blocked waiting on (e.g., chan receive, mutex), as of the last Blocked update -- empty if not blocked"`
 }
 
 // TaskByID returns the given thread by ID from full list, and index.
@@ -90,19 +91,63 @@ type Frame struct {
 	Args     []*Variable `tableview:"-" desc:"values of the local function args at this frame"`
 }
 
+// Instr is one machine instruction, from a disassembly
+type Instr struct {
+	PC    uint64 `format:"%#X" desc:"address of the instruction"`
+	Text  string `desc:"the instruction, in assembly syntax"`
+	File  string `desc:"file name (trimmed up to point of project base path)"`
+	Line  int    `desc:"line within file"`
+	FPath string `tableview:"-" desc:"full path to file"`
+	Func  string `desc:"the name of the function"`
+	Dest  string `desc:"destination function of a call instruction"`
+	Break bool   `desc:"a breakpoint is set at this instruction"`
+	AtPC  bool   `desc:"this is the instruction the current thread is stopped at"`
+}
+
 // Break describes one breakpoint
 type Break struct {
-	ID    int    `inactive:"+" desc:"unique numerical ID of the breakpoint"`
-	On    bool   `width:"4" desc:"whether the breakpoint is currently enabled"`
-	PC    uint64 `inactive:"+" format:"%#X" desc:"program counter (address) -- may be subset of multiple"`
-	File  string `inactive:"+" desc:"file name (trimmed up to point of project base path)"`
-	Line  int    `inactive:"+" desc:"line within file"`
-	FPath string `inactive:"+" view:"-" tableview:"-" desc:"full path to file"`
-	Func  string `inactive:"+" desc:"the name of the function"`
-	Cond  string `desc:"condition for conditional breakbpoint"`
-	Trace bool   `width:"7" desc:"if true, execution does not stop -- just a message is reported when this point is hit"`
+	ID        int        `inactive:"+" desc:"unique numerical ID of the breakpoint"`
+	On        bool       `width:"4" desc:"whether the breakpoint is currently enabled"`
+	PC        uint64     `inactive:"+" format:"%#X" desc:"program counter (address) -- may be subset of multiple"`
+	File      string     `inactive:"+" desc:"file name (trimmed up to point of project base path)"`
+	Line      int        `inactive:"+" desc:"line within file"`
+	FPath     string     `inactive:"+" view:"-" tableview:"-" desc:"full path to file"`
+	Func      string     `inactive:"+" desc:"the name of the function"`
+	Cond      string     `desc:"condition for conditional breakbpoint"`
+	Trace     bool       `width:"7" desc:"if true, execution does not stop -- just a message is reported when this point is hit"`
+	TraceVars string     `desc:"for Trace breakpoints, comma-separated list of expressions to evaluate and record in the Trace log each time it is hit"`
+	Kind      BreakKinds `inactive:"+" desc:"kind of breakpoint: at a source line, an instruction address, the start of a function, or a data watchpoint"`
+	Expr      string     `inactive:"+" desc:"for Func breakpoints, the name of the function, and for Watch, the expression being watched"`
 }
 
+// BreakKinds are the different kinds of breakpoints
+type BreakKinds int32
+
+const (
+	// BreakLine stops at the start of a source File, Line
+	BreakLine BreakKinds = iota
+
+	// BreakAddr stops at the instruction at address PC
+	BreakAddr
+
+	// BreakFunc stops at the start of the function named in Expr
+	BreakFunc
+
+	// BreakWatch is a data watchpoint that stops when the variable
+	// expression in Expr is written
+	BreakWatch
+
+	// BreakKindsN is the number of breakpoint kinds
+	BreakKindsN
+)
+
+//go:generate stringer -type=BreakKinds
+
+var KiT_BreakKinds = kit.Enums.AddEnum(BreakKindsN, kit.NotBitFlag, nil)
+
+func (ev BreakKinds) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *BreakKinds) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
 // BreakByID returns the given breakpoint by ID from full list, and index.
 // returns nil, -1 if not found.
 func BreakByID(bks []*Break, id int) (*Break, int) {
@@ -115,10 +160,22 @@ func BreakByID(bks []*Break, id int) (*Break, int) {
 }
 
 // BreakByFile returns the given breakpoint by file path and line from list.
+// only BreakLine breakpoints are considered.
 // returns nil, -1 if not found.
 func BreakByFile(bks []*Break, fpath string, line int) (*Break, int) {
 	for i, br := range bks {
-		if br.FPath == fpath && br.Line == line {
+		if br.Kind == BreakLine && br.FPath == fpath && br.Line == line {
+			return br, i
+		}
+	}
+	return nil, -1
+}
+
+// BreakByPC returns the given instruction-level breakpoint by address from
+// list.  returns nil, -1 if not found.
+func BreakByPC(bks []*Break, pc uint64) (*Break, int) {
+	for i, br := range bks {
+		if br.Kind == BreakAddr && br.PC == pc {
 			return br, i
 		}
 	}
@@ -134,34 +191,47 @@ func SortBreaks(brk []*Break) {
 
 // State represents the current immediate execution state of the debugger.
 type State struct {
-	Thread     Thread `desc:"currently executing system thread"`
-	Task       Task   `desc:"currently executing task"`
-	Running    bool   `desc:"true if the process is running and no other information can be collected."`
-	NextUp     bool   `desc:"if true, a Next or Step is already in progress and another should not be attempted until after a Continue"`
-	Exited     bool   `desc:"if true, the program has exited"`
-	ExitStatus int    `desc:"indicates the exit status if Exited"`
-	Err        error  `desc:"error communicated to client -- if non-empty, something bad happened"`
-	CurTrace   int    `desc:"if this is > 0, then we just hit that tracepoint -- the Continue process will continue execution"`
+	Thread     Thread      `desc:"currently executing system thread"`
+	Task       Task        `desc:"currently executing task"`
+	Running    bool        `desc:"true if the process is running and no other information can be collected."`
+	NextUp     bool        `desc:"if true, a Next or Step is already in progress and another should not be attempted until after a Continue"`
+	Exited     bool        `desc:"if true, the program has exited"`
+	ExitStatus int         `desc:"indicates the exit status if Exited"`
+	Err        error       `desc:"error communicated to client -- if non-empty, something bad happened"`
+	CurTrace   int         `desc:"if this is > 0, then we just hit that tracepoint -- the Continue process will continue execution"`
+	Trace      *TraceEvent `desc:"if CurTrace > 0, the record of the tracepoint hit"`
+	Returns    []*Variable `desc:"return values of the function just called or stepped out of"`
 }
 
 // AllState holds all relevant state information.
 // This can be maintained and updated in the debug view.
 type AllState struct {
-	Mode       Modes       `desc:"mode we're running in"`
-	Status     Status      `desc:"overall debugger status"`
-	State      State       `desc:"current run state"`
-	CurThread  int         `desc:"id of the current system thread to examine"`
-	CurTask    int         `desc:"id of the current task to examine"`
-	CurFrame   int         `desc:"frame number within current thread"`
-	CurBreak   int         `desc:"current breakpoint that we stopped at -- will be 0 if none, after UpdateState"`
-	Breaks     []*Break    `desc:"all breakpoints that have been set -- some may not be On"`
-	CurBreaks  []*Break    `desc:"current, active breakpoints as retrieved from debugger"`
-	Threads    []*Thread   `desc:"all system threads"`
-	Tasks      []*Task     `desc:"all tasks"`
-	Stack      []*Frame    `desc:"current stack frame for current thread / task"`
-	Vars       []*Variable `desc:"current local variables and args for current frame"`
-	GlobalVars []*Variable `desc:"global variables for current thread / task"`
-	FindFrames []*Frame    `desc:"current find-frames result"`
+	Mode              Modes         `desc:"mode we're running in"`
+	Status            Status        `desc:"overall debugger status"`
+	State             State         `desc:"current run state"`
+	CurThread         int           `desc:"id of the current system thread to examine"`
+	CurTask           int           `desc:"id of the current task to examine"`
+	CurFrame          int           `desc:"frame number within current thread"`
+	CurBreak          int           `desc:"current breakpoint that we stopped at -- will be 0 if none, after UpdateState"`
+	Breaks            []*Break      `desc:"all breakpoints that have been set -- some may not be On"`
+	CurBreaks         []*Break      `desc:"current, active breakpoints as retrieved from debugger"`
+	Threads           []*Thread     `desc:"all system threads"`
+	Tasks             []*Task       `desc:"all tasks"`
+	Stack             []*Frame      `desc:"current stack frame for current thread / task"`
+	Vars              []*Variable   `desc:"current local variables and args for current frame"`
+	GlobalVars        []*Variable   `desc:"global variables for current thread / task"`
+	FindFrames        []*Frame      `desc:"current find-frames result"`
+	Disasm            []*Instr      `desc:"disassembly of the function of the current frame"`
+	Traces            []*TraceEvent `desc:"log of tracepoint hits, in order"`
+	MemAddr           uint64        `format:"%#X" desc:"start address of the memory being examined -- 0 if none"`
+	MemLen            int           `desc:"number of bytes of memory being examined"`
+	Mem               []byte        `desc:"memory being examined, starting at MemAddr -- updated at each stop"`
+	PrevMem           []byte        `desc:"memory at MemAddr as of the previous stop, for highlighting changes"`
+	PrevFunc          string        `desc:"function of the frame that PrevVars are from"`
+	PrevVars          []*Variable   `desc:"local variables as of the previous stop, for highlighting changes"`
+	PrevGlobalVars    []*Variable   `desc:"global variables as of the previous stop, for highlighting changes"`
+	RemovedVars       []*Variable   `desc:"local variables present at the previous stop that are no longer present"`
+	RemovedGlobalVars []*Variable   `desc:"global variables present at the previous stop that are no longer present"`
 }
 
 // BlankState initializes state with a blank initial state with the various slices
@@ -197,6 +267,32 @@ func (as *AllState) BreakByFile(fpath string, line int) (*Break, int) {
 	return BreakByFile(as.Breaks, fpath, line)
 }
 
+// BreakByPC returns the given instruction-level breakpoint by address.
+// returns nil, -1 if not found.
+func (as *AllState) BreakByPC(pc uint64) (*Break, int) {
+	return BreakByPC(as.Breaks, pc)
+}
+
+// AddBreakPC adds an instruction-level breakpoint at given address, which is
+// within given file path and line, to full list of breaks.
+// checks for an existing and turns it on if so.
+func (as *AllState) AddBreakPC(pc uint64, fpath string, line int) *Break {
+	br, _ := as.BreakByPC(pc)
+	if br != nil {
+		br.On = true
+		return br
+	}
+	br = &Break{}
+	br.On = true
+	br.Kind = BreakAddr
+	br.PC = pc
+	br.FPath = fpath
+	br.File = giv.DirAndFile(fpath)
+	br.Line = line
+	as.Breaks = append(as.Breaks, br)
+	return br
+}
+
 // AddBreak adds file path and line to full list of breaks.
 // checks for an existing and turns it on if so.
 func (as *AllState) AddBreak(fpath string, line int) *Break {
@@ -214,6 +310,18 @@ func (as *AllState) AddBreak(fpath string, line int) *Break {
 	return br
 }
 
+// DeleteBreak deletes given break from full list.
+// Returns true if deleted.
+func (as *AllState) DeleteBreak(br *Break) bool {
+	for i, b := range as.Breaks {
+		if b == br {
+			as.Breaks = append(as.Breaks[:i], as.Breaks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteBreakByID deletes given break by ID from full list.
 // Returns true if deleted.
 func (as *AllState) DeleteBreakByID(id int) bool {
@@ -230,7 +338,7 @@ func (as *AllState) DeleteBreakByID(id int) bool {
 // Returns true if deleted.
 func (as *AllState) DeleteBreakByFile(fpath string, line int) bool {
 	for i, br := range as.Breaks {
-		if br.FPath == fpath && br.Line == line {
+		if br.Kind == BreakLine && br.FPath == fpath && br.Line == line {
 			as.Breaks = append(as.Breaks[:i], as.Breaks[i+1:]...)
 			return true
 		}
@@ -238,10 +346,121 @@ func (as *AllState) DeleteBreakByFile(fpath string, line int) bool {
 	return false
 }
 
+// BreakByExpr returns the given function or watch breakpoint by its
+// function name or watch expression.  returns nil, -1 if not found.
+func (as *AllState) BreakByExpr(kind BreakKinds, expr string) (*Break, int) {
+	for i, br := range as.Breaks {
+		if br.Kind == kind && br.Expr == expr {
+			return br, i
+		}
+	}
+	return nil, -1
+}
+
+// AddBreakExpr adds a function or watch breakpoint of given kind, for
+// given function name or watch expression, to full list of breaks.
+// checks for an existing and turns it on if so.
+func (as *AllState) AddBreakExpr(kind BreakKinds, expr string) *Break {
+	br, _ := as.BreakByExpr(kind, expr)
+	if br != nil {
+		br.On = true
+		return br
+	}
+	br = &Break{}
+	br.On = true
+	br.Kind = kind
+	br.Expr = expr
+	if kind == BreakFunc {
+		br.Func = expr
+	}
+	as.Breaks = append(as.Breaks, br)
+	return br
+}
+
+// SaveBreaks returns a copy of the breaks, for saving, without the
+// IDs assigned by the current debugger.  Instruction-level breaks are
+// not saved, as their addresses only hold for the current executable.
+func (as *AllState) SaveBreaks() []*Break {
+	bks := make([]*Break, 0, len(as.Breaks))
+	for _, br := range as.Breaks {
+		if br.ID < 0 || br.Kind == BreakAddr {
+			continue
+		}
+		nb := *br
+		nb.ID = 0
+		bks = append(bks, &nb)
+	}
+	return bks
+}
+
+// DeleteBreakByPC deletes given instruction-level break by address from
+// full list.  Returns true if deleted.
+func (as *AllState) DeleteBreakByPC(pc uint64) bool {
+	_, i := as.BreakByPC(pc)
+	if i < 0 {
+		return false
+	}
+	as.Breaks = append(as.Breaks[:i], as.Breaks[i+1:]...)
+	return true
+}
+
+// SetMem sets the memory being examined, saving the previous memory at
+// the same address in PrevMem
+func (as *AllState) SetMem(mem []byte) {
+	as.PrevMem = as.Mem
+	as.Mem = mem
+}
+
+// MemChanged returns true if memory byte at given offset from MemAddr
+// has changed since the previous stop
+func (as *AllState) MemChanged(off int) bool {
+	if off < 0 || off >= len(as.Mem) || off >= len(as.PrevMem) {
+		return false
+	}
+	return as.Mem[off] != as.PrevMem[off]
+}
+
+// SavePrevVars saves the current Vars and GlobalVars as those of the
+// previous stop, for highlighting changes at the next stop with DiffVars
+func (as *AllState) SavePrevVars() {
+	as.PrevFunc = ""
+	if cf := as.StackFrame(as.CurFrame); cf != nil {
+		as.PrevFunc = cf.Func
+	}
+	as.PrevVars = as.Vars
+	as.PrevGlobalVars = as.GlobalVars
+	ClearPrevVars(as.PrevVars)
+	ClearPrevVars(as.PrevGlobalVars)
+}
+
+// DiffVars sets the Change of the current Vars and GlobalVars relative to
+// those of the previous stop, and RemovedVars and RemovedGlobalVars to
+// those no longer present.  Vars are only compared if the current frame is
+// in the same function as PrevVars.
+func (as *AllState) DiffVars() {
+	var pvars []*Variable
+	if cf := as.StackFrame(as.CurFrame); cf != nil && cf.Func == as.PrevFunc {
+		pvars = as.PrevVars
+	}
+	as.RemovedVars = DiffVars(pvars, as.Vars)
+	as.RemovedGlobalVars = DiffVars(as.PrevGlobalVars, as.GlobalVars)
+}
+
 // MergeBreaks merges the current breaks with AllBreaks -- any not in
-// Cur are indicated as !On
+// Cur are indicated as !On, except those not yet set (ID 0) and watchpoints,
+// which the debugger clears when out of scope: these stay On, with ID 0,
+// so the next UpdateBreaks sets them again.
 func (as *AllState) MergeBreaks() {
 	for _, br := range as.Breaks {
+		if br.ID <= 0 {
+			continue
+		}
+		if br.Kind == BreakWatch {
+			if c, _ := BreakByID(as.CurBreaks, br.ID); c == nil {
+				br.ID = 0
+			}
+			continue
+		}
 		br.On = false
 	}
 	for _, br := range as.CurBreaks {
@@ -253,8 +472,12 @@ func (as *AllState) MergeBreaks() {
 			br.On = true
 			as.Breaks = append(as.Breaks, br)
 		} else {
+			kind := ab.Kind
+			expr := ab.Expr
 			*ab = *br
 			ab.On = true
+			ab.Kind = kind
+			ab.Expr = expr
 		}
 	}
 	SortBreaks(as.Breaks)
@@ -0,0 +1,158 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goki/ki/kit"
+)
+
+// TaskGroupings are the different ways of grouping tasks
+type TaskGroupings int32
+
+const (
+	// GroupByNone shows all tasks in one group
+	GroupByNone TaskGroupings = iota
+
+	// GroupByFunc groups tasks by the function they are currently in
+	GroupByFunc
+
+	// GroupByStart groups tasks by the function they started running in
+	GroupByStart
+
+	// GroupByLaunch groups tasks by the location they were launched from
+	GroupByLaunch
+
+	// TaskGroupingsN is the number of task groupings
+	TaskGroupingsN
+)
+
+//go:generate stringer -type=TaskGroupings
+
+var KiT_TaskGroupings = kit.Enums.AddEnum(TaskGroupingsN, kit.NotBitFlag, nil)
+
+func (ev TaskGroupings) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TaskGroupings) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// TaskGroupAll is the key of the one group when not grouping
+const TaskGroupAll = "(all)"
+
+// GroupKey returns the key for grouping given task
+func (gp TaskGroupings) GroupKey(tk *Task) string {
+	switch gp {
+	case GroupByFunc:
+		return tk.Func
+	case GroupByStart:
+		return tk.StartLoc.Func
+	case GroupByLaunch:
+		if tk.LaunchLoc.File == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s:%d", tk.LaunchLoc.File, tk.LaunchLoc.Line)
+	}
+	return TaskGroupAll
+}
+
+// TaskGroup is a group of tasks sharing the same key
+type TaskGroup struct {
+	Key     string  `width:"60" desc:"function or location that the tasks in this group share"`
+	Count   int     `desc:"number of tasks in the group"`
+	Blocked int     `desc:"number of tasks in the group that are blocked, as of the last Blocked update"`
+	Tasks   []*Task `view:"-" tableview:"-" desc:"the tasks in the group"`
+}
+
+// TaskMatches returns true if given task matches given filter string,
+// in its function, file, start or launch location (case insensitive).
+// An empty filter matches all tasks.
+func TaskMatches(tk *Task, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, s := range []string{tk.Func, tk.File, tk.StartLoc.Func, tk.LaunchLoc.File, tk.Blocked} {
+		if strings.Contains(strings.ToLower(s), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupTasks returns the tasks matching filter, grouped according to gp,
+// sorted by decreasing count.  If blockedOnly, only blocked tasks are
+// included.
+func GroupTasks(tasks []*Task, gp TaskGroupings, filter string, blockedOnly bool) []*TaskGroup {
+	var grps []*TaskGroup
+	gmap := make(map[string]*TaskGroup)
+	for _, tk := range tasks {
+		if blockedOnly && tk.Blocked == "" {
+			continue
+		}
+		if !TaskMatches(tk, filter) {
+			continue
+		}
+		key := gp.GroupKey(tk)
+		tg, has := gmap[key]
+		if !has {
+			tg = &TaskGroup{Key: key}
+			gmap[key] = tg
+			grps = append(grps, tg)
+		}
+		tg.Count++
+		if tk.Blocked != "" {
+			tg.Blocked++
+		}
+		tg.Tasks = append(tg.Tasks, tk)
+	}
+	sort.SliceStable(grps, func(i, j int) bool {
+		return grps[i].Count > grps[j].Count
+	})
+	return grps
+}
+
+// TaskBlockers are the blocking functions that indicate what a task is
+// blocked on, as function name prefixes in order of precedence, with the
+// corresponding reason.  Only the functions that block are included, not
+// e.g., Unlock, which is also in a task's stack when it is not blocked.
+var TaskBlockers = [][2]string{
+	{"runtime.chanrecv", "chan receive"},
+	{"runtime.chansend", "chan send"},
+	{"runtime.selectgo", "select"},
+	{"runtime.block", "select (no cases)"},
+	{"sync.(*RWMutex).RLock", "rw mutex"},
+	{"sync.(*RWMutex).Lock", "rw mutex"},
+	{"sync.(*Mutex).Lock", "mutex"},
+	{"internal/sync.(*Mutex).Lock", "mutex"},
+	{"sync.(*WaitGroup).Wait", "wait group"},
+	{"sync.(*Cond).Wait", "cond"},
+	{"sync.runtime_SemacquireRWMutex", "rw mutex"},
+	{"sync.runtime_SemacquireMutex", "mutex"},
+	{"sync.runtime_Semacquire", "semaphore"},
+	{"runtime.semacquire", "semaphore"},
+	{"internal/poll.runtime_pollWait", "io wait"},
+}
+
+// TaskBlockDepth is the number of frames from the top of the stack that
+// are checked for a blocking call: a blocking call further down does not
+// block the task.
+var TaskBlockDepth = 8
+
+// BlockedReason returns what the task with given stack is blocked on,
+// e.g., "chan receive" or "mutex", or "" if it is not blocked.
+func BlockedReason(stack []*Frame) string {
+	if len(stack) > TaskBlockDepth {
+		stack = stack[:TaskBlockDepth]
+	}
+	for _, tb := range TaskBlockers {
+		for _, fr := range stack {
+			if strings.HasPrefix(fr.Func, tb[0]) {
+				return tb[1]
+			}
+		}
+	}
+	return ""
+}
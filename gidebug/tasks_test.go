@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import "testing"
+
+// stack returns a stack with given functions, from the top down
+func stack(funcs ...string) []*Frame {
+	st := make([]*Frame, len(funcs))
+	for i, fn := range funcs {
+		st[i] = &Frame{Depth: i, Func: fn}
+	}
+	return st
+}
+
+func TestBlockedReason(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack []*Frame
+		want  string
+	}{
+		{"running", stack("main.work", "main.main"), ""},
+		{"chan receive", stack("runtime.gopark", "runtime.chanrecv", "runtime.chanrecv1", "main.worker"), "chan receive"},
+		{"chan send", stack("runtime.gopark", "runtime.chansend", "runtime.chansend1", "main.worker"), "chan send"},
+		{"select", stack("runtime.gopark", "runtime.selectgo", "main.worker"), "select"},
+		{"mutex", stack("runtime.gopark", "runtime.goparkunlock", "runtime.semacquire1", "sync.runtime_SemacquireMutex", "sync.(*Mutex).lockSlow", "sync.(*Mutex).Lock", "main.worker"), "mutex"},
+		{"rw mutex read", stack("runtime.gopark", "runtime.semacquire1", "sync.runtime_SemacquireMutex", "sync.(*RWMutex).RLock", "main.worker"), "rw mutex"},
+		{"rw mutex write", stack("runtime.gopark", "runtime.semacquire1", "sync.runtime_SemacquireRWMutex", "sync.(*RWMutex).Lock", "main.worker"), "rw mutex"},
+		{"unlock", stack("sync.(*Mutex).Unlock", "main.worker"), ""},
+		{"runlock", stack("sync.(*RWMutex).RUnlock", "main.worker"), ""},
+		{"wait group", stack("runtime.gopark", "runtime.semacquire1", "sync.runtime_Semacquire", "sync.(*WaitGroup).Wait", "main.main"), "wait group"},
+		{"cond", stack("runtime.gopark", "runtime.goparkunlock", "sync.runtime_notifyListWait", "sync.(*Cond).Wait", "main.worker"), "cond"},
+		{"io wait", stack("runtime.gopark", "runtime.netpollblock", "internal/poll.runtime_pollWait", "internal/poll.(*pollDesc).wait", "os.(*File).Read"), "io wait"},
+		{"sleep", stack("runtime.gopark", "time.Sleep", "main.poll"), ""},
+		{"deep in stack", stack("main.f", "main.f", "main.f", "main.f", "main.f", "main.f", "main.f", "main.f", "sync.(*Mutex).Lock", "main.main"), ""},
+	}
+	for _, tt := range tests {
+		if got := BlockedReason(tt.stack); got != tt.want {
+			t.Errorf("%s: BlockedReason was: %q, want: %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGroupTasks(t *testing.T) {
+	tasks := []*Task{
+		{ID: 1, Func: "main.main", File: "main.go", StartLoc: Location{Func: "runtime.main"}},
+		{ID: 2, Func: "runtime.gopark", File: "proc.go", StartLoc: Location{Func: "main.worker"}, LaunchLoc: Location{File: "main.go", Line: 12}, Blocked: "chan receive"},
+		{ID: 3, Func: "runtime.gopark", File: "proc.go", StartLoc: Location{Func: "main.worker"}, LaunchLoc: Location{File: "main.go", Line: 12}, Blocked: "mutex"},
+		{ID: 4, Func: "main.serve", File: "serve.go", StartLoc: Location{Func: "main.serve"}, LaunchLoc: Location{File: "main.go", Line: 20}},
+	}
+	tests := []struct {
+		name        string
+		gp          TaskGroupings
+		filter      string
+		blockedOnly bool
+		keys        []string
+		counts      []int
+		blocked     []int
+	}{
+		{"none", GroupByNone, "", false, []string{TaskGroupAll}, []int{4}, []int{2}},
+		{"func", GroupByFunc, "", false, []string{"runtime.gopark", "main.main", "main.serve"}, []int{2, 1, 1}, []int{2, 0, 0}},
+		{"start", GroupByStart, "", false, []string{"main.worker", "runtime.main", "main.serve"}, []int{2, 1, 1}, []int{2, 0, 0}},
+		{"launch", GroupByLaunch, "", false, []string{"main.go:12", "", "main.go:20"}, []int{2, 1, 1}, []int{2, 0, 0}},
+		{"blocked only", GroupByFunc, "", true, []string{"runtime.gopark"}, []int{2}, []int{2}},
+		{"filter func", GroupByStart, "SERVE", false, []string{"main.serve"}, []int{1}, []int{0}},
+		{"filter blocked", GroupByNone, "mutex", false, []string{TaskGroupAll}, []int{1}, []int{1}},
+		{"no match", GroupByFunc, "nothing", false, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		grps := GroupTasks(tasks, tt.gp, tt.filter, tt.blockedOnly)
+		if len(grps) != len(tt.keys) {
+			t.Errorf("%s: should have %d groups, was: %d", tt.name, len(tt.keys), len(grps))
+			continue
+		}
+		for i, tg := range grps {
+			if tg.Key != tt.keys[i] || tg.Count != tt.counts[i] || tg.Blocked != tt.blocked[i] || len(tg.Tasks) != tg.Count {
+				t.Errorf("%s: group %d should be %q with %d tasks, %d blocked, was: %q %d %d %d", tt.name, i, tt.keys[i], tt.counts[i], tt.blocked[i], tg.Key, tg.Count, tg.Blocked, len(tg.Tasks))
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TraceVal is the value of one trace expression, evaluated when a
+// tracepoint is hit
+type TraceVal struct {
+	Expr  string `desc:"the expression evaluated"`
+	Value string `desc:"the value of the expression"`
+}
+
+// TraceEvent records one hit of a tracepoint
+type TraceEvent struct {
+	Time  time.Time  `desc:"time when the tracepoint was hit"`
+	Break int        `desc:"id of the tracepoint that was hit"`
+	Task  int        `desc:"id of the task (e.g., goroutine) that hit the tracepoint"`
+	File  string     `desc:"file name (trimmed up to point of project base path)"`
+	Line  int        `desc:"line within file"`
+	FPath string     `view:"-" tableview:"-" desc:"full path to file"`
+	Func  string     `desc:"the name of the function"`
+	Vals  []TraceVal `tableview:"-" desc:"values of the trace expressions of the tracepoint"`
+	Value string     `width:"60" json:"-" xml:"-" desc:"values of the trace expressions, as expr = value"`
+}
+
+// SetValue sets the Value summary string from Vals
+func (te *TraceEvent) SetValue() {
+	vs := make([]string, len(te.Vals))
+	for i, tv := range te.Vals {
+		vs[i] = tv.Expr + " = " + tv.Value
+	}
+	te.Value = strings.Join(vs, ", ")
+}
+
+// TraceExprs returns the list of trace expressions in given comma-separated
+// string, as in Break.TraceVars
+func TraceExprs(vars string) []string {
+	var exs []string
+	for _, ex := range strings.Split(vars, ",") {
+		ex = strings.TrimSpace(ex)
+		if ex != "" {
+			exs = append(exs, ex)
+		}
+	}
+	return exs
+}
+
+// TraceMatches returns true if given trace event matches given filter
+// string, in its function, file or values (case insensitive).
+// An empty filter matches all events.
+func TraceMatches(te *TraceEvent, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, s := range []string{te.Func, te.File, te.Value} {
+		if strings.Contains(strings.ToLower(s), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// TraceTimeFormat is the format for times of trace events in CSV
+const TraceTimeFormat = "2006-01-02 15:04:05.000000"
+
+// SaveTraces saves given trace events to given file, as JSON if it has
+// a .json extension, and otherwise as CSV, with one column per expression
+// after the location
+func SaveTraces(evs []*TraceEvent, fname string) error {
+	var b []byte
+	if strings.ToLower(filepath.Ext(fname)) == ".json" {
+		var err error
+		b, err = json.MarshalIndent(evs, "", "  ")
+		if err != nil {
+			return err
+		}
+	} else {
+		b = TracesCSV(evs)
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// TracesCSV returns given trace events in CSV format, with a header row,
+// and one column per distinct expression, in order of first appearance
+func TracesCSV(evs []*TraceEvent) []byte {
+	var exs []string
+	excol := make(map[string]int)
+	for _, te := range evs {
+		for _, tv := range te.Vals {
+			if _, has := excol[tv.Expr]; !has {
+				excol[tv.Expr] = len(exs)
+				exs = append(exs, tv.Expr)
+			}
+		}
+	}
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	hdr := append([]string{"Time", "Break", "Task", "File", "Line", "Func"}, exs...)
+	w.Write(hdr)
+	for _, te := range evs {
+		rec := make([]string, len(hdr))
+		rec[0] = te.Time.Format(TraceTimeFormat)
+		rec[1] = fmt.Sprintf("%d", te.Break)
+		rec[2] = fmt.Sprintf("%d", te.Task)
+		rec[3] = te.File
+		rec[4] = fmt.Sprintf("%d", te.Line)
+		rec[5] = te.Func
+		for _, tv := range te.Vals {
+			rec[6+excol[tv.Expr]] = tv.Value
+		}
+		w.Write(rec)
+	}
+	w.Flush()
+	return b.Bytes()
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import (
+	"reflect"
+
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// VarChanges are the ways a variable can change between debugger stops
+type VarChanges int32
+
+const (
+	// VarSame means the variable has the same value as at the previous stop,
+	// or there is nothing to compare it to
+	VarSame VarChanges = iota
+
+	// VarAdded means the variable was not present at the previous stop
+	VarAdded
+
+	// VarChanged means the value of the variable, or of any of its
+	// children, is different from the previous stop
+	VarChanged
+
+	// VarRemoved means the variable was present at the previous stop,
+	// but is not now
+	VarRemoved
+
+	// VarChangesN is the number of variable changes
+	VarChangesN
+)
+
+//go:generate stringer -type=VarChanges
+
+var KiT_VarChanges = kit.Enums.AddEnum(VarChangesN, kit.NotBitFlag, nil)
+
+func (ev VarChanges) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *VarChanges) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// DiffPrev sets the Change of this variable relative to given variable
+// from the previous stop (nil if it was not present), recursively for the
+// children present in both.  Children of prev that are not present here are
+// added as copies marked VarRemoved.  A variable whose children have
+// changed is also marked VarChanged.  Returns true if anything changed.
+func (vr *Variable) DiffPrev(prev *Variable) bool {
+	vr.Prev = prev
+	if prev == nil {
+		vr.SetChangeAll(VarAdded)
+		return true
+	}
+	vr.Change = VarSame
+	if vr.Value != prev.Value || vr.ElValue != prev.ElValue || vr.TypeStr != prev.TypeStr || vr.Len != prev.Len || !reflect.DeepEqual(vr.List, prev.List) || !reflect.DeepEqual(vr.Map, prev.Map) {
+		vr.Change = VarChanged
+	}
+	if !vr.HasChildren() || !prev.HasChildren() {
+		return vr.Change != VarSame
+	}
+	pkids := make(map[string]*Variable, len(prev.Kids))
+	for _, pk := range prev.Kids {
+		if pv := pk.(*Variable); pv.Change != VarRemoved {
+			pkids[pv.Nm] = pv
+		}
+	}
+	changed := false
+	for _, k := range vr.Kids {
+		kv := k.(*Variable)
+		pv, has := pkids[kv.Nm]
+		if kv.DiffPrev(pv) {
+			changed = true
+		}
+		if has {
+			delete(pkids, kv.Nm)
+		}
+	}
+	for _, pk := range prev.Kids {
+		if pv, has := pkids[pk.Name()]; !has || pv != pk.(*Variable) {
+			continue
+		}
+		rv := pk.Clone().(*Variable)
+		rv.SetChangeAll(VarRemoved)
+		vr.AddChild(rv)
+		changed = true
+	}
+	if changed && vr.Change == VarSame {
+		vr.Change = VarChanged
+	}
+	return vr.Change != VarSame
+}
+
+// SetChangeAll sets the Change of this variable and all of its children
+func (vr *Variable) SetChangeAll(ch VarChanges) {
+	vr.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d interface{}) bool {
+		k.(*Variable).Change = ch
+		return ki.Continue
+	})
+}
+
+// DiffVars sets the Change of each of the variables in cur relative to the
+// variable of the same name in prev, from the previous stop, and returns
+// copies of the variables in prev that are no longer present, marked
+// VarRemoved.  If prev is empty, there is nothing to compare to, and all
+// of cur are marked VarSame.
+func DiffVars(prev, cur []*Variable) []*Variable {
+	pvars := make(map[string]*Variable, len(prev))
+	for _, pv := range prev {
+		if pv != nil && pv.Nm != "" {
+			pvars[pv.Nm] = pv
+		}
+	}
+	if len(pvars) == 0 {
+		for _, vr := range cur {
+			if vr != nil {
+				vr.Prev = nil
+				vr.SetChangeAll(VarSame)
+			}
+		}
+		return nil
+	}
+	for _, vr := range cur {
+		if vr == nil || vr.Nm == "" {
+			continue
+		}
+		vr.DiffPrev(pvars[vr.Nm])
+		delete(pvars, vr.Nm)
+	}
+	var rem []*Variable
+	for _, pv := range prev {
+		if pv == nil || pv.Nm == "" {
+			continue
+		}
+		if _, has := pvars[pv.Nm]; !has {
+			continue
+		}
+		rv := pv.Clone().(*Variable)
+		rv.SetChangeAll(VarRemoved)
+		rem = append(rem, rv)
+	}
+	return rem
+}
+
+// ClearPrevVars clears the Prev of the given variables and all of their
+// children, so that only one previous stop is retained
+func ClearPrevVars(vrs []*Variable) {
+	for _, vr := range vrs {
+		if vr == nil {
+			continue
+		}
+		vr.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d interface{}) bool {
+			k.(*Variable).Prev = nil
+			return ki.Continue
+		})
+	}
+}
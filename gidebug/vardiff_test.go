@@ -0,0 +1,119 @@
+// Copyright (c) 2020, The Gide Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gidebug
+
+import "testing"
+
+// newVar returns a new variable with given name, value and children
+func newVar(name, value string, kids ...*Variable) *Variable {
+	vr := &Variable{Value: value}
+	vr.InitName(vr, name)
+	for _, k := range kids {
+		vr.AddChild(k)
+	}
+	return vr
+}
+
+// kidVar returns the child of given variable with given name, or nil
+func kidVar(vr *Variable, name string) *Variable {
+	k := vr.ChildByName(name, 0)
+	if k == nil {
+		return nil
+	}
+	return k.(*Variable)
+}
+
+func TestDiffVars(t *testing.T) {
+	prev := []*Variable{
+		newVar("a", "1"),
+		newVar("b", "2"),
+		newVar("s", "{...}", newVar("x", "1"), newVar("y", "2"), newVar("z", "{...}", newVar("q", "1")), newVar("r", "{...}", newVar("rk", "3"))),
+		newVar("gone", "5"),
+	}
+	cur := []*Variable{
+		newVar("a", "1"),
+		newVar("b", "3"),
+		newVar("s", "{...}", newVar("x", "1"), newVar("y", "3"), newVar("z", "{...}", newVar("q", "1")), newVar("w", "4")),
+		newVar("new", "{...}", newVar("nk", "7")),
+	}
+	rem := DiffVars(prev, cur)
+	a, b, s, nw := cur[0], cur[1], cur[2], cur[3]
+	if a.Change != VarSame || b.Change != VarChanged || b.Prev != prev[1] {
+		t.Errorf("a should be same and b changed from prev b, was: %v %v %v", a.Change, b.Change, b.Prev)
+	}
+	if nw.Change != VarAdded || kidVar(nw, "nk").Change != VarAdded {
+		t.Errorf("new and its children should be added, was: %v %v", nw.Change, kidVar(nw, "nk").Change)
+	}
+	if s.Change != VarChanged {
+		t.Errorf("s should be changed as its children changed, was: %v", s.Change)
+	}
+	kids := []struct {
+		name string
+		want VarChanges
+	}{
+		{"x", VarSame},
+		{"y", VarChanged},
+		{"z", VarSame},
+		{"w", VarAdded},
+		{"r", VarRemoved},
+	}
+	for _, kt := range kids {
+		kv := kidVar(s, kt.name)
+		if kv == nil {
+			t.Errorf("s.%s should be present", kt.name)
+		} else if kv.Change != kt.want {
+			t.Errorf("s.%s should be %v, was: %v", kt.name, kt.want, kv.Change)
+		}
+	}
+	if q := kidVar(kidVar(s, "z"), "q"); q.Change != VarSame {
+		t.Errorf("s.z.q should be same, was: %v", q.Change)
+	}
+	r := kidVar(s, "r")
+	pr := kidVar(prev[2], "r")
+	if r == nil || r == pr || kidVar(r, "rk").Change != VarRemoved || pr.Change != VarSame || kidVar(pr, "rk").Change != VarSame {
+		t.Errorf("s.r should be a copy of prev s.r marked removed, with children, leaving prev as is")
+	}
+	if len(rem) != 1 || rem[0].Nm != "gone" || rem[0] == prev[3] || rem[0].Change != VarRemoved || prev[3].Change != VarSame {
+		t.Errorf("gone should be returned as a copy marked removed, was: %v", rem)
+	}
+
+	// next stop: the removed copies from this stop are not compared again
+	next := []*Variable{
+		newVar("s", "{...}", newVar("x", "1"), newVar("y", "3"), newVar("z", "{...}", newVar("q", "1")), newVar("w", "4")),
+	}
+	DiffVars(cur, next)
+	if ns := next[0]; ns.Change != VarSame || kidVar(ns, "r") != nil || ns.NumChildren() != 4 {
+		t.Errorf("s should be same without r, was: %v with %d children", ns.Change, ns.NumChildren())
+	}
+
+	// nothing to compare to
+	DiffVars(nil, next)
+	if next[0].Change != VarSame || next[0].Prev != nil || kidVar(next[0], "y").Change != VarSame {
+		t.Errorf("should all be same with no prev, was: %v", next[0].Change)
+	}
+}
+
+func TestDiffPrevValues(t *testing.T) {
+	tests := []struct {
+		name string
+		prev *Variable
+		want VarChanges
+	}{
+		{"nil", nil, VarAdded},
+		{"same", &Variable{Value: "1", TypeStr: "int"}, VarSame},
+		{"value", &Variable{Value: "2", TypeStr: "int"}, VarChanged},
+		{"type", &Variable{Value: "1", TypeStr: "int64"}, VarChanged},
+		{"len", &Variable{Value: "1", TypeStr: "int", Len: 3}, VarChanged},
+		{"list", &Variable{Value: "1", TypeStr: "int", List: []string{"a"}}, VarChanged},
+	}
+	for _, tt := range tests {
+		vr := &Variable{Value: "1", TypeStr: "int"}
+		vr.InitName(vr, "v")
+		changed := vr.DiffPrev(tt.prev)
+		if vr.Change != tt.want || changed != (tt.want != VarSame) {
+			t.Errorf("%s: should be %v, was: %v %v", tt.name, tt.want, vr.Change, changed)
+		}
+	}
+}
@@ -33,6 +33,8 @@ type Variable struct {
 	List        []string             `tableview:"-" desc:"if kind is a list type (array, slice), and elements are primitive types, this is the contents"`
 	Map         map[string]string    `tableview:"-" desc:"if kind is a map, and elements are primitive types, this is the contents"`
 	MapVar      map[string]*Variable `tableview:"-" desc:"if kind is a map, and elements are not primitive types, this is the contents"`
+	Change      VarChanges           `inactive:"-" desc:"how the variable has changed since the previous stop"`
+	Prev        *Variable            `view:"-" tableview:"-" json:"-" xml:"-" desc:"this variable as of the previous stop, if any -- for diffing children that are retrieved later"`
 	Dbg         GiDebug              `view:"-" desc:"our debugger -- for getting further variable data"`
 }
 
@@ -53,6 +55,7 @@ func (vr *Variable) CopyFieldsFrom(frm interface{}) {
 	vr.List = fr.List
 	vr.Map = fr.Map
 	vr.MapVar = fr.MapVar
+	vr.Change = fr.Change
 	vr.Dbg = fr.Dbg
 }
 
@@ -74,7 +77,7 @@ var VariableProps = ki.Props{
 			"icon": "update",
 			"updtfunc": func(vri interface{}, act *gi.Action) {
 				vr := vri.(ki.Ki).Embed(KiT_Variable).(*Variable)
-				act.SetActiveState(!vr.HasChildren())
+				act.SetActiveState(!vr.HasChildren() && vr.Change != VarRemoved)
 			},
 		}},
 	},
@@ -207,6 +210,9 @@ func (vr *Variable) FollowPtr() {
 	}
 	updt := vr.UpdateStart()
 	vr.Dbg.FollowPtr(vr)
+	if vr.Prev != nil {
+		vr.DiffPrev(vr.Prev)
+	}
 	vr.UpdateEnd(updt)
 }
 
@@ -222,12 +228,20 @@ type VarParams struct {
 
 // Params are overall debugger parameters
 type Params struct {
-	Mode     Modes             `xml:"-" json:"-" view:"-" desc:"mode for running the debugger"`
-	PID      uint64            `xml:"-" json:"-" view:"-" desc:"process id number to attach to, for Attach mode"`
-	Args     []string          `desc:"optional extra args to pass to the debugger.  Use double-dash -- and then add args to pass args to the executable (double-dash is by itself as a separate arg first)"`
-	StatFunc func(stat Status) `xml:"-" json:"-" view:"-" desc:"status function for debugger updating status"`
-	VarList  VarParams         `desc:"parameters for level of detail on overall list of variables"`
-	GetVar   VarParams         `desc:"parameters for level of detail retrieving a specific variable"`
+	Mode      Modes             `xml:"-" json:"-" view:"-" desc:"mode for running the debugger"`
+	PID       uint64            `xml:"-" json:"-" view:"-" desc:"process id number to attach to, for Attach mode"`
+	CoreFile  string            `xml:"-" json:"-" view:"-" desc:"core dump file to examine, for Core mode -- the executable is the path passed to the debugger -- or the dump file to browse, for Dump mode"`
+	ProgArgs  []string          `xml:"-" json:"-" view:"-" desc:"args to pass to the program being debugged, from the launch configuration"`
+	Env       []string          `xml:"-" json:"-" view:"-" desc:"environment variables (NAME=value) to add for the program being debugged, from the launch configuration"`
+	WorkDir   string            `xml:"-" json:"-" view:"-" desc:"working directory for the program being debugged, from the launch configuration -- if empty, the directory of the program"`
+	BuildTags []string          `xml:"-" json:"-" view:"-" desc:"build tags to use in building the program, for Exec and Test modes, from the launch configuration"`
+	TestRun   string            `xml:"-" json:"-" view:"-" desc:"regular expression selecting the tests to run, for Test mode (as in go test -run), from the launch configuration"`
+	Addr      string            `xml:"-" json:"-" view:"-" desc:"host:port address of the headless debugger server to connect to, for Remote mode"`
+	PathMaps  PathMaps          `xml:"-" json:"-" view:"-" desc:"rules mapping source paths on the remote machine to local ones, for Remote mode, from the launch configuration"`
+	Args      []string          `desc:"optional extra args to pass to the debugger.  Use double-dash -- and then add args to pass args to the executable (double-dash is by itself as a separate arg first)"`
+	StatFunc  func(stat Status) `xml:"-" json:"-" view:"-" desc:"status function for debugger updating status"`
+	VarList   VarParams         `desc:"parameters for level of detail on overall list of variables"`
+	GetVar    VarParams         `desc:"parameters for level of detail retrieving a specific variable"`
 }
 
 // DefaultParams are default parameter values
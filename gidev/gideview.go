@@ -18,7 +18,6 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -82,6 +81,7 @@ type GideView struct {
 	ArgVals           gide.ArgVarVals         `json:"-" xml:"-" desc:"current arg var vals"`
 	Prefs             gide.ProjPrefs          `desc:"preferences for this project -- this is what is saved in a .gide project file"`
 	CurDbg            *gide.DebugView         `desc:"current debug view"`
+	Index             *gide.TrigramIndex      `json:"-" desc:"trigram index of file contents used by Find -- opened on first use"`
 	KeySeq1           key.Chord               `desc:"first key in sequence if needs2 key pressed"`
 	UpdtMu            sync.Mutex              `desc:"mutex for protecting overall updates to GideView"`
 }
@@ -476,6 +476,14 @@ func (ge *GideView) ConfigTextBuf(tb *giv.TextBuf) {
 	if tb.Complete != nil {
 		tb.Complete.LookupFunc = ge.LookupFun
 	}
+	gide.SetSnippetCompleter(tb, func() gide.SnippetList {
+		return gide.LangSnippets(tb.Info.Sup, &ge.Prefs)
+	})
+	tb.TextBufSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		gee := recv.Embed(KiT_GideView).(*GideView)
+		stb := send.Embed(giv.KiT_TextBuf).(*giv.TextBuf)
+		gide.BookmarksBufSig(gee, stb, giv.TextBufSignals(sig), data)
+	})
 
 	// these are now set in std textbuf..
 	// tb.SetSpellCorrect(tb, giv.SpellCorrectEdit)                    // always set -- option can override
@@ -929,6 +937,7 @@ func (ge *GideView) LinkViewFile(fnm gi.FileName) (*gide.TextView, int, bool) {
 	if fn == nil {
 		return nil, -1, false
 	}
+	ge.RecordNavPos()
 	tv, idx, ok := ge.TextViewForFileNode(fn)
 	if ok {
 		if idx == 1 {
@@ -1809,7 +1818,10 @@ func (ge *GideView) LookupFun(data interface{}, text string, posLn, posCh int) (
 	ofb.SetIcon("file-open")
 	ofb.ButtonSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 		if sig == int64(gi.ButtonClicked) {
-			ge.ViewFile(gi.FileName(ld.Filename))
+			ge.RecordNavPos()
+			if tv, _, ok := ge.ViewFile(gi.FileName(ld.Filename)); ok {
+				tv.SetCursorShow(lex.Pos{Ln: ld.StLine})
+			}
 			dlg.Close()
 		}
 	})
@@ -1837,6 +1849,7 @@ func (ge *GideView) Find(find, repl string, ignoreCase, regExp bool, loc gide.Fi
 		return
 	}
 	ge.Prefs.Find.IgnoreCase = ignoreCase
+	ge.Prefs.Find.Regexp = regExp
 	ge.Prefs.Find.Langs = langs
 	ge.Prefs.Find.Loc = loc
 
@@ -1846,6 +1859,7 @@ func (ge *GideView) Find(find, repl string, ignoreCase, regExp bool, loc gide.Fi
 		return
 	}
 	fv := fvi.Embed(gide.KiT_FindView).(*gide.FindView)
+	fv.CancelSearch()
 	fv.Config(ge)
 	fv.Time = time.Now()
 	ftv := fv.TextView()
@@ -1864,29 +1878,66 @@ func (ge *GideView) Find(find, repl string, ignoreCase, regExp bool, loc gide.Fi
 		adir, _ = filepath.Split(string(ond.FPath))
 	}
 
+	if loc != gide.FindLocFile {
+		if ge.Prefs.Find.Index {
+			ge.OpenIndex()
+		}
+		fs, err := gide.NewFileSearch(root, &ge.Prefs.Find, adir)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		fv.StartSearch(fs)
+		ge.FocusOnPanel(TabsIdx)
+		return
+	}
 	var res []gide.FileSearchResults
-	if loc == gide.FindLocFile {
-		if got {
-			if regExp {
-				re, err := regexp.Compile(find)
-				if err != nil {
-					log.Println(err)
-				} else {
-					cnt, matches := atv.Buf.SearchRegexp(re)
-					res = append(res, gide.FileSearchResults{ond, cnt, matches})
-				}
-			} else {
-				cnt, matches := atv.Buf.Search([]byte(find), ignoreCase, false)
-				res = append(res, gide.FileSearchResults{ond, cnt, matches})
+	if got {
+		re, err := gide.FindRegexp(&ge.Prefs.Find)
+		switch {
+		case err != nil:
+			log.Println(err)
+		case ge.Prefs.Find.Structural:
+			sp, err := gide.ParseStructPattern(find)
+			if err != nil {
+				log.Println(err)
+				break
 			}
+			cnt, matches := gide.SearchBufStruct(atv.Buf, sp)
+			res = append(res, gide.FileSearchResults{Node: ond, FPath: string(ond.FPath), Count: cnt, Matches: matches})
+		case ge.Prefs.Find.MultiLine:
+			cnt, matches := gide.SearchBufMultiLine(atv.Buf, re)
+			res = append(res, gide.FileSearchResults{Node: ond, FPath: string(ond.FPath), Count: cnt, Matches: matches})
+		case re != nil:
+			cnt, matches := atv.Buf.SearchRegexp(re)
+			res = append(res, gide.FileSearchResults{Node: ond, FPath: string(ond.FPath), Count: cnt, Matches: matches})
+		default:
+			cnt, matches := atv.Buf.Search([]byte(find), ignoreCase, false)
+			res = append(res, gide.FileSearchResults{Node: ond, FPath: string(ond.FPath), Count: cnt, Matches: matches})
 		}
-	} else {
-		res = gide.FileTreeSearch(root, find, ignoreCase, regExp, loc, adir, langs)
 	}
 	fv.ShowResults(res)
 	ge.FocusOnPanel(TabsIdx)
 }
 
+// OpenIndex opens the trigram index of file contents for the project, used
+// by Find when the Index option is set, and connects it to file tree
+// changes so that it is kept up-to-date
+func (ge *GideView) OpenIndex() *gide.TrigramIndex {
+	root := string(ge.Files.FPath)
+	if ge.Index != nil && ge.Index.Root == filepath.Clean(root) {
+		return ge.Index
+	}
+	ge.Index = gide.OpenTrigramIndex(root, gide.TrigramIndexFileName(root, string(ge.ProjFilename)))
+	ge.Files.NodeSignal().Connect(ge.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		gee, _ := recv.Embed(KiT_GideView).(*GideView)
+		if sig == int64(ki.NodeSignalUpdated) && gee.Index != nil {
+			gee.Index.FilesChanged()
+		}
+	})
+	return ge.Index
+}
+
 // Spell checks spelling in active text view
 func (ge *GideView) Spell() {
 	tv := ge.ActiveTextView()
@@ -1910,13 +1961,18 @@ func (ge *GideView) Symbols() {
 	ge.FocusOnPanel(TabsIdx)
 }
 
-// Debug starts the debugger on the RunExec executable.
+// Debug starts the debugger using the current launch configuration
+// (CurLaunch) if set, and otherwise on the RunExec executable.
 func (ge *GideView) Debug() {
+	if lc, _ := ge.Prefs.Launches.ByName(ge.Prefs.CurLaunch); lc != nil {
+		ge.DebugLaunch(lc.Name)
+		return
+	}
 	ge.Prefs.Debug.Mode = gidebug.Exec
 	exePath := string(ge.Prefs.RunExec)
 	exe := filepath.Base(exePath)
 	dv := ge.RecycleTab("Debug "+exe, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
-	dv.Config(ge, ge.Prefs.MainLang, exePath)
+	dv.Config(ge, ge.Prefs.MainLang, exePath, "")
 	ge.FocusOnPanel(TabsIdx)
 	ge.CurDbg = dv
 }
@@ -1931,7 +1987,7 @@ func (ge *GideView) DebugTest() {
 	tstPath := string(tv.Buf.Filename)
 	dir := filepath.Base(filepath.Dir(tstPath))
 	dv := ge.RecycleTab("Debug "+dir, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
-	dv.Config(ge, ge.Prefs.MainLang, tstPath)
+	dv.Config(ge, ge.Prefs.MainLang, tstPath, "")
 	ge.FocusOnPanel(TabsIdx)
 	ge.CurDbg = dv
 }
@@ -1944,7 +2000,62 @@ func (ge *GideView) DebugAttach(pid uint64) {
 	exePath := string(ge.Prefs.RunExec)
 	exe := filepath.Base(exePath)
 	dv := ge.RecycleTab("Debug "+exe, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
-	dv.Config(ge, ge.Prefs.MainLang, exePath)
+	dv.Config(ge, ge.Prefs.MainLang, exePath, "")
+	ge.FocusOnPanel(TabsIdx)
+	ge.CurDbg = dv
+}
+
+// DebugRemote runs the debugger by connecting to an already-running headless
+// delve server at given host:port address, as started with:
+// dlv exec --headless --listen host:port -- use a Remote launch configuration
+// to also map the source paths on the remote machine to local ones.
+func (ge *GideView) DebugRemote(addr string) {
+	ge.Prefs.Debug.Mode = gidebug.Remote
+	ge.Prefs.Debug.Addr = addr
+	exePath := string(ge.Prefs.RunExec)
+	dv := ge.RecycleTab("Debug "+addr, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
+	dv.Config(ge, ge.Prefs.MainLang, exePath, "")
+	ge.FocusOnPanel(TabsIdx)
+	ge.CurDbg = dv
+}
+
+// DebugCore runs the debugger on a core dump file, for post-mortem examination
+// of the state of a crashed process: exePath is the executable that produced it.
+func (ge *GideView) DebugCore(exePath, corePath gi.FileName) {
+	ge.Prefs.Debug.Mode = gidebug.Core
+	ge.Prefs.Debug.CoreFile = string(corePath)
+	exe := filepath.Base(string(exePath))
+	dv := ge.RecycleTab("Debug core "+exe, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
+	dv.Config(ge, ge.Prefs.MainLang, string(exePath), "")
+	ge.FocusOnPanel(TabsIdx)
+	ge.CurDbg = dv
+}
+
+// DebugDump browses a saved dump of the state of a process, read-only, as in
+// a debugging session: a Go panic or SIGQUIT goroutine dump, or a dump
+// exported from the debugger.  Use a Dump launch configuration to also map
+// the source paths in the dump to local ones.
+func (ge *GideView) DebugDump(dumpPath gi.FileName) {
+	ge.Prefs.Debug.Mode = gidebug.Dump
+	ge.Prefs.Debug.CoreFile = string(dumpPath)
+	exePath := string(ge.Prefs.RunExec)
+	dv := ge.RecycleTab("Debug dump "+filepath.Base(string(dumpPath)), gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
+	dv.Config(ge, ge.Prefs.MainLang, exePath, "")
+	ge.FocusOnPanel(TabsIdx)
+	ge.CurDbg = dv
+}
+
+// DebugLaunch runs the debugger using the launch configuration of given name,
+// from the project Launches, which becomes the current one used by Debug.
+func (ge *GideView) DebugLaunch(name string) {
+	lc, _ := ge.Prefs.Launches.ByName(name)
+	if lc == nil {
+		gi.PromptDialog(ge.Viewport, gi.DlgOpts{Title: "No Such Launch Configuration", Prompt: fmt.Sprintf("Launch configuration: %v was not found in the project Launches -- add it in File / Project Prefs", name)}, gi.AddOk, gi.NoCancel, nil, nil)
+		return
+	}
+	ge.Prefs.CurLaunch = name
+	dv := ge.RecycleTab("Debug "+name, gide.KiT_DebugView, true).Embed(gide.KiT_DebugView).(*gide.DebugView)
+	dv.Config(ge, ge.Prefs.MainLang, lc.ProgramPath(string(ge.Prefs.ProjRoot)), name)
 	ge.FocusOnPanel(TabsIdx)
 	ge.CurDbg = dv
 }
@@ -2061,6 +2172,223 @@ func (ge *GideView) PasteRect() {
 	tv.PasteRect()
 }
 
+//////////////////////////////////////////////////////////////////////////////////////
+//    Multiple Cursors
+
+// AddCursorAbove adds a cursor on the line above in active text view
+func (ge *GideView) AddCursorAbove() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.AddCursorAbove()
+}
+
+// AddCursorBelow adds a cursor on the line below in active text view
+func (ge *GideView) AddCursorBelow() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.AddCursorBelow()
+}
+
+// AddNextMatch adds a cursor at the next occurrence of the selection in
+// active text view, selecting the word at the cursor if nothing is selected
+func (ge *GideView) AddNextMatch() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.AddNextMatch()
+}
+
+// SplitSelectionLines splits the selection in active text view into a cursor
+// per line
+func (ge *GideView) SplitSelectionLines() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.SplitSelectionLines()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//    Snippets
+
+// InsertSnippet inserts the snippet whose prefix is the word before the
+// cursor in active text view, or otherwise offers a menu of the snippets for
+// its language
+func (ge *GideView) InsertSnippet() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.OfferSnippets(gide.LangSnippets(tv.Buf.Info.Sup, &ge.Prefs))
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//    Folding
+
+// ToggleFold folds or unfolds the region at the cursor in active text view
+func (ge *GideView) ToggleFold() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.ToggleFold(tv.CursorPos.Ln)
+}
+
+// FoldAll folds all of the regions in active text view
+func (ge *GideView) FoldAll() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.FoldAll()
+}
+
+// UnfoldAll unfolds all of the folded regions in active text view
+func (ge *GideView) UnfoldAll() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil {
+		return
+	}
+	tv.UnfoldAll()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//    Navigation history and bookmarks
+
+// RecordNavPos records the cursor position in active text view in the
+// navigation history, before jumping somewhere else
+func (ge *GideView) RecordNavPos() {
+	tv := ge.ActiveTextView()
+	if tv == nil || tv.Buf == nil || tv.Buf.Filename == "" {
+		return
+	}
+	ge.Prefs.NavHist.Push(gide.NavPos{File: gide.ProjRelPath(&ge.Prefs, string(tv.Buf.Filename)), Pos: tv.CursorPos})
+}
+
+// ShowNavPos shows given position from the navigation history, without
+// recording the current one
+func (ge *GideView) ShowNavPos(np gide.NavPos) bool {
+	tv, _, ok := ge.ViewFile(gi.FileName(gide.ProjAbsPath(&ge.Prefs, np.File)))
+	if !ok || tv.Buf == nil {
+		return false
+	}
+	tv.SetCursorShow(tv.Buf.ValidPos(np.Pos))
+	return true
+}
+
+// NavBack goes back to the previous position in the navigation history of
+// positions jumped from
+func (ge *GideView) NavBack() {
+	var cur gide.NavPos
+	if tv := ge.ActiveTextView(); tv != nil && tv.Buf != nil {
+		cur = gide.NavPos{File: gide.ProjRelPath(&ge.Prefs, string(tv.Buf.Filename)), Pos: tv.CursorPos}
+	}
+	if np, ok := ge.Prefs.NavHist.Back(cur); ok {
+		ge.ShowNavPos(np)
+	}
+}
+
+// NavForward goes forward to the next position in the navigation history,
+// after going back
+func (ge *GideView) NavForward() {
+	if np, ok := ge.Prefs.NavHist.Forward(); ok {
+		ge.ShowNavPos(np)
+	}
+}
+
+// ToggleBookmark adds a numbered bookmark at the cursor line in active text
+// view, or removes the bookmark there if there is one
+func (ge *GideView) ToggleBookmark() {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil || tv.Buf.Filename == "" {
+		return
+	}
+	fn := gide.ProjRelPath(&ge.Prefs, string(tv.Buf.Filename))
+	if idx := ge.Prefs.Bookmarks.Find(fn, tv.CursorPos.Ln+1); idx >= 0 {
+		ge.Prefs.Bookmarks.Delete(idx)
+	} else {
+		ge.Prefs.Bookmarks.Add(fn, tv.CursorPos.Ln+1, "")
+	}
+	ge.UpdateBookmarks()
+}
+
+// AddNamedBookmark adds a bookmark with given name at the cursor line in
+// active text view, or names the bookmark already there
+func (ge *GideView) AddNamedBookmark(name string) {
+	tv := ge.ActiveTextView()
+	if tv.Buf == nil || tv.Buf.Filename == "" {
+		return
+	}
+	fn := gide.ProjRelPath(&ge.Prefs, string(tv.Buf.Filename))
+	if idx := ge.Prefs.Bookmarks.Find(fn, tv.CursorPos.Ln+1); idx >= 0 {
+		ge.Prefs.Bookmarks[idx].Name = name
+	} else {
+		ge.Prefs.Bookmarks.Add(fn, tv.CursorPos.Ln+1, name)
+	}
+	ge.UpdateBookmarks()
+}
+
+// NextBookmark goes to the bookmark after the cursor in active text view,
+// in order of file and line
+func (ge *GideView) NextBookmark() {
+	ge.GoToBookmark(1)
+}
+
+// PrevBookmark goes to the bookmark before the cursor in active text view,
+// in order of file and line
+func (ge *GideView) PrevBookmark() {
+	ge.GoToBookmark(-1)
+}
+
+// GoToBookmark goes to the bookmark after (dir > 0) or before the cursor in
+// active text view
+func (ge *GideView) GoToBookmark(dir int) {
+	fn := ""
+	ln := 0
+	if tv := ge.ActiveTextView(); tv != nil && tv.Buf != nil {
+		fn = gide.ProjRelPath(&ge.Prefs, string(tv.Buf.Filename))
+		ln = tv.CursorPos.Ln + 1
+	}
+	idx := ge.Prefs.Bookmarks.Next(fn, ln, dir)
+	if idx < 0 {
+		ge.SetStatus("no bookmarks")
+		return
+	}
+	bm := ge.Prefs.Bookmarks[idx]
+	ge.RecordNavPos()
+	if tv, _, ok := ge.ViewFile(gi.FileName(gide.ProjAbsPath(&ge.Prefs, bm.File))); ok {
+		tv.SetCursorShow(lex.Pos{Ln: bm.Line - 1})
+	}
+}
+
+// ShowBookmarks shows the bookmarks of the project in the Bookmarks tab
+func (ge *GideView) ShowBookmarks() {
+	bv := ge.RecycleTab("Bookmarks", gide.KiT_BookmarksView, true).Embed(gide.KiT_BookmarksView).(*gide.BookmarksView)
+	bv.Config(ge)
+	ge.FocusOnPanel(TabsIdx)
+}
+
+// UpdateBookmarks updates the bookmarks shown in the text views and the
+// Bookmarks tab, after they have changed
+func (ge *GideView) UpdateBookmarks() {
+	for i := 0; i < NTextViews; i++ {
+		tv := ge.TextViewByIndex(i)
+		if tv.Buf != nil {
+			tv.SetFullReRender()
+			tv.UpdateSig()
+		}
+	}
+	if bvi := ge.TabByName("Bookmarks"); bvi != nil {
+		bv := bvi.Embed(gide.KiT_BookmarksView).(*gide.BookmarksView)
+		bv.Config(ge)
+	}
+}
+
 // RegisterCopy saves current selection in active text view to register of given name
 // returns true if saved
 func (ge *GideView) RegisterCopy(name string) bool {
@@ -2823,6 +3151,48 @@ func (ge *GideView) GideViewKeys(kt *key.ChordEvent) {
 	case gide.KeyFunRectPaste:
 		kt.SetProcessed()
 		ge.PasteRect()
+	case gide.KeyFunCursorAbove:
+		kt.SetProcessed()
+		ge.AddCursorAbove()
+	case gide.KeyFunCursorBelow:
+		kt.SetProcessed()
+		ge.AddCursorBelow()
+	case gide.KeyFunCursorNextMatch:
+		kt.SetProcessed()
+		ge.AddNextMatch()
+	case gide.KeyFunCursorSplitLines:
+		kt.SetProcessed()
+		ge.SplitSelectionLines()
+	case gide.KeyFunSnippet:
+		kt.SetProcessed()
+		ge.InsertSnippet()
+	case gide.KeyFunFoldToggle:
+		kt.SetProcessed()
+		ge.ToggleFold()
+	case gide.KeyFunFoldAll:
+		kt.SetProcessed()
+		ge.FoldAll()
+	case gide.KeyFunUnfoldAll:
+		kt.SetProcessed()
+		ge.UnfoldAll()
+	case gide.KeyFunNavBack:
+		kt.SetProcessed()
+		ge.NavBack()
+	case gide.KeyFunNavForward:
+		kt.SetProcessed()
+		ge.NavForward()
+	case gide.KeyFunBookmarkToggle:
+		kt.SetProcessed()
+		ge.ToggleBookmark()
+	case gide.KeyFunBookmarkNamed:
+		kt.SetProcessed()
+		giv.CallMethod(ge, "AddNamedBookmark", ge.Viewport)
+	case gide.KeyFunBookmarkNext:
+		kt.SetProcessed()
+		ge.NextBookmark()
+	case gide.KeyFunBookmarkPrev:
+		kt.SetProcessed()
+		ge.PrevBookmark()
 	case gide.KeyFunRegCopy:
 		kt.SetProcessed()
 		giv.CallMethod(ge, "RegisterCopy", ge.Viewport)
@@ -3298,6 +3668,43 @@ var GideViewProps = ki.Props{
 					},
 				}},
 			}},
+			{"Cursors", ki.PropSlice{
+				{"AddCursorAbove", ki.Props{
+					"label": "Add Cursor Above",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunCursorAbove).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"AddCursorBelow", ki.Props{
+					"label": "Add Cursor Below",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunCursorBelow).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"AddNextMatch", ki.Props{
+					"label": "Add Next Match",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunCursorNextMatch).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"SplitSelectionLines", ki.Props{
+					"label": "Split Into Lines",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunCursorSplitLines).String())
+					}),
+					"updtfunc": GideViewInactiveTextSelectionFunc,
+				}},
+			}},
+			{"InsertSnippet", ki.Props{
+				"label": "Insert Snippet",
+				"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+					return key.Chord(gide.ChordForFun(gide.KeyFunSnippet).String())
+				}),
+				"updtfunc": GideViewInactiveEmptyFunc,
+			}},
 			{"sep-undo", ki.BlankProp{}},
 			{"Undo", ki.Props{
 				"keyfun": gi.KeyFunUndo,
@@ -3418,6 +3825,29 @@ var GideViewProps = ki.Props{
 					"updtfunc": GideViewInactiveEmptyFunc,
 				}},
 			}},
+			{"Folding", ki.PropSlice{
+				{"ToggleFold", ki.Props{
+					"label": "Toggle Fold",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunFoldToggle).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"FoldAll", ki.Props{
+					"label": "Fold All",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunFoldAll).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"UnfoldAll", ki.Props{
+					"label": "Unfold All",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunUnfoldAll).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+			}},
 			{"Splits", ki.PropSlice{
 				{"SplitsSetView", ki.Props{
 					"label":    "Set View",
@@ -3469,6 +3899,65 @@ var GideViewProps = ki.Props{
 					"keyfun": gi.KeyFunJump,
 				}},
 			}},
+			{"History", ki.PropSlice{
+				{"NavBack", ki.Props{
+					"label": "Back",
+					"desc":  "go back to the position before the last jump to a link, symbol, found item or debugger frame",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunNavBack).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"NavForward", ki.Props{
+					"label": "Forward",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunNavForward).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+			}},
+			{"Bookmarks", ki.PropSlice{
+				{"ToggleBookmark", ki.Props{
+					"label": "Toggle Bookmark",
+					"desc":  "add a numbered bookmark at the cursor line, or remove the one there",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunBookmarkToggle).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"AddNamedBookmark", ki.Props{
+					"label": "Named Bookmark...",
+					"desc":  "add a bookmark with a name at the cursor line, or name the one there",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunBookmarkNamed).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+					"Args": ki.PropSlice{
+						{"Bookmark Name", ki.Props{
+							"default": "",
+						}},
+					},
+				}},
+				{"NextBookmark", ki.Props{
+					"label": "Next Bookmark",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunBookmarkNext).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"PrevBookmark", ki.Props{
+					"label": "Prev Bookmark",
+					"shortcut-func": giv.ShortcutFunc(func(gei interface{}, act *gi.Action) key.Chord {
+						return key.Chord(gide.ChordForFun(gide.KeyFunBookmarkPrev).String())
+					}),
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+				{"ShowBookmarks", ki.Props{
+					"label":    "Show Bookmarks",
+					"desc":     "list the bookmarks of the project in the Bookmarks tab",
+					"updtfunc": GideViewInactiveEmptyFunc,
+				}},
+			}},
 		}},
 		{"Command", ki.PropSlice{
 			{"Build", ki.Props{
@@ -3491,6 +3980,37 @@ var GideViewProps = ki.Props{
 					{"Process PID", ki.Props{}},
 				},
 			}},
+			{"DebugRemote", ki.Props{
+				"desc": "connect to an already running headless delve server, e.g., debugging a service in a container: enter its host:port address, as given to dlv --headless --listen -- use a Remote launch configuration to also map remote source paths to local ones",
+				"Args": ki.PropSlice{
+					{"Address", ki.Props{
+						"default": "127.0.0.1:2345",
+					}},
+				},
+			}},
+			{"DebugLaunch", ki.Props{
+				"desc": "run the debugger using a named launch configuration from the project Launches (edit in File / Project Prefs) -- it becomes the one used by Debug",
+				"Args": ki.PropSlice{
+					{"Launch Name", ki.Props{
+						"default-field": "Prefs.CurLaunch",
+					}},
+				},
+			}},
+			{"DebugCore", ki.Props{
+				"desc": "examine a core dump of a crashed process, post-mortem: choose the executable that produced it, and the core file",
+				"Args": ki.PropSlice{
+					{"Exe Path", ki.Props{
+						"default-field": "Prefs.RunExec",
+					}},
+					{"Core Path", ki.Props{}},
+				},
+			}},
+			{"DebugDump", ki.Props{
+				"desc": "browse a saved dump of the state of a process, read-only: a Go panic or SIGQUIT goroutine dump (e.g., from a production crash log), or a dump exported from the debugger -- stack frames link to the source",
+				"Args": ki.PropSlice{
+					{"Dump Path", ki.Props{}},
+				},
+			}},
 			{"ChooseRunExec", ki.Props{
 				"desc": "choose the executable to run for this project using the Run button",
 				"Args": ki.PropSlice{
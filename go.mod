@@ -1,17 +1,15 @@
 module github.com/goki/gide
 
 require (
-	github.com/go-delve/delve v1.5.1
+	github.com/go-delve/delve v1.7.3
 	github.com/goki/gi v1.2.8
 	github.com/goki/ki v1.1.3
 	github.com/goki/mat32 v1.0.9
 	github.com/goki/pi v1.0.14
 	github.com/goki/vci v1.0.0
-	github.com/kr/pretty v0.1.0 // indirect
 	github.com/sirupsen/logrus v1.7.0 // indirect
 	golang.org/x/arch v0.0.0-20201008161808-52c3e6f60cff // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
-	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
 
 go 1.13